@@ -0,0 +1,255 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+// Package cache implements a persistent cache on disk, which keeps data
+// such as provider schemas and registry module metadata across sessions.
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const tempFilePrefix = ".tmp-"
+
+// Cache stores entries as files under a directory. Entries are addressed
+// by keys, which are slash-separated paths relative to the directory,
+// e.g. "provider-schemas/registry.opentofu.org/hashicorp/aws/5.0.0.json".
+//
+// Once the total size of entries exceeds the size limit, the least
+// recently used entries are evicted.
+type Cache struct {
+	dir     string
+	maxSize int64
+
+	mu sync.Mutex
+}
+
+// New returns a cache stored in dir. A maxSize of zero or less
+// means that the size of the cache isn't limited.
+func New(dir string, maxSize int64) *Cache {
+	return &Cache{
+		dir:     dir,
+		maxSize: maxSize,
+	}
+}
+
+// DefaultDir returns the directory of the cache shared by all sessions
+// of the current user.
+func DefaultDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "tofu-ls"), nil
+}
+
+func (c *Cache) Dir() string {
+	return c.dir
+}
+
+// Read returns the content of the entry under the given key.
+// The error wraps [fs.ErrNotExist] if there is no such entry.
+func (c *Cache) Read(key string) ([]byte, error) {
+	path, err := c.path(key)
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(path)
+}
+
+// Write stores the entry under the given key, replacing any existing
+// entry, and evicts other entries, if the size limit was exceeded.
+func (c *Cache) Write(key string, b []byte) error {
+	path, err := c.path(key)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	err = os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		return err
+	}
+
+	// write into a temporary file first, so that other sessions
+	// reading the same entry never see it partially written
+	f, err := os.CreateTemp(filepath.Dir(path), tempFilePrefix+"*")
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(b)
+	if cErr := f.Close(); err == nil {
+		err = cErr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	err = os.Rename(f.Name(), path)
+	if err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+
+	return c.evict(path)
+}
+
+// Touch marks the entry under the given key as recently used,
+// which makes it the last candidate for eviction.
+func (c *Cache) Touch(key string) error {
+	path, err := c.path(key)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	return os.Chtimes(path, now, now)
+}
+
+// Keys returns the keys of all entries below the given prefix,
+// such as "provider-schemas"
+func (c *Cache) Keys(prefix string) ([]string, error) {
+	root, err := c.path(prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0)
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() || strings.HasPrefix(d.Name(), tempFilePrefix) {
+			return nil
+		}
+		rel, err := filepath.Rel(c.dir, path)
+		if err != nil {
+			return err
+		}
+		keys = append(keys, filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// Size returns the total size of all entries in bytes
+func (c *Cache) Size() (int64, error) {
+	files, err := c.files()
+	if err != nil {
+		return 0, err
+	}
+
+	var size int64
+	for _, f := range files {
+		size += f.size
+	}
+	return size, nil
+}
+
+// Clean removes all entries
+func (c *Cache) Clean() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return os.RemoveAll(c.dir)
+}
+
+func (c *Cache) path(key string) (string, error) {
+	if !fs.ValidPath(key) {
+		return "", fmt.Errorf("invalid cache key: %q", key)
+	}
+	return filepath.Join(c.dir, filepath.FromSlash(key)), nil
+}
+
+type cacheFile struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+func (c *Cache) files() ([]cacheFile, error) {
+	files := make([]cacheFile, 0)
+	err := filepath.WalkDir(c.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() || strings.HasPrefix(d.Name(), tempFilePrefix) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				// removed by another session in the meantime
+				return nil
+			}
+			return err
+		}
+		files = append(files, cacheFile{
+			path:    path,
+			size:    info.Size(),
+			modTime: info.ModTime(),
+		})
+		return nil
+	})
+	return files, err
+}
+
+// evict removes the least recently used entries, until the cache
+// fits into its size limit. The entry at keepPath is never removed.
+func (c *Cache) evict(keepPath string) error {
+	if c.maxSize <= 0 {
+		return nil
+	}
+
+	files, err := c.files()
+	if err != nil {
+		return err
+	}
+
+	var size int64
+	for _, f := range files {
+		size += f.size
+	}
+	if size <= c.maxSize {
+		return nil
+	}
+
+	sort.SliceStable(files, func(i, j int) bool {
+		return files[i].modTime.Before(files[j].modTime)
+	})
+
+	for _, f := range files {
+		if size <= c.maxSize {
+			break
+		}
+		if f.path == keepPath {
+			continue
+		}
+		err := os.Remove(f.path)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		size -= f.size
+	}
+
+	return nil
+}
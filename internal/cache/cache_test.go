@@ -0,0 +1,154 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package cache
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCache_readWrite(t *testing.T) {
+	c := New(t.TempDir(), 0)
+
+	_, err := c.Read("provider-schemas/example.com/foo/bar/1.0.0.json")
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("expected not exist error, given: %#v", err)
+	}
+
+	err = c.Write("provider-schemas/example.com/foo/bar/1.0.0.json", []byte("first"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = c.Write("provider-schemas/example.com/foo/bar/1.0.0.json", []byte("second"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = c.Write("registry-modules/example.com/foo/bar/aws/1.0.0.json", []byte("module"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := c.Read("provider-schemas/example.com/foo/bar/1.0.0.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff("second", string(b)); diff != "" {
+		t.Fatalf("unexpected content: %s", diff)
+	}
+
+	keys, err := c.Keys("provider-schemas")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedKeys := []string{"provider-schemas/example.com/foo/bar/1.0.0.json"}
+	if diff := cmp.Diff(expectedKeys, keys); diff != "" {
+		t.Fatalf("unexpected keys: %s", diff)
+	}
+
+	size, err := c.Size()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size != int64(len("second")+len("module")) {
+		t.Fatalf("unexpected size: %d", size)
+	}
+}
+
+func TestCache_invalidKey(t *testing.T) {
+	c := New(t.TempDir(), 0)
+
+	err := c.Write("../outside.json", []byte("{}"))
+	if err == nil {
+		t.Fatal("expected error for key outside of the cache")
+	}
+}
+
+func TestCache_keysEmpty(t *testing.T) {
+	c := New(filepath.Join(t.TempDir(), "missing"), 0)
+
+	keys, err := c.Keys("provider-schemas")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 0 {
+		t.Fatalf("expected no keys, given: %q", keys)
+	}
+}
+
+func TestCache_evict(t *testing.T) {
+	dir := t.TempDir()
+	c := New(dir, 10)
+
+	for _, key := range []string{"a.json", "b.json", "c.json"} {
+		err := c.Write(key, []byte("1234"))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// entries are evicted once the limit is exceeded,
+	// so the cache never holds more than 2 entries
+	keys, err := c.Keys(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 2 {
+		t.Fatalf("expected 2 entries, given: %q", keys)
+	}
+
+	// mark a.json, b.json as used in the past, c.json as recently used
+	past := time.Now().Add(-time.Hour)
+	for _, key := range keys {
+		err := os.Chtimes(filepath.Join(dir, key), past, past)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	err = c.Touch("c.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = c.Write("d.json", []byte("1234"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys, err = c.Keys(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedKeys := []string{"c.json", "d.json"}
+	if diff := cmp.Diff(expectedKeys, keys); diff != "" {
+		t.Fatalf("unexpected keys after eviction: %s", diff)
+	}
+}
+
+func TestCache_clean(t *testing.T) {
+	c := New(filepath.Join(t.TempDir(), "tofu-ls"), 0)
+
+	err := c.Write("provider-schemas/example.com/foo/bar/1.0.0.json", []byte("{}"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = c.Clean()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	size, err := c.Size()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size != 0 {
+		t.Fatalf("expected empty cache, given size %d", size)
+	}
+}
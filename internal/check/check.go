@@ -0,0 +1,419 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package check
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/hcl/v2"
+	lsctx "github.com/opentofu/tofu-ls/internal/context"
+	"github.com/opentofu/tofu-ls/internal/document"
+	"github.com/opentofu/tofu-ls/internal/eventbus"
+	fmodules "github.com/opentofu/tofu-ls/internal/features/modules"
+	modast "github.com/opentofu/tofu-ls/internal/features/modules/ast"
+	frootmodules "github.com/opentofu/tofu-ls/internal/features/rootmodules"
+	ftests "github.com/opentofu/tofu-ls/internal/features/tests"
+	testast "github.com/opentofu/tofu-ls/internal/features/tests/ast"
+	fvariables "github.com/opentofu/tofu-ls/internal/features/variables"
+	varast "github.com/opentofu/tofu-ls/internal/features/variables/ast"
+	"github.com/opentofu/tofu-ls/internal/filesystem"
+	"github.com/opentofu/tofu-ls/internal/job"
+	"github.com/opentofu/tofu-ls/internal/langserver/diagnostics"
+	ilsp "github.com/opentofu/tofu-ls/internal/lsp"
+	"github.com/opentofu/tofu-ls/internal/registry"
+	"github.com/opentofu/tofu-ls/internal/scheduler"
+	"github.com/opentofu/tofu-ls/internal/settings"
+	"github.com/opentofu/tofu-ls/internal/state"
+	"github.com/opentofu/tofu-ls/internal/tofu/ast"
+	"github.com/opentofu/tofu-ls/internal/tofu/discovery"
+	"github.com/opentofu/tofu-ls/internal/tofu/exec"
+	"github.com/opentofu/tofu-ls/internal/walker"
+)
+
+var discardLogs = log.New(io.Discard, "", 0)
+
+// Checker indexes all modules, variable definitions files and tests
+// within a directory the same way the language server does for open
+// documents and collects diagnostics from parsing and validation.
+type Checker struct {
+	logger *log.Logger
+
+	tfDiscoFunc       discovery.DiscoveryFunc
+	tfExecFactory     exec.ExecutorFactory
+	registryClient    registry.Client
+	validationOptions settings.ValidationOptions
+}
+
+func NewChecker() *Checker {
+	d := &discovery.Discovery{}
+
+	return &Checker{
+		logger:         discardLogs,
+		tfDiscoFunc:    d.LookPath,
+		tfExecFactory:  exec.NewExecutor,
+		registryClient: registry.NewClient(),
+		validationOptions: settings.ValidationOptions{
+			EnableEnhancedValidation: true,
+		},
+	}
+}
+
+func (c *Checker) SetLogger(logger *log.Logger) {
+	c.logger = logger
+}
+
+// SetEnhancedValidation toggles the schema and reference validation,
+// which is enabled by default, as in the language server settings.
+func (c *Checker) SetEnhancedValidation(enabled bool) {
+	c.validationOptions.EnableEnhancedValidation = enabled
+}
+
+// Check walks the directory at rootPath, runs all jobs for discovered
+// directories to completion and returns diagnostics for all files.
+//
+// Modules installed in the .terraform directory are indexed where
+// needed, but not checked.
+func (c *Checker) Check(ctx context.Context, rootPath string) (Diagnostics, error) {
+	ctx, cancelFunc := context.WithCancel(ctx)
+	defer cancelFunc()
+
+	execOpts := &exec.ExecutorOpts{}
+	path, err := c.tfDiscoFunc()
+	if err == nil {
+		execOpts.ExecPath = path
+	}
+	ctx = exec.WithExecutorOpts(ctx, execOpts)
+	ctx = exec.WithExecutorFactory(ctx, c.tfExecFactory)
+	ctx = lsctx.WithValidationOptions(ctx, &c.validationOptions)
+	// Jobs are not triggered by any request
+	ctx = lsctx.WithDocumentContext(ctx, lsctx.Document{})
+
+	stateStore, err := state.NewStateStore()
+	if err != nil {
+		return nil, err
+	}
+	stateStore.SetLogger(c.logger)
+
+	// Jobs for directories without open documents are scheduled
+	// with low priority, but module calls may still be scheduled
+	// with high priority.
+	for _, priority := range []job.JobPriority{job.LowPriority, job.HighPriority} {
+		s := scheduler.NewScheduler(stateStore.JobStore, 1, priority)
+		s.SetLogger(c.logger)
+		s.Start(ctx)
+		defer s.Stop()
+	}
+
+	fs := filesystem.NewFilesystem(stateStore.DocumentStore)
+	fs.SetLogger(c.logger)
+
+	eventBus := eventbus.NewEventBus()
+	eventBus.SetLogger(c.logger)
+
+	features, err := c.startFeatures(ctx, eventBus, stateStore, fs)
+	if err != nil {
+		return nil, err
+	}
+	defer features.stop()
+
+	dirs, err := c.walk(ctx, eventBus, stateStore, fs, rootPath)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, dir := range dirs {
+		eventBus.DidOpen(eventbus.DidOpenEvent{
+			Context:    ctx,
+			Dir:        dir.handle,
+			LanguageID: dir.languageID,
+		})
+	}
+
+	err = waitForJobs(ctx, stateStore.JobStore, dirs)
+	if err != nil {
+		return nil, err
+	}
+
+	diags := make(Diagnostics, 0)
+	for _, dir := range dirs {
+		path := dir.handle.Path()
+
+		dirDiags := diagnostics.NewDiagnostics()
+		dirDiags.Extend(features.modules.Diagnostics(path))
+		dirDiags.Extend(features.variables.Diagnostics(path))
+		dirDiags.Extend(features.tests.Diagnostics(path))
+
+		for filename, sourceDiags := range dirDiags {
+			for source, hclDiags := range sourceDiags {
+				for _, diag := range hclDiags {
+					diags = append(diags, Diagnostic{
+						Path:       filepath.Join(path, filename),
+						Source:     source,
+						Diagnostic: diag,
+					})
+				}
+			}
+		}
+	}
+	diags.sort()
+
+	return diags, nil
+}
+
+type features struct {
+	rootModules *frootmodules.RootModulesFeature
+	modules     *fmodules.ModulesFeature
+	variables   *fvariables.VariablesFeature
+	tests       *ftests.TestsFeature
+}
+
+func (c *Checker) startFeatures(ctx context.Context, eventBus *eventbus.EventBus, stateStore *state.StateStore, fs *filesystem.Filesystem) (*features, error) {
+	rootModulesFeature, err := frootmodules.NewRootModulesFeature(eventBus, stateStore, fs, c.tfExecFactory)
+	if err != nil {
+		return nil, err
+	}
+	rootModulesFeature.SetLogger(c.logger)
+	rootModulesFeature.Start(ctx)
+
+	credentials, err := registry.LoadCredentials()
+	if err != nil {
+		c.logger.Printf("failed to load registry credentials: %s", err)
+	}
+	c.registryClient.Credentials = credentials
+
+	modulesFeature, err := fmodules.NewModulesFeature(eventBus, stateStore, fs, rootModulesFeature, c.registryClient)
+	if err != nil {
+		return nil, err
+	}
+	modulesFeature.SetLogger(c.logger)
+	modulesFeature.Start(ctx)
+
+	variablesFeature, err := fvariables.NewVariablesFeature(eventBus, stateStore, fs, modulesFeature)
+	if err != nil {
+		return nil, err
+	}
+	variablesFeature.SetLogger(c.logger)
+	variablesFeature.Start(ctx)
+
+	testsFeature, err := ftests.NewTestsFeature(eventBus, stateStore, fs, modulesFeature, rootModulesFeature)
+	if err != nil {
+		return nil, err
+	}
+	testsFeature.SetLogger(c.logger)
+	testsFeature.Start(ctx)
+
+	return &features{
+		rootModules: rootModulesFeature,
+		modules:     modulesFeature,
+		variables:   variablesFeature,
+		tests:       testsFeature,
+	}, nil
+}
+
+func (f *features) stop() {
+	f.tests.Stop()
+	f.variables.Stop()
+	f.modules.Stop()
+	f.rootModules.Stop()
+}
+
+type checkedDir struct {
+	handle     document.DirHandle
+	languageID string
+}
+
+// walk walks the directory at rootPath, so that features can discover
+// modules, variable definitions files and tests within it, and returns
+// directories which should be checked, sorted by path.
+func (c *Checker) walk(ctx context.Context, eventBus *eventbus.EventBus, stateStore *state.StateStore, fs *filesystem.Filesystem, rootPath string) ([]checkedDir, error) {
+	dirs := make([]checkedDir, 0)
+	var dirsMu sync.Mutex
+
+	discoverDone := make(chan struct{}, 10)
+	discover := eventBus.OnDiscover("check", discoverDone)
+	go func() {
+		for {
+			select {
+			case discover := <-discover:
+				languageID := languageIDForFiles(discover.Files)
+				if languageID != "" && !isInDataDir(rootPath, discover.Path) {
+					dirsMu.Lock()
+					dirs = append(dirs, checkedDir{
+						handle:     document.DirHandleFromPath(discover.Path),
+						languageID: languageID,
+					})
+					dirsMu.Unlock()
+				}
+				discoverDone <- struct{}{}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	collector := walker.NewWalkerCollector()
+	w := walker.NewWalker(fs, state.NewPathAwaiter(stateStore.WalkerPaths, false), eventBus)
+	w.Collector = collector
+	w.SetLogger(c.logger)
+	err := w.StartWalking(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer w.Stop()
+
+	root := document.DirHandleFromPath(rootPath)
+	err = stateStore.WalkerPaths.EnqueueDir(ctx, root)
+	if err != nil {
+		return nil, err
+	}
+	err = stateStore.WalkerPaths.WaitForDirs(ctx, []document.DirHandle{root})
+	if err != nil {
+		return nil, err
+	}
+	err = collector.ErrorOrNil()
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk %s: %w", rootPath, err)
+	}
+
+	dirsMu.Lock()
+	defer dirsMu.Unlock()
+	sort.Slice(dirs, func(i, j int) bool {
+		return dirs[i].handle.Path() < dirs[j].handle.Path()
+	})
+
+	return dirs, nil
+}
+
+// languageIDForFiles returns the language ID of the given files
+// which all features use to decide whether to index a directory,
+// or an empty string if there are no relevant files.
+func languageIDForFiles(files []string) string {
+	var hasVarsFiles, hasTestFiles bool
+	for _, file := range files {
+		if ast.IsIgnoredFile(file) {
+			continue
+		}
+		switch {
+		case modast.IsModuleFilename(file):
+			return ilsp.OpenTofu.String()
+		case varast.IsVarsFilename(file):
+			hasVarsFiles = true
+		case testast.IsTestFilename(file) || testast.IsMockFilename(file):
+			hasTestFiles = true
+		}
+	}
+
+	if hasVarsFiles {
+		return ilsp.OpenTofuVars.String()
+	}
+	if hasTestFiles {
+		return ilsp.OpenTofuTest.String()
+	}
+	return ""
+}
+
+// isInDataDir returns true if the path is within a .terraform
+// directory, such as installed modules, which users don't maintain.
+func isInDataDir(rootPath, path string) bool {
+	relPath, err := filepath.Rel(rootPath, path)
+	if err != nil {
+		return false
+	}
+	for _, name := range strings.Split(relPath, string(filepath.Separator)) {
+		if name == ".terraform" {
+			return true
+		}
+	}
+	return false
+}
+
+// waitForJobs waits until there are no incomplete jobs for the given
+// directories, including jobs scheduled while waiting, such as validation
+// which gets scheduled once the module is decoded.
+func waitForJobs(ctx context.Context, jobStore *state.JobStore, dirs []checkedDir) error {
+	for {
+		ids := make(job.IDs, 0)
+		for _, dir := range dirs {
+			dirIds, err := jobStore.ListIncompleteJobsForDir(dir.handle)
+			if err != nil {
+				return err
+			}
+			ids = append(ids, dirIds...)
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		err := jobStore.WaitForJobs(ctx, ids...)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// Diagnostic represents a diagnostic within a file
+type Diagnostic struct {
+	// Path is the absolute path of the file
+	Path   string
+	Source ast.DiagnosticSource
+
+	*hcl.Diagnostic
+}
+
+type Diagnostics []Diagnostic
+
+// HasErrors returns true if any of the diagnostics is an error
+func (d Diagnostics) HasErrors() bool {
+	for _, diag := range d {
+		if diag.Severity == hcl.DiagError {
+			return true
+		}
+	}
+	return false
+}
+
+// Count returns the number of errors and warnings
+func (d Diagnostics) Count() (errCount, warnCount int) {
+	for _, diag := range d {
+		switch diag.Severity {
+		case hcl.DiagError:
+			errCount++
+		case hcl.DiagWarning:
+			warnCount++
+		}
+	}
+	return errCount, warnCount
+}
+
+func (d Diagnostics) sort() {
+	sort.SliceStable(d, func(i, j int) bool {
+		if d[i].Path != d[j].Path {
+			return d[i].Path < d[j].Path
+		}
+		iStart, jStart := d[i].start(), d[j].start()
+		if iStart.Line != jStart.Line {
+			return iStart.Line < jStart.Line
+		}
+		if iStart.Column != jStart.Column {
+			return iStart.Column < jStart.Column
+		}
+		return d[i].Summary < d[j].Summary
+	})
+}
+
+// start returns the position at which the diagnostic starts,
+// which is the start of the file for diagnostics without subject
+func (d Diagnostic) start() hcl.Pos {
+	if d.Subject == nil {
+		return hcl.InitialPos
+	}
+	return d.Subject.Start
+}
@@ -0,0 +1,226 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package check
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/opentofu/tofu-ls/internal/tofu/ast"
+)
+
+func TestChecker_Check(t *testing.T) {
+	rootPath := t.TempDir()
+	files := map[string]string{
+		"main.tf": `variable "region" {
+  type = string
+}
+
+output "region" {
+  value = var.regoin
+}
+
+module "vpc" {
+  source = "./modules/vpc"
+}
+`,
+		"terraform.tfvars": `region = "eu-west-1"
+zone   = "a"
+`,
+		"modules/vpc/main.tf": `resource "aws_vpc" {
+`,
+		// Installed modules are not checked
+		".terraform/modules/vpc/main.tf": `resource {
+`,
+	}
+	for name, content := range files {
+		path := filepath.Join(rootPath, filepath.FromSlash(name))
+		err := os.MkdirAll(filepath.Dir(path), 0o755)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = os.WriteFile(path, []byte(content), 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c := NewChecker()
+	c.tfDiscoFunc = func() (string, error) {
+		return "", errors.New("not found")
+	}
+	diags, err := c.Check(context.Background(), rootPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := &bytes.Buffer{}
+	err = WriteText(buf, rootPath, diags)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedOutput := `main.tf:1:1: warning: "var.region" is declared but not used
+main.tf:6:11: error: No declaration found for "var.regoin"
+modules/vpc/main.tf:1:1: error: Not enough labels specified for "resource": All "resource" blocks must have 2 label(s)
+modules/vpc/main.tf:1:20: error: Unclosed configuration block: There is no closing brace for this block before the end of the file. This may be caused by incorrect brace nesting elsewhere in this file.
+terraform.tfvars:2:1: error: Unexpected attribute: An attribute named "zone" is not expected here
+`
+	if diff := cmp.Diff(filepath.FromSlash(expectedOutput), buf.String()); diff != "" {
+		t.Fatalf("unexpected output: %s", diff)
+	}
+	if !diags.HasErrors() {
+		t.Fatal("expected errors")
+	}
+}
+
+func TestChecker_Check_enhancedValidationDisabled(t *testing.T) {
+	rootPath := t.TempDir()
+	err := os.WriteFile(filepath.Join(rootPath, "main.tf"), []byte(`output "region" {
+  value = var.region
+}
+`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := NewChecker()
+	c.tfDiscoFunc = func() (string, error) {
+		return "", errors.New("not found")
+	}
+	c.SetEnhancedValidation(false)
+	diags, err := c.Check(context.Background(), rootPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diags) != 0 {
+		t.Fatalf("expected no diagnostics, given: %#v", diags)
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	rootPath := t.TempDir()
+	diags := Diagnostics{
+		{
+			Path:   filepath.Join(rootPath, "main.tf"),
+			Source: ast.ReferenceValidationSource,
+			Diagnostic: &hcl.Diagnostic{
+				Severity: hcl.DiagWarning,
+				Summary:  `"var.region" is declared but not used`,
+				Subject: &hcl.Range{
+					Filename: "main.tf",
+					Start:    hcl.Pos{Line: 1, Column: 1, Byte: 0},
+					End:      hcl.Pos{Line: 1, Column: 18, Byte: 17},
+				},
+			},
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	err := WriteJSON(buf, rootPath, diags)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedOutput := `{
+  "valid": true,
+  "error_count": 0,
+  "warning_count": 1,
+  "diagnostics": [
+    {
+      "severity": "warning",
+      "summary": "\"var.region\" is declared but not used",
+      "source": "reference-validation",
+      "filename": "main.tf",
+      "range": {
+        "start": {
+          "line": 1,
+          "column": 1,
+          "byte": 0
+        },
+        "end": {
+          "line": 1,
+          "column": 18,
+          "byte": 17
+        }
+      }
+    }
+  ]
+}
+`
+	if diff := cmp.Diff(expectedOutput, buf.String()); diff != "" {
+		t.Fatalf("unexpected output: %s", diff)
+	}
+}
+
+func TestWriteSARIF(t *testing.T) {
+	rootPath := t.TempDir()
+	diags := Diagnostics{
+		{
+			Path:   filepath.Join(rootPath, "modules", "vpc", "main.tf"),
+			Source: ast.SchemaValidationSource,
+			Diagnostic: &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Unexpected attribute",
+				Detail:   `An attribute named "foo" is not expected here`,
+				Subject: &hcl.Range{
+					Filename: "main.tf",
+					Start:    hcl.Pos{Line: 2, Column: 3, Byte: 20},
+					End:      hcl.Pos{Line: 2, Column: 12, Byte: 29},
+				},
+			},
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	err := WriteSARIF(buf, rootPath, "0.1.0", diags)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var sarif sarifLog
+	err = json.Unmarshal(buf.Bytes(), &sarif)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sarif.Runs) != 1 {
+		t.Fatalf("expected 1 run, given %d", len(sarif.Runs))
+	}
+
+	expectedResults := []sarifResult{
+		{
+			RuleId: "schema-validation",
+			Level:  "error",
+			Message: sarifMessage{
+				Text: "Unexpected attribute\n\nAn attribute named \"foo\" is not expected here",
+			},
+			Locations: []sarifLocation{
+				{
+					PhysicalLocation: sarifPhysicalLocation{
+						ArtifactLocation: sarifArtifactLoc{
+							Uri:       "modules/vpc/main.tf",
+							UriBaseId: srcRootId,
+						},
+						Region: &sarifRegion{
+							StartLine:   2,
+							StartColumn: 3,
+							EndLine:     2,
+							EndColumn:   12,
+						},
+					},
+				},
+			},
+		},
+	}
+	if diff := cmp.Diff(expectedResults, sarif.Runs[0].Results); diff != "" {
+		t.Fatalf("unexpected results: %s", diff)
+	}
+}
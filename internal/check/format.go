@@ -0,0 +1,270 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package check
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/opentofu/tofu-ls/internal/tofu/ast"
+	"github.com/opentofu/tofu-ls/internal/uri"
+)
+
+type rule struct {
+	id          string
+	description string
+}
+
+// rules describe diagnostic sources in machine-readable output
+var rules = map[ast.DiagnosticSource]rule{
+	ast.HCLParsingSource:             {"hcl-parsing", "Configuration must be valid HCL syntax"},
+	ast.SchemaValidationSource:       {"schema-validation", "Configuration must match the language and provider schemas"},
+	ast.ReferenceValidationSource:    {"reference-validation", "References must point to declared objects"},
+	ast.TofuValidateSource:           {"tofu-validate", "Configuration must pass tofu validate"},
+	ast.ProviderLockValidationSource: {"provider-lock", "Locked provider versions must match required_providers"},
+}
+
+// WriteText writes diagnostics one per line, prefixed with
+// the file path relative to rootPath and the position.
+func WriteText(w io.Writer, rootPath string, diags Diagnostics) error {
+	for _, diag := range diags {
+		start := diag.start()
+		msg := diag.Summary
+		if diag.Detail != "" {
+			msg = fmt.Sprintf("%s: %s", msg, strings.Join(strings.Fields(diag.Detail), " "))
+		}
+
+		_, err := fmt.Fprintf(w, "%s:%d:%d: %s: %s\n", relPath(rootPath, diag.Path),
+			start.Line, start.Column, severityString(diag.Severity), msg)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type jsonOutput struct {
+	Valid        bool             `json:"valid"`
+	ErrorCount   int              `json:"error_count"`
+	WarningCount int              `json:"warning_count"`
+	Diagnostics  []jsonDiagnostic `json:"diagnostics"`
+}
+
+type jsonDiagnostic struct {
+	Severity string     `json:"severity"`
+	Summary  string     `json:"summary"`
+	Detail   string     `json:"detail,omitempty"`
+	Source   string     `json:"source"`
+	Filename string     `json:"filename"`
+	Range    *jsonRange `json:"range,omitempty"`
+}
+
+type jsonRange struct {
+	Start jsonPos `json:"start"`
+	End   jsonPos `json:"end"`
+}
+
+type jsonPos struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+	Byte   int `json:"byte"`
+}
+
+// WriteJSON writes diagnostics as a JSON object, resembling
+// the output of tofu validate -json.
+func WriteJSON(w io.Writer, rootPath string, diags Diagnostics) error {
+	errCount, warnCount := diags.Count()
+	output := jsonOutput{
+		Valid:        errCount == 0,
+		ErrorCount:   errCount,
+		WarningCount: warnCount,
+		Diagnostics:  make([]jsonDiagnostic, 0, len(diags)),
+	}
+
+	for _, diag := range diags {
+		jsonDiag := jsonDiagnostic{
+			Severity: severityString(diag.Severity),
+			Summary:  diag.Summary,
+			Detail:   diag.Detail,
+			Source:   rules[diag.Source].id,
+			Filename: relPath(rootPath, diag.Path),
+		}
+		if diag.Subject != nil {
+			jsonDiag.Range = &jsonRange{
+				Start: jsonPos{diag.Subject.Start.Line, diag.Subject.Start.Column, diag.Subject.Start.Byte},
+				End:   jsonPos{diag.Subject.End.Line, diag.Subject.End.Column, diag.Subject.End.Byte},
+			}
+		}
+		output.Diagnostics = append(output.Diagnostics, jsonDiag)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(output)
+}
+
+// See https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+	srcRootId    = "%SRCROOT%"
+)
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool               sarifTool                   `json:"tool"`
+	OriginalUriBaseIds map[string]sarifArtifactLoc `json:"originalUriBaseIds"`
+	Results            []sarifResult               `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationUri string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	Id               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleId    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLoc `json:"artifactLocation"`
+	Region           *sarifRegion     `json:"region,omitempty"`
+}
+
+type sarifArtifactLoc struct {
+	Uri       string `json:"uri"`
+	UriBaseId string `json:"uriBaseId,omitempty"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn"`
+	EndLine     int `json:"endLine"`
+	EndColumn   int `json:"endColumn"`
+}
+
+// WriteSARIF writes diagnostics as a SARIF log, which can be uploaded
+// to code scanning services. File locations are relative to rootPath.
+func WriteSARIF(w io.Writer, rootPath, version string, diags Diagnostics) error {
+	sources := []ast.DiagnosticSource{
+		ast.HCLParsingSource,
+		ast.SchemaValidationSource,
+		ast.ReferenceValidationSource,
+		ast.TofuValidateSource,
+		ast.ProviderLockValidationSource,
+	}
+	sarifRules := make([]sarifRule, 0, len(sources))
+	for _, source := range sources {
+		sarifRules = append(sarifRules, sarifRule{
+			Id:               rules[source].id,
+			ShortDescription: sarifMessage{Text: rules[source].description},
+		})
+	}
+
+	results := make([]sarifResult, 0, len(diags))
+	for _, diag := range diags {
+		msg := diag.Summary
+		if diag.Detail != "" {
+			msg = fmt.Sprintf("%s\n\n%s", msg, diag.Detail)
+		}
+
+		loc := sarifPhysicalLocation{
+			ArtifactLocation: sarifArtifactLoc{
+				Uri:       filepath.ToSlash(relPath(rootPath, diag.Path)),
+				UriBaseId: srcRootId,
+			},
+		}
+		if diag.Subject != nil {
+			loc.Region = &sarifRegion{
+				StartLine:   diag.Subject.Start.Line,
+				StartColumn: diag.Subject.Start.Column,
+				EndLine:     diag.Subject.End.Line,
+				EndColumn:   diag.Subject.End.Column,
+			}
+		}
+
+		results = append(results, sarifResult{
+			RuleId:    rules[diag.Source].id,
+			Level:     severityString(diag.Severity),
+			Message:   sarifMessage{Text: msg},
+			Locations: []sarifLocation{{PhysicalLocation: loc}},
+		})
+	}
+
+	output := sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs: []sarifRun{
+			{
+				Tool: sarifTool{
+					Driver: sarifDriver{
+						Name:           "tofu-ls",
+						Version:        version,
+						InformationUri: "https://github.com/opentofu/tofu-ls",
+						Rules:          sarifRules,
+					},
+				},
+				OriginalUriBaseIds: map[string]sarifArtifactLoc{
+					srcRootId: {Uri: uri.FromPath(rootPath) + "/"},
+				},
+				Results: results,
+			},
+		},
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(output)
+}
+
+// severityString returns the severity as used in both,
+// the JSON output of tofu and SARIF levels
+func severityString(severity hcl.DiagnosticSeverity) string {
+	switch severity {
+	case hcl.DiagError:
+		return "error"
+	case hcl.DiagWarning:
+		return "warning"
+	}
+	return "none"
+}
+
+func relPath(rootPath, path string) string {
+	rel, err := filepath.Rel(rootPath, path)
+	if err != nil {
+		return path
+	}
+	return rel
+}
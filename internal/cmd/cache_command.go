@@ -0,0 +1,73 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/mitchellh/cli"
+
+	"github.com/opentofu/tofu-ls/internal/cache"
+)
+
+type CacheCleanCommand struct {
+	Ui cli.Ui
+
+	cacheDir string
+}
+
+func (c *CacheCleanCommand) flags() *flag.FlagSet {
+	fs := defaultFlagSet("cache clean")
+
+	defaultDir, _ := cache.DefaultDir()
+	fs.StringVar(&c.cacheDir, "dir", defaultDir, "path to the cache directory")
+
+	fs.Usage = func() { c.Ui.Error(c.Help()) }
+
+	return fs
+}
+
+func (c *CacheCleanCommand) Run(args []string) int {
+	f := c.flags()
+	if err := f.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s", err))
+		return 1
+	}
+
+	if c.cacheDir == "" {
+		c.Ui.Error("Unable to determine the cache directory, please specify -dir")
+		return 1
+	}
+
+	diskCache := cache.New(c.cacheDir, 0)
+	size, err := diskCache.Size()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error reading cache: %s", err))
+		return 1
+	}
+
+	err = diskCache.Clean()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error cleaning cache: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf("Removed %d bytes of cached data from %s", size, c.cacheDir))
+	return 0
+}
+
+func (c *CacheCleanCommand) Help() string {
+	helpText := `
+Usage: tofu-ls cache clean [-dir=path]
+
+` + c.Synopsis() + "\n\n" + helpForFlags(c.flags())
+
+	return strings.TrimSpace(helpText)
+}
+
+func (c *CacheCleanCommand) Synopsis() string {
+	return "Removes provider schemas and registry data cached by the language server"
+}
@@ -0,0 +1,151 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/mitchellh/cli"
+	"github.com/opentofu/tofu-ls/internal/check"
+	lsctx "github.com/opentofu/tofu-ls/internal/context"
+	"github.com/opentofu/tofu-ls/internal/logging"
+)
+
+type CheckCommand struct {
+	Ui      cli.Ui
+	Version string
+
+	// flags
+	format             string
+	enhancedValidation bool
+	logFilePath        string
+}
+
+func (c *CheckCommand) flags() *flag.FlagSet {
+	fs := defaultFlagSet("check")
+
+	fs.StringVar(&c.format, "format", "text", "output format of diagnostics: text, json or sarif")
+	fs.BoolVar(&c.enhancedValidation, "enhanced-validation", true, "validate configuration "+
+		"against schemas and references, in addition to syntax")
+	fs.StringVar(&c.logFilePath, "log-file", "", "path to a file to log into with support "+
+		"for variables (e.g. timestamp, pid, ppid) via Go template syntax {{varName}}")
+
+	fs.Usage = func() { c.Ui.Error(c.Help()) }
+
+	return fs
+}
+
+func (c *CheckCommand) Run(args []string) int {
+	f := c.flags()
+	if err := f.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s", err))
+		return 1
+	}
+
+	switch c.format {
+	case "text", "json", "sarif":
+	default:
+		c.Ui.Error(fmt.Sprintf("Unknown format %q, expected text, json or sarif", c.format))
+		return 1
+	}
+
+	if f.NArg() > 1 {
+		c.Ui.Error("Expected at most one directory to check")
+		return 1
+	}
+	dir := "."
+	if f.NArg() == 1 {
+		dir = f.Arg(0)
+	}
+	rootPath, err := filepath.Abs(dir)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to resolve %q: %s", dir, err))
+		return 1
+	}
+	fi, err := os.Stat(rootPath)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to read %q: %s", dir, err))
+		return 1
+	}
+	if !fi.IsDir() {
+		c.Ui.Error(fmt.Sprintf("%q is not a directory", dir))
+		return 1
+	}
+
+	checker := check.NewChecker()
+	checker.SetEnhancedValidation(c.enhancedValidation)
+
+	logger := log.New(io.Discard, "", 0)
+	if c.logFilePath != "" {
+		fl, err := logging.NewFileLogger(c.logFilePath)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Failed to setup file logging: %s", err))
+			return 1
+		}
+		defer fl.Close()
+
+		logger = fl.Logger()
+		checker.SetLogger(logger)
+	}
+
+	ctx, cancelFunc := lsctx.WithSignalCancel(context.Background(), logger,
+		os.Interrupt, syscall.SIGTERM)
+	defer cancelFunc()
+
+	diags, err := checker.Check(ctx, rootPath)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to check %q: %s", dir, err))
+		return 1
+	}
+
+	buf := &strings.Builder{}
+	switch c.format {
+	case "json":
+		err = check.WriteJSON(buf, rootPath, diags)
+	case "sarif":
+		err = check.WriteSARIF(buf, rootPath, c.Version, diags)
+	default:
+		err = check.WriteText(buf, rootPath, diags)
+		errCount, warnCount := diags.Count()
+		fmt.Fprintf(buf, "%d error(s), %d warning(s)\n", errCount, warnCount)
+	}
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to write diagnostics: %s", err))
+		return 1
+	}
+	c.Ui.Output(strings.TrimSuffix(buf.String(), "\n"))
+
+	if diags.HasErrors() {
+		return 1
+	}
+	return 0
+}
+
+func (c *CheckCommand) Help() string {
+	helpText := `
+Usage: tofu-ls check [options] [DIR]
+
+` + c.Synopsis() + `
+
+The directory (which defaults to the current working directory) is walked
+recursively and all modules, variable definitions files and tests
+within it are checked. The command exits with a non-zero status
+if any errors are found.
+
+` + helpForFlags(c.flags())
+
+	return strings.TrimSpace(helpText)
+}
+
+func (c *CheckCommand) Synopsis() string {
+	return "Reports diagnostics for configuration without starting the Language Server"
+}
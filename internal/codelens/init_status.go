@@ -0,0 +1,183 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package codelens
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
+	tfmod "github.com/opentofu/opentofu-schema/module"
+	tfaddr "github.com/opentofu/registry-address"
+	ilsp "github.com/opentofu/tofu-ls/internal/lsp"
+	"github.com/opentofu/tofu-ls/internal/pathcmp"
+	"github.com/opentofu/tofu-ls/internal/tofu/datadir"
+	"github.com/opentofu/tofu-ls/internal/uri"
+)
+
+type ModuleReader interface {
+	Paths(ctx context.Context) []lang.Path
+	DeclaredModuleCalls(modPath string) (map[string]tfmod.DeclaredModuleCall, error)
+	ModuleCallPath(modPath string, mc tfmod.DeclaredModuleCall) (string, bool)
+	ProviderRequirements(modPath string) (tfmod.ProviderRequirements, error)
+}
+
+type RootModuleReader interface {
+	InitState(modPath string) (lockFile bool, manifest bool, err error)
+	InstalledProviders(modPath string) (map[tfaddr.Provider]*version.Version, error)
+}
+
+// InitStatus returns code lenses at the top of each file of a root module,
+// which show whether the module is initialized and whether the installed
+// providers match the version constraints in required_providers. If either
+// isn't the case, a lens runs the given init command for the module.
+//
+// Modules are considered root modules if they have a lock file or data
+// directory, or if they aren't called by any other module in the workspace.
+func InitStatus(modReader ModuleReader, rootReader RootModuleReader, initCmdId string) lang.CodeLensFunc {
+	return func(ctx context.Context, path lang.Path, file string) ([]lang.CodeLens, error) {
+		lenses := make([]lang.CodeLens, 0)
+
+		if path.LanguageID != ilsp.OpenTofu.String() || datadir.IsInstalledModulePath(path.Path) {
+			return lenses, nil
+		}
+
+		lockFile, manifest, err := rootReader.InitState(path.Path)
+		if err != nil && isCalledModule(ctx, modReader, path.Path) {
+			return lenses, nil
+		}
+
+		moduleCalls, err := modReader.DeclaredModuleCalls(path.Path)
+		if err != nil {
+			return lenses, nil
+		}
+		requirements, err := modReader.ProviderRequirements(path.Path)
+		if err != nil {
+			return lenses, nil
+		}
+
+		missing := make([]string, 0)
+		if !lockFile {
+			missing = append(missing, "lock file")
+		}
+		if !manifest && len(moduleCalls) > 0 {
+			missing = append(missing, "module manifest")
+		}
+
+		rng := hcl.Range{
+			Filename: file,
+			Start:    hcl.InitialPos,
+			End:      hcl.InitialPos,
+		}
+		needsInit := len(missing) > 0
+
+		if needsInit {
+			lenses = append(lenses, lang.CodeLens{
+				Range: rng,
+				Command: lang.Command{
+					Title: fmt.Sprintf("Not initialized (missing %s)", strings.Join(missing, " and ")),
+				},
+			})
+		} else {
+			lenses = append(lenses, lang.CodeLens{
+				Range: rng,
+				Command: lang.Command{
+					Title: "Initialized",
+				},
+			})
+
+			if len(requirements) > 0 {
+				installed, _ := rootReader.InstalledProviders(path.Path)
+				mismatched := mismatchedProviders(requirements, installed)
+				needsInit = len(mismatched) > 0
+
+				lenses = append(lenses, lang.CodeLens{
+					Range: rng,
+					Command: lang.Command{
+						Title: providersTitle(len(requirements), mismatched),
+					},
+				})
+			}
+		}
+
+		if needsInit {
+			lenses = append(lenses, lang.CodeLens{
+				Range: rng,
+				Command: lang.Command{
+					Title: "Run tofu init",
+					ID:    initCmdId,
+					Arguments: []lang.CommandArgument{
+						StringArgument(fmt.Sprintf("uri=%s", uri.FromPath(path.Path))),
+					},
+				},
+			})
+		}
+
+		return lenses, nil
+	}
+}
+
+type StringArgument string
+
+func (s StringArgument) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(s))
+}
+
+// isCalledModule checks whether any other module in the workspace
+// calls the module at modPath
+func isCalledModule(ctx context.Context, modReader ModuleReader, modPath string) bool {
+	for _, path := range modReader.Paths(ctx) {
+		if path.Path == modPath {
+			continue
+		}
+		moduleCalls, err := modReader.DeclaredModuleCalls(path.Path)
+		if err != nil {
+			continue
+		}
+		for _, mc := range moduleCalls {
+			mcPath, ok := modReader.ModuleCallPath(path.Path, mc)
+			if ok && pathcmp.PathEquals(filepath.Clean(mcPath), modPath) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// mismatchedProviders returns the display names of required providers,
+// which are either not installed or installed in a version not matching
+// the version constraints
+func mismatchedProviders(requirements tfmod.ProviderRequirements, installed map[tfaddr.Provider]*version.Version) []string {
+	mismatched := make([]string, 0)
+	for pAddr, constraints := range requirements {
+		v, ok := installed[pAddr]
+		if !ok || v == nil {
+			mismatched = append(mismatched, fmt.Sprintf("%s (not installed)", pAddr.ForDisplay()))
+			continue
+		}
+		if !constraints.Check(v) {
+			mismatched = append(mismatched, fmt.Sprintf("%s (%s installed, %s required)",
+				pAddr.ForDisplay(), v, constraints))
+		}
+	}
+	sort.Strings(mismatched)
+	return mismatched
+}
+
+func providersTitle(total int, mismatched []string) string {
+	if len(mismatched) == 0 {
+		if total == 1 {
+			return "Installed provider matches required_providers"
+		}
+		return fmt.Sprintf("All %d installed providers match required_providers", total)
+	}
+	return fmt.Sprintf("%s not matching required_providers: %s",
+		getTitle("provider", "providers", len(mismatched)), strings.Join(mismatched, ", "))
+}
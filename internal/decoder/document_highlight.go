@@ -0,0 +1,166 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/hcl-lang/decoder"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/reference"
+	"github.com/hashicorp/hcl/v2"
+)
+
+// Highlight represents a range within a file where an object
+// is either declared, or referenced.
+type Highlight struct {
+	Range         hcl.Range
+	IsDeclaration bool
+}
+
+// DocumentHighlights finds the variable, local value, resource, data source,
+// module call or output of a module call which is declared or referenced
+// at the given position and returns ranges of its declaration and all
+// references to it within the same file.
+//
+// It returns nil if there is no such object at the position.
+func DocumentHighlights(pr decoder.PathReader, path lang.Path, filename string, pos hcl.Pos) ([]Highlight, error) {
+	pathCtx, err := pr.PathContext(path)
+	if err != nil {
+		return nil, err
+	}
+
+	f, ok := pathCtx.Files[filename]
+	if !ok {
+		return nil, fmt.Errorf("%s: file not found", filename)
+	}
+
+	addr, ok := objectAtPos(pathCtx, filename, pos)
+	if !ok {
+		return nil, nil
+	}
+
+	highlights := make([]Highlight, 0)
+	seen := make(map[hcl.Range]struct{})
+	addHighlight := func(rng hcl.Range, isDeclaration bool) {
+		if _, ok := seen[rng]; ok {
+			return
+		}
+		seen[rng] = struct{}{}
+		highlights = append(highlights, Highlight{
+			Range:         rng,
+			IsDeclaration: isDeclaration,
+		})
+	}
+
+	// An object can be represented by more targets with the same address
+	for _, target := range targetsWithAddr(pathCtx.ReferenceTargets, addr) {
+		if target.DefRangePtr == nil || target.DefRangePtr.Filename != filename {
+			continue
+		}
+		rng, ok := declarationNameRange(f, target)
+		if !ok {
+			rng = *target.DefRangePtr
+		}
+		addHighlight(rng, true)
+	}
+
+	for _, origin := range pathCtx.ReferenceOrigins {
+		localOrigin, ok := origin.(reference.LocalOrigin)
+		if !ok || localOrigin.Range.Filename != filename || len(localOrigin.Addr) < len(addr) {
+			continue
+		}
+		if !localOrigin.Addr.FirstSteps(uint(len(addr))).Equals(addr) {
+			continue
+		}
+		rng, ok := objectReferenceRange(f, localOrigin.Range, len(addr))
+		if !ok {
+			rng = localOrigin.Range
+		}
+		addHighlight(rng, false)
+	}
+
+	sort.SliceStable(highlights, func(i, j int) bool {
+		return highlights[i].Range.Start.Byte < highlights[j].Range.Start.Byte
+	})
+
+	return highlights, nil
+}
+
+// objectAtPos returns the address of the object which is either
+// referenced or declared at the given position.
+func objectAtPos(pathCtx *decoder.PathContext, filename string, pos hcl.Pos) (lang.Address, bool) {
+	origins, _ := pathCtx.ReferenceOrigins.AtPos(filename, pos)
+	for _, origin := range origins {
+		localOrigin, ok := origin.(reference.LocalOrigin)
+		if !ok {
+			continue
+		}
+		objLen := highlightableAddressLength(localOrigin.Addr)
+		if objLen == 0 || len(localOrigin.Addr) < objLen {
+			continue
+		}
+		addr := localOrigin.Addr.FirstSteps(uint(objLen))
+		if len(targetsWithAddr(pathCtx.ReferenceTargets, addr)) == 0 {
+			continue
+		}
+		// Outputs of a module call are highlighted separately from
+		// each other, as these are declared in the called module.
+		if outLen := moduleOutputAddressLength(localOrigin.Addr); outLen > 0 {
+			return localOrigin.Addr.FirstSteps(uint(outLen)), true
+		}
+		return addr, true
+	}
+
+	for _, target := range pathCtx.ReferenceTargets {
+		if target.DefRangePtr == nil || target.DefRangePtr.Filename != filename || !target.DefRangePtr.ContainsPos(pos) {
+			continue
+		}
+		if objLen := highlightableAddressLength(target.Addr); objLen == 0 || objLen != len(target.Addr) {
+			continue
+		}
+		return target.Addr, true
+	}
+
+	return nil, false
+}
+
+// highlightableAddressLength returns the number of address steps
+// identifying the object, or 0 if the object cannot be highlighted,
+// such as outputs, which are never referenced within the same module.
+func highlightableAddressLength(addr lang.Address) int {
+	if len(addr) == 0 {
+		return 0
+	}
+	if root, ok := addr[0].(lang.RootStep); ok && root.Name == "output" {
+		return 0
+	}
+	return objectAddressLength(addr)
+}
+
+// moduleOutputAddressLength returns the number of address steps
+// identifying an output of a module call, e.g. 3 for module.vpc.id
+// or 4 for module.vpc[0].id, or 0 if the address doesn't refer
+// to a module output.
+func moduleOutputAddressLength(addr lang.Address) int {
+	if len(addr) < 3 {
+		return 0
+	}
+	if root, ok := addr[0].(lang.RootStep); !ok || root.Name != "module" {
+		return 0
+	}
+
+	outIdx := 2
+	if _, ok := addr[outIdx].(lang.IndexStep); ok {
+		outIdx++
+	}
+	if len(addr) <= outIdx {
+		return 0
+	}
+	if _, ok := addr[outIdx].(lang.AttrStep); !ok {
+		return 0
+	}
+	return outIdx + 1
+}
@@ -0,0 +1,101 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"bytes"
+	"path/filepath"
+	"unicode/utf8"
+
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
+)
+
+// FileEdits maps absolute file paths to edits to apply in them
+type FileEdits map[string][]lang.TextEdit
+
+// Add appends an edit to the file the range belongs to,
+// which is relative to the given directory.
+func (fe FileEdits) Add(dirPath string, rng hcl.Range, newText string) {
+	path := filepath.Join(dirPath, rng.Filename)
+	fe[path] = append(fe[path], lang.TextEdit{
+		Range:   rng,
+		NewText: newText,
+		Snippet: newText,
+	})
+}
+
+// WholeLinesRange extends the given range to the start of its first line
+// and past the line break of its last line, so that replacing it doesn't
+// leave empty lines behind.
+func WholeLinesRange(src []byte, rng hcl.Range) hcl.Range {
+	rng.Start = lineStart(src, rng.Start)
+
+	if rng.End.Byte > len(src) {
+		return rng
+	}
+	lineEnd := bytes.IndexByte(src[rng.End.Byte:], '\n')
+	if lineEnd < 0 {
+		rng.End = posAfter(src, rng.End, len(src)-rng.End.Byte)
+		return rng
+	}
+	rng.End = hcl.Pos{
+		Line:   rng.End.Line + 1,
+		Column: 1,
+		Byte:   rng.End.Byte + lineEnd + 1,
+	}
+	return rng
+}
+
+// lineStart returns the position of the first byte of the line
+// containing pos. This is found by scanning the source, since
+// columns count characters rather than bytes.
+func lineStart(src []byte, pos hcl.Pos) hcl.Pos {
+	if pos.Byte > len(src) {
+		return pos
+	}
+	return hcl.Pos{
+		Line:   pos.Line,
+		Column: 1,
+		Byte:   bytes.LastIndexByte(src[:pos.Byte], '\n') + 1,
+	}
+}
+
+// EndOfFile returns the position after the last byte
+func EndOfFile(src []byte) hcl.Pos {
+	return posAfter(src, hcl.InitialPos, len(src))
+}
+
+// posAfter moves pos forward by n bytes. Like in HCL, columns
+// count characters rather than bytes.
+func posAfter(src []byte, pos hcl.Pos, n int) hcl.Pos {
+	for _, b := range src[pos.Byte : pos.Byte+n] {
+		pos.Byte++
+		if b == '\n' {
+			pos.Line++
+			pos.Column = 1
+			continue
+		}
+		if utf8.RuneStart(b) {
+			pos.Column++
+		}
+	}
+	return pos
+}
+
+// posBefore moves pos back by n bytes
+func posBefore(src []byte, pos hcl.Pos, n int) hcl.Pos {
+	if n == 0 {
+		return pos
+	}
+	// Columns are easier to compute going forward from the line start
+	target := pos.Byte - n
+	lineStart := bytes.LastIndexByte(src[:target], '\n') + 1
+	line := pos.Line - bytes.Count(src[target:pos.Byte], []byte{'\n'})
+	return hcl.Pos{
+		Line:   line,
+		Column: utf8.RuneCount(src[lineStart:target]) + 1,
+		Byte:   target,
+	}
+}
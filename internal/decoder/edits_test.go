@@ -0,0 +1,43 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl/v2"
+)
+
+func TestWholeLinesRange(t *testing.T) {
+	src := []byte("locals {\n  é = \"ü\"\n}\n")
+
+	// Columns count characters, so the start column
+	// doesn't match the byte offset of the line start
+	rng := hcl.Range{
+		Filename: "main.tf",
+		Start:    hcl.Pos{Line: 2, Column: 7, Byte: 16},
+		End:      hcl.Pos{Line: 2, Column: 10, Byte: 20},
+	}
+	expectedRange := hcl.Range{
+		Filename: "main.tf",
+		Start:    hcl.Pos{Line: 2, Column: 1, Byte: 9},
+		End:      hcl.Pos{Line: 3, Column: 1, Byte: 21},
+	}
+
+	given := WholeLinesRange(src, rng)
+	if diff := cmp.Diff(expectedRange, given); diff != "" {
+		t.Fatalf("unexpected range: %s", diff)
+	}
+}
+
+func TestEndOfFile(t *testing.T) {
+	src := []byte("locals {\n  é = \"ü\"\n}")
+
+	expectedPos := hcl.Pos{Line: 3, Column: 2, Byte: 22}
+	given := EndOfFile(src)
+	if diff := cmp.Diff(expectedPos, given); diff != "" {
+		t.Fatalf("unexpected position: %s", diff)
+	}
+}
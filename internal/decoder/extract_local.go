@@ -0,0 +1,324 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+
+	"github.com/hashicorp/hcl-lang/decoder"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+// localsFilename is the conventional file for local values
+// which extracted expressions are moved into, if it exists.
+const localsFilename = "locals.tf"
+
+// localsIndent is the indentation of attributes in a locals block
+const localsIndent = "  "
+
+// nonExtractableBlocks represents blocks which only accept
+// static values or references to particular objects,
+// so their expressions cannot be replaced with local values.
+var nonExtractableBlocks = []string{
+	"terraform",
+	"variable",
+	"moved",
+	"removed",
+	"lifecycle",
+}
+
+// extractableImportAttributes represents attributes of import blocks
+// which accept arbitrary expressions, unlike the address to import to.
+var extractableImportAttributes = []string{
+	"id",
+}
+
+// nonExtractableAttributes represents attributes which only
+// accept static values or references to particular objects.
+var nonExtractableAttributes = []string{
+	"depends_on",
+	"provider",
+	"providers",
+}
+
+// ExtractedLocal represents an expression extracted into a local value
+type ExtractedLocal struct {
+	Name  string
+	Edits FileEdits
+}
+
+// ExtractToLocal returns edits which move the expression at the given range
+// into a new local value and replace the expression with a reference to it.
+//
+// The local value is added to an existing locals block in locals.tf,
+// or in the current file if there is no locals.tf, otherwise a new
+// locals block is created there. It returns nil if the range
+// does not correspond to any expression which can be extracted.
+func ExtractToLocal(pr decoder.PathReader, path lang.Path, filename string, rng hcl.Range) (*ExtractedLocal, error) {
+	pathCtx, err := pr.PathContext(path)
+	if err != nil {
+		return nil, err
+	}
+
+	f, ok := pathCtx.Files[filename]
+	if !ok {
+		return nil, fmt.Errorf("%s: file not found", filename)
+	}
+	body, ok := f.Body.(*hclsyntax.Body)
+	if !ok {
+		// JSON is not supported
+		return nil, nil
+	}
+
+	rng = trimRangeSpace(f.Bytes, rng)
+	if rng.Start.Byte >= rng.End.Byte {
+		return nil, nil
+	}
+
+	finder := &expressionFinder{rng: rng}
+	hclsyntax.Walk(body, finder)
+	if finder.expr == nil || !isExtractable(finder.expr, finder.ancestors) {
+		return nil, nil
+	}
+
+	name := "value"
+	for i := len(finder.ancestors) - 1; i >= 0; i-- {
+		if attr, ok := finder.ancestors[i].(*hclsyntax.Attribute); ok {
+			name = attr.Name
+			break
+		}
+	}
+	name = uniqueLocalName(pathCtx, name)
+
+	exprRng := finder.expr.Range()
+	// Lines following the first one are indented relative to
+	// the line of the expression, which may be nested deeper
+	// than attributes of the locals block.
+	exprText := reindent(exprRng.SliceBytes(f.Bytes), lineIndent(f.Bytes, exprRng.Start), localsIndent)
+
+	edits := make(FileEdits)
+	edits.Add(path.Path, exprRng, fmt.Sprintf("local.%s", name))
+
+	localsFile := filename
+	if _, ok := pathCtx.Files[localsFilename]; ok {
+		localsFile = localsFilename
+	}
+	insertRng, text := localInsertion(pathCtx.Files[localsFile], localsFile, name, exprText)
+	edits.Add(path.Path, insertRng, text)
+
+	return &ExtractedLocal{
+		Name:  name,
+		Edits: edits,
+	}, nil
+}
+
+// expressionFinder finds the outermost expression with the given range
+// and keeps track of its ancestors.
+type expressionFinder struct {
+	rng       hcl.Range
+	stack     []hclsyntax.Node
+	expr      hclsyntax.Expression
+	ancestors []hclsyntax.Node
+}
+
+func (ef *expressionFinder) Enter(node hclsyntax.Node) hcl.Diagnostics {
+	if expr, ok := node.(hclsyntax.Expression); ok && ef.expr == nil {
+		exprRng := expr.Range()
+		if exprRng.Start.Byte == ef.rng.Start.Byte && exprRng.End.Byte == ef.rng.End.Byte {
+			ef.expr = expr
+			ef.ancestors = slices.Clone(ef.stack)
+		}
+	}
+	ef.stack = append(ef.stack, node)
+	return nil
+}
+
+func (ef *expressionFinder) Exit(node hclsyntax.Node) hcl.Diagnostics {
+	ef.stack = ef.stack[:len(ef.stack)-1]
+	return nil
+}
+
+// isExtractable checks whether the expression can be moved into a local
+// value, i.e. it is within an attribute accepting arbitrary expressions
+// and it doesn't reference anything only available in its current scope,
+// such as count.index or for expression symbols.
+func isExtractable(expr hclsyntax.Expression, ancestors []hclsyntax.Node) bool {
+	if len(ancestors) == 0 {
+		return false
+	}
+	if _, ok := ancestors[len(ancestors)-1].(*hclsyntax.TemplateExpr); ok {
+		// Literal parts of a template cannot be replaced by a reference
+		if _, ok := expr.(*hclsyntax.LiteralValueExpr); ok {
+			return false
+		}
+	}
+
+	scopedNames := []string{"count", "each", "self"}
+	inAttribute := false
+	var parentBlock *hclsyntax.Block
+	for _, node := range ancestors {
+		switch n := node.(type) {
+		case *hclsyntax.Block:
+			if slices.Contains(nonExtractableBlocks, n.Type) {
+				return false
+			}
+			if n.Type == "dynamic" && len(n.Labels) > 0 {
+				iterator := n.Labels[0]
+				if attr, ok := n.Body.Attributes["iterator"]; ok {
+					iterator = hcl.ExprAsKeyword(attr.Expr)
+				}
+				scopedNames = append(scopedNames, iterator)
+			}
+			parentBlock = n
+		case *hclsyntax.Attribute:
+			if slices.Contains(nonExtractableAttributes, n.Name) {
+				return false
+			}
+			// Module source and version need to be static
+			if parentBlock != nil && parentBlock.Type == "module" && (n.Name == "source" || n.Name == "version") {
+				return false
+			}
+			if parentBlock != nil && parentBlock.Type == "import" && !slices.Contains(extractableImportAttributes, n.Name) {
+				return false
+			}
+			inAttribute = true
+		case *hclsyntax.ObjectConsKeyExpr:
+			// Object keys are names rather than expressions
+			return false
+		case *hclsyntax.ForExpr:
+			scopedNames = append(scopedNames, n.KeyVar, n.ValVar)
+		}
+	}
+	if !inAttribute {
+		return false
+	}
+
+	for _, traversal := range expr.Variables() {
+		if slices.Contains(scopedNames, traversal.RootName()) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// uniqueLocalName returns the given name, or the name with a numeric
+// suffix if a local value of the same name is already declared.
+func uniqueLocalName(pathCtx *decoder.PathContext, name string) string {
+	isDeclared := func(name string) bool {
+		addr := lang.Address{
+			lang.RootStep{Name: "local"},
+			lang.AttrStep{Name: name},
+		}
+		return len(targetsWithAddr(pathCtx.ReferenceTargets, addr)) > 0
+	}
+
+	candidate := name
+	for i := 2; isDeclared(candidate); i++ {
+		candidate = fmt.Sprintf("%s_%d", name, i)
+	}
+	return candidate
+}
+
+// localInsertion returns the range and text to insert a local value,
+// either into the first locals block of the file, or as a new block
+// at the end of the file.
+func localInsertion(f *hcl.File, filename, name, exprText string) (hcl.Range, string) {
+	attrLine := fmt.Sprintf("%s%s = %s\n", localsIndent, name, exprText)
+
+	if body, ok := f.Body.(*hclsyntax.Body); ok {
+		for _, block := range body.Blocks {
+			if block.Type != "locals" {
+				continue
+			}
+			closingBrace := block.Body.EndRange.Start
+			if closingBrace.Line > block.Body.SrcRange.Start.Line {
+				pos := lineStart(f.Bytes, closingBrace)
+				return hcl.Range{Filename: filename, Start: pos, End: pos}, attrLine
+			}
+			// Break a single-line block, i.e. locals {}
+			return hcl.Range{Filename: filename, Start: closingBrace, End: closingBrace}, "\n" + attrLine
+		}
+	}
+
+	text := fmt.Sprintf("locals {\n%s}\n", attrLine)
+	if len(f.Bytes) > 0 {
+		text = "\n" + text
+		if f.Bytes[len(f.Bytes)-1] != '\n' {
+			text = "\n" + text
+		}
+	}
+	eof := EndOfFile(f.Bytes)
+	return hcl.Range{Filename: filename, Start: eof, End: eof}, text
+}
+
+// trimRangeSpace excludes any leading and trailing whitespace
+// from the range, e.g. when the selection spans whole lines.
+func trimRangeSpace(src []byte, rng hcl.Range) hcl.Range {
+	if rng.End.Byte > len(src) || rng.Start.Byte > rng.End.Byte {
+		return rng
+	}
+
+	selected := src[rng.Start.Byte:rng.End.Byte]
+	leading := len(selected) - len(bytes.TrimLeft(selected, " \t\r\n"))
+	trailing := len(selected) - len(bytes.TrimRight(selected, " \t\r\n"))
+	if leading == len(selected) {
+		return hcl.Range{Filename: rng.Filename, Start: rng.Start, End: rng.Start}
+	}
+
+	rng.Start = posAfter(src, rng.Start, leading)
+	rng.End = posBefore(src, rng.End, trailing)
+	return rng
+}
+
+// lineIndent returns the leading whitespace of the line containing pos
+func lineIndent(src []byte, pos hcl.Pos) []byte {
+	line := src[lineStart(src, pos).Byte:]
+	return line[:len(line)-len(bytes.TrimLeft(line, " \t"))]
+}
+
+// reindent replaces the given indentation at the beginning of each line
+// except the first one, keeping any lines within heredoc templates
+// intact, since their indentation is part of the value.
+func reindent(src []byte, from []byte, to string) string {
+	if bytes.Equal(from, []byte(to)) {
+		return string(src)
+	}
+
+	type byteRange struct{ start, end int }
+	var heredocs []byteRange
+	tokens, _ := hclsyntax.LexExpression(src, "", hcl.InitialPos)
+	for i, token := range tokens {
+		if token.Type != hclsyntax.TokenOHeredoc {
+			continue
+		}
+		for _, closing := range tokens[i:] {
+			if closing.Type == hclsyntax.TokenCHeredoc {
+				heredocs = append(heredocs, byteRange{token.Range.End.Byte, closing.Range.Start.Byte})
+				break
+			}
+		}
+	}
+	inHeredoc := func(offset int) bool {
+		return slices.ContainsFunc(heredocs, func(r byteRange) bool {
+			return offset >= r.start && offset < r.end
+		})
+	}
+
+	var buf bytes.Buffer
+	offset := 0
+	for i, line := range bytes.SplitAfter(src, []byte{'\n'}) {
+		lineLen := len(line)
+		if i > 0 && !inHeredoc(offset) && bytes.HasPrefix(line, from) {
+			line = append([]byte(to), line[len(from):]...)
+		}
+		buf.Write(line)
+		offset += lineLen
+	}
+	return buf.String()
+}
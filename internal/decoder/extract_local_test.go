@@ -0,0 +1,299 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl-lang/decoder"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/reference"
+	"github.com/hashicorp/hcl/v2"
+)
+
+func TestExtractToLocal(t *testing.T) {
+	modPath := lang.Path{Path: "mod", LanguageID: "opentofu"}
+	mainSrc := `resource "aws_instance" "web" {
+  count = 2
+  ami   = "ami-${var.region}"
+  tags  = { Name = "web-${count.index}" }
+}
+`
+	importSrc := `import {
+  to = aws_instance.web
+  id = "i-${var.suffix}"
+}
+`
+	nestedSrc := `resource "aws_instance" "web" {
+  dynamic "ebs" {
+    content {
+      tags = merge(
+        var.tags,
+        {
+          Doc = <<EOT
+        indented
+EOT
+        },
+      )
+    }
+  }
+}
+`
+
+	testCases := []struct {
+		name          string
+		files         map[string]string
+		targets       reference.Targets
+		rng           hcl.Range
+		expectedLocal *ExtractedLocal
+	}{
+		{
+			"new locals block",
+			map[string]string{"main.tf": mainSrc},
+			reference.Targets{},
+			hcl.Range{
+				Filename: "main.tf",
+				Start:    hcl.Pos{Line: 3, Column: 11, Byte: 54},
+				End:      hcl.Pos{Line: 3, Column: 30, Byte: 73},
+			},
+			&ExtractedLocal{
+				Name: "ami",
+				Edits: FileEdits{
+					filepath.Join("mod", "main.tf"): {
+						{
+							Range: hcl.Range{
+								Filename: "main.tf",
+								Start:    hcl.Pos{Line: 3, Column: 11, Byte: 54},
+								End:      hcl.Pos{Line: 3, Column: 30, Byte: 73},
+							},
+							NewText: "local.ami",
+							Snippet: "local.ami",
+						},
+						{
+							Range: hcl.Range{
+								Filename: "main.tf",
+								Start:    hcl.Pos{Line: 6, Column: 1, Byte: 118},
+								End:      hcl.Pos{Line: 6, Column: 1, Byte: 118},
+							},
+							NewText: "\nlocals {\n  ami = \"ami-${var.region}\"\n}\n",
+							Snippet: "\nlocals {\n  ami = \"ami-${var.region}\"\n}\n",
+						},
+					},
+				},
+			},
+		},
+		{
+			"existing locals block with name collision",
+			map[string]string{
+				"main.tf":   mainSrc,
+				"locals.tf": "locals {\n  ami = \"foo\"\n}\n",
+			},
+			reference.Targets{
+				{
+					Addr: lang.Address{
+						lang.RootStep{Name: "local"},
+						lang.AttrStep{Name: "ami"},
+					},
+				},
+			},
+			// selection including surrounding whitespace
+			hcl.Range{
+				Filename: "main.tf",
+				Start:    hcl.Pos{Line: 3, Column: 10, Byte: 53},
+				End:      hcl.Pos{Line: 4, Column: 1, Byte: 75},
+			},
+			&ExtractedLocal{
+				Name: "ami_2",
+				Edits: FileEdits{
+					filepath.Join("mod", "main.tf"): {
+						{
+							Range: hcl.Range{
+								Filename: "main.tf",
+								Start:    hcl.Pos{Line: 3, Column: 11, Byte: 54},
+								End:      hcl.Pos{Line: 3, Column: 30, Byte: 73},
+							},
+							NewText: "local.ami_2",
+							Snippet: "local.ami_2",
+						},
+					},
+					filepath.Join("mod", "locals.tf"): {
+						{
+							Range: hcl.Range{
+								Filename: "locals.tf",
+								Start:    hcl.Pos{Line: 3, Column: 1, Byte: 23},
+								End:      hcl.Pos{Line: 3, Column: 1, Byte: 23},
+							},
+							NewText: "  ami_2 = \"ami-${var.region}\"\n",
+							Snippet: "  ami_2 = \"ami-${var.region}\"\n",
+						},
+					},
+				},
+			},
+		},
+		{
+			"interpolated reference",
+			map[string]string{"main.tf": mainSrc},
+			reference.Targets{},
+			hcl.Range{
+				Filename: "main.tf",
+				Start:    hcl.Pos{Line: 3, Column: 18, Byte: 61},
+				End:      hcl.Pos{Line: 3, Column: 28, Byte: 71},
+			},
+			&ExtractedLocal{
+				Name: "ami",
+				Edits: FileEdits{
+					filepath.Join("mod", "main.tf"): {
+						{
+							Range: hcl.Range{
+								Filename: "main.tf",
+								Start:    hcl.Pos{Line: 3, Column: 18, Byte: 61},
+								End:      hcl.Pos{Line: 3, Column: 28, Byte: 71},
+							},
+							NewText: "local.ami",
+							Snippet: "local.ami",
+						},
+						{
+							Range: hcl.Range{
+								Filename: "main.tf",
+								Start:    hcl.Pos{Line: 6, Column: 1, Byte: 118},
+								End:      hcl.Pos{Line: 6, Column: 1, Byte: 118},
+							},
+							NewText: "\nlocals {\n  ami = var.region\n}\n",
+							Snippet: "\nlocals {\n  ami = var.region\n}\n",
+						},
+					},
+				},
+			},
+		},
+		{
+			"reference to count",
+			map[string]string{"main.tf": mainSrc},
+			reference.Targets{},
+			hcl.Range{
+				Filename: "main.tf",
+				Start:    hcl.Pos{Line: 4, Column: 20, Byte: 93},
+				End:      hcl.Pos{Line: 4, Column: 40, Byte: 113},
+			},
+			nil,
+		},
+		{
+			"partial expression",
+			map[string]string{"main.tf": mainSrc},
+			reference.Targets{},
+			hcl.Range{
+				Filename: "main.tf",
+				Start:    hcl.Pos{Line: 3, Column: 11, Byte: 54},
+				End:      hcl.Pos{Line: 3, Column: 15, Byte: 58},
+			},
+			nil,
+		},
+		{
+			"import id",
+			map[string]string{"main.tf": importSrc},
+			reference.Targets{},
+			hcl.Range{
+				Filename: "main.tf",
+				Start:    hcl.Pos{Line: 3, Column: 8, Byte: 40},
+				End:      hcl.Pos{Line: 3, Column: 25, Byte: 57},
+			},
+			&ExtractedLocal{
+				Name: "id",
+				Edits: FileEdits{
+					filepath.Join("mod", "main.tf"): {
+						{
+							Range: hcl.Range{
+								Filename: "main.tf",
+								Start:    hcl.Pos{Line: 3, Column: 8, Byte: 40},
+								End:      hcl.Pos{Line: 3, Column: 25, Byte: 57},
+							},
+							NewText: "local.id",
+							Snippet: "local.id",
+						},
+						{
+							Range: hcl.Range{
+								Filename: "main.tf",
+								Start:    hcl.Pos{Line: 5, Column: 1, Byte: 60},
+								End:      hcl.Pos{Line: 5, Column: 1, Byte: 60},
+							},
+							NewText: "\nlocals {\n  id = \"i-${var.suffix}\"\n}\n",
+							Snippet: "\nlocals {\n  id = \"i-${var.suffix}\"\n}\n",
+						},
+					},
+				},
+			},
+		},
+		{
+			"import address",
+			map[string]string{"main.tf": importSrc},
+			reference.Targets{},
+			hcl.Range{
+				Filename: "main.tf",
+				Start:    hcl.Pos{Line: 2, Column: 8, Byte: 16},
+				End:      hcl.Pos{Line: 2, Column: 24, Byte: 32},
+			},
+			nil,
+		},
+		{
+			"multi-line expression in nested block",
+			map[string]string{"main.tf": nestedSrc},
+			reference.Targets{},
+			hcl.Range{
+				Filename: "main.tf",
+				Start:    hcl.Pos{Line: 4, Column: 14, Byte: 77},
+				End:      hcl.Pos{Line: 11, Column: 8, Byte: 173},
+			},
+			&ExtractedLocal{
+				Name: "tags",
+				Edits: FileEdits{
+					filepath.Join("mod", "main.tf"): {
+						{
+							Range: hcl.Range{
+								Filename: "main.tf",
+								Start:    hcl.Pos{Line: 4, Column: 14, Byte: 77},
+								End:      hcl.Pos{Line: 11, Column: 8, Byte: 173},
+							},
+							NewText: "local.tags",
+							Snippet: "local.tags",
+						},
+						{
+							Range: hcl.Range{
+								Filename: "main.tf",
+								Start:    hcl.Pos{Line: 15, Column: 1, Byte: 186},
+								End:      hcl.Pos{Line: 15, Column: 1, Byte: 186},
+							},
+							// heredoc content keeps its indentation
+							NewText: "\nlocals {\n  tags = merge(\n    var.tags,\n    {\n      Doc = <<EOT\n        indented\nEOT\n    },\n  )\n}\n",
+							Snippet: "\nlocals {\n  tags = merge(\n    var.tags,\n    {\n      Doc = <<EOT\n        indented\nEOT\n    },\n  )\n}\n",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			files := make(map[string]*hcl.File)
+			for name, src := range tc.files {
+				files[name], _ = parseFile(t, name, src)
+			}
+			reader := testPathReader{
+				modPath: &decoder.PathContext{
+					Files:            files,
+					ReferenceTargets: tc.targets,
+				},
+			}
+
+			extracted, err := ExtractToLocal(reader, modPath, "main.tf", tc.rng)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.expectedLocal, extracted); diff != "" {
+				t.Fatalf("unexpected extracted local: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,578 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl-lang/decoder"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/reference"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+// extractedModulesDir is the directory, relative to the module,
+// where new modules are created when extracting blocks.
+const extractedModulesDir = "modules"
+
+// ExtractedModule represents blocks extracted into a new child module
+type ExtractedModule struct {
+	Name string
+	// Dir is the absolute path of the new module
+	Dir string
+	// Files maps names of files to create in Dir to their content
+	Files map[string]string
+	// Edits to apply to existing files of the parent module
+	Edits FileEdits
+}
+
+// textReplacement represents a change of the source of a moved block
+type textReplacement struct {
+	Range hcl.Range
+	Text  string
+}
+
+// moduleReference represents a reference which needs to be rewritten
+// when blocks are moved to another module.
+type moduleReference struct {
+	// Range is the range of the referenced object within the reference,
+	// e.g. aws_vpc.main within aws_vpc.main.id
+	Range hcl.Range
+	// Object is the address of the referenced object
+	Object lang.Address
+}
+
+// ExtractToModule returns changes which move resource and data blocks
+// within the given range into a new module in the modules directory.
+//
+// Any references from the moved blocks to other objects become input
+// variables and any references from the rest of the module to the moved
+// blocks become outputs of the new module. The moved blocks are replaced
+// with a module block, followed by moved blocks for each resource, such that
+// no resources get replaced on the next apply. Provider configurations
+// and dependencies of the moved blocks are declared by the module block.
+//
+// It returns nil if there are no blocks to extract within the range,
+// or if the blocks use different configurations of the same provider.
+func ExtractToModule(pr decoder.PathReader, fsys fs.StatFS, path lang.Path, filename string, rng hcl.Range) (*ExtractedModule, error) {
+	pathCtx, err := pr.PathContext(path)
+	if err != nil {
+		return nil, err
+	}
+
+	f, ok := pathCtx.Files[filename]
+	if !ok {
+		return nil, fmt.Errorf("%s: file not found", filename)
+	}
+	body, ok := f.Body.(*hclsyntax.Body)
+	if !ok {
+		// JSON is not supported
+		return nil, nil
+	}
+
+	blocks := make([]*hclsyntax.Block, 0)
+	for _, block := range body.Blocks {
+		if (block.Type == "resource" || block.Type == "data") && len(block.Labels) == 2 &&
+			block.Range().Overlaps(rng) {
+			blocks = append(blocks, block)
+		}
+	}
+	if len(blocks) == 0 {
+		return nil, nil
+	}
+
+	providers, ok := blockProviders(f.Bytes, blocks)
+	if !ok {
+		// Passing more than one configuration of a provider
+		// would require configuration aliases in the new module
+		return nil, nil
+	}
+
+	name := uniqueModuleName(pathCtx, fsys, path.Path, blocks[0].Labels[1])
+	modDir := filepath.Join(path.Path, extractedModulesDir, name)
+
+	inputs := make(map[string]lang.Address)
+	outputs := make(map[string]lang.Address)
+	replacements := make(map[*hclsyntax.Block][]textReplacement)
+	dependsOn := make([]string, 0)
+	edits := make(FileEdits)
+
+	for _, block := range blocks {
+		if attr, ok := block.Body.Attributes["provider"]; ok && providers[providerLocalName(f.Bytes, block)] != "" {
+			// The provider is passed to the module instead
+			replacements[block] = append(replacements[block], textReplacement{
+				Range: WholeLinesRange(f.Bytes, attr.SrcRange),
+			})
+		}
+
+		attr, inner, outer := splitDependsOn(f.Bytes, block, blocks)
+		if len(outer) == 0 {
+			continue
+		}
+		// Dependencies outside of the new module become
+		// dependencies of the module as a whole
+		for _, dep := range outer {
+			if !slices.Contains(dependsOn, dep) {
+				dependsOn = append(dependsOn, dep)
+			}
+		}
+		replacement := textReplacement{Range: WholeLinesRange(f.Bytes, attr.SrcRange)}
+		if len(inner) > 0 {
+			replacement = textReplacement{
+				Range: attr.Expr.Range(),
+				Text:  fmt.Sprintf("[%s]", strings.Join(inner, ", ")),
+			}
+		}
+		replacements[block] = append(replacements[block], replacement)
+	}
+
+	for _, ref := range moduleReferences(pathCtx) {
+		block, isInner := blockContaining(blocks, ref.Range)
+		if isInner && isMetaArgumentRange(block, ref.Range) {
+			// Handled above
+			continue
+		}
+
+		if selected, ok := selectedObject(blocks, ref.Object); ok {
+			if isInner {
+				// References between the moved blocks stay intact
+				continue
+			}
+			if isDependsOnRange(pathCtx.Files, ref.Range) {
+				// Dependencies can only refer to the module as a whole
+				edits.Add(path.Path, ref.Range, fmt.Sprintf("module.%s", name))
+				continue
+			}
+			outName := objectName(selected)
+			outputs[outName] = selected
+			edits.Add(path.Path, ref.Range, fmt.Sprintf("module.%s.%s", name, outName))
+			continue
+		}
+
+		if isInner {
+			replacements[block] = append(replacements[block], textReplacement{
+				Range: ref.Range,
+				Text:  "var." + inputName(inputs, ref.Object),
+			})
+		}
+	}
+
+	files := make(map[string]string)
+	blockTexts := make([]string, 0, len(blocks))
+	for _, block := range blocks {
+		blockTexts = append(blockTexts, replaceInBlock(f.Bytes, block, replacements[block]))
+	}
+	// Removing or replacing attributes may break their alignment
+	files["main.tf"] = string(hclwrite.Format([]byte(strings.Join(blockTexts, "\n\n") + "\n")))
+
+	if len(inputs) > 0 {
+		variables := make([]string, 0, len(inputs))
+		for _, inName := range sortedKeys(inputs) {
+			variables = append(variables, fmt.Sprintf("variable %q {\n}\n", inName))
+		}
+		files["variables.tf"] = strings.Join(variables, "\n")
+	}
+	if len(outputs) > 0 {
+		outs := make([]string, 0, len(outputs))
+		for _, outName := range sortedKeys(outputs) {
+			outs = append(outs, fmt.Sprintf("output %q {\n  value = %s\n}\n", outName, outputs[outName]))
+		}
+		files["outputs.tf"] = strings.Join(outs, "\n")
+	}
+
+	replacement := moduleBlock(name, providers, inputs, dependsOn)
+	for _, block := range blocks {
+		if block.Type != "resource" {
+			continue
+		}
+		addr := fmt.Sprintf("%s.%s", block.Labels[0], block.Labels[1])
+		replacement += fmt.Sprintf("\nmoved {\n  from = %s\n  to   = module.%s.%s\n}\n", addr, name, addr)
+	}
+	for i, block := range blocks {
+		text := ""
+		if i == 0 {
+			text = replacement
+		}
+		edits.Add(path.Path, WholeLinesRange(f.Bytes, block.Range()), text)
+	}
+
+	for path := range edits {
+		sort.SliceStable(edits[path], func(i, j int) bool {
+			return edits[path][i].Range.Start.Byte < edits[path][j].Range.Start.Byte
+		})
+	}
+
+	return &ExtractedModule{
+		Name:  name,
+		Dir:   modDir,
+		Files: files,
+		Edits: edits,
+	}, nil
+}
+
+// moduleReferences returns references to objects declared within the module,
+// or to input variables and local values, along with the range of the object
+// within each reference.
+func moduleReferences(pathCtx *decoder.PathContext) []moduleReference {
+	refs := make([]moduleReference, 0)
+	seen := make(map[hcl.Range]struct{})
+
+	for _, origin := range pathCtx.ReferenceOrigins {
+		localOrigin, ok := origin.(reference.LocalOrigin)
+		if !ok {
+			continue
+		}
+		if _, ok := seen[localOrigin.Range]; ok {
+			continue
+		}
+
+		objLen := objectAddressLength(localOrigin.Addr)
+		if objLen == 0 || len(localOrigin.Addr) < objLen {
+			continue
+		}
+		// Avoid rewriting anything which only resembles a reference,
+		// such as symbols of for expressions
+		if _, ok := pathCtx.ReferenceTargets.Match(localOrigin); !ok {
+			continue
+		}
+
+		f, ok := pathCtx.Files[localOrigin.Range.Filename]
+		if !ok {
+			continue
+		}
+		rng, ok := objectReferenceRange(f, localOrigin.Range, objLen)
+		if !ok {
+			continue
+		}
+
+		seen[localOrigin.Range] = struct{}{}
+		refs = append(refs, moduleReference{
+			Range:  rng,
+			Object: localOrigin.Addr.FirstSteps(uint(objLen)),
+		})
+	}
+
+	return refs
+}
+
+// objectReferenceRange returns the range of the referenced object
+// within the reference at the given range, e.g. range of aws_vpc.main
+// within aws_vpc.main.id, where objLen is the number of address steps
+// identifying the object (see [objectAddressLength]).
+func objectReferenceRange(f *hcl.File, rng hcl.Range, objLen int) (hcl.Range, bool) {
+	if rng.End.Byte > len(f.Bytes) || rng.Start.Byte > rng.End.Byte {
+		return hcl.Range{}, false
+	}
+	traversal, diags := hclsyntax.ParseTraversalAbs(rng.SliceBytes(f.Bytes), rng.Filename, rng.Start)
+	if diags.HasErrors() || len(traversal) < objLen {
+		return hcl.Range{}, false
+	}
+	return hcl.RangeBetween(traversal[0].SourceRange(), traversal[objLen-1].SourceRange()), true
+}
+
+// objectAddressLength returns the number of address steps
+// which identify the referenced object, e.g. 2 for aws_vpc.main.id,
+// or 0 if the reference does not point to an object which can be
+// passed between modules.
+func objectAddressLength(addr lang.Address) int {
+	root, ok := addr[0].(lang.RootStep)
+	if !ok {
+		return 0
+	}
+
+	switch root.Name {
+	case "path", "terraform", "count", "each", "self":
+		return 0
+	case "data":
+		return 3
+	}
+	return 2
+}
+
+func blockAddress(block *hclsyntax.Block) lang.Address {
+	if block.Type == "data" {
+		return lang.Address{
+			lang.RootStep{Name: "data"},
+			lang.AttrStep{Name: block.Labels[0]},
+			lang.AttrStep{Name: block.Labels[1]},
+		}
+	}
+	return lang.Address{
+		lang.RootStep{Name: block.Labels[0]},
+		lang.AttrStep{Name: block.Labels[1]},
+	}
+}
+
+func selectedObject(blocks []*hclsyntax.Block, addr lang.Address) (lang.Address, bool) {
+	for _, block := range blocks {
+		blockAddr := blockAddress(block)
+		if blockAddr.Equals(addr) {
+			return blockAddr, true
+		}
+	}
+	return nil, false
+}
+
+func blockContaining(blocks []*hclsyntax.Block, rng hcl.Range) (*hclsyntax.Block, bool) {
+	for _, block := range blocks {
+		blockRng := block.Range()
+		if blockRng.Filename == rng.Filename &&
+			blockRng.Start.Byte <= rng.Start.Byte && rng.End.Byte <= blockRng.End.Byte {
+			return block, true
+		}
+	}
+	return nil, false
+}
+
+// objectName turns an address into a name of a variable or output,
+// e.g. aws_vpc_main for aws_vpc.main, region for var.region
+// or local_region for local.region
+func objectName(addr lang.Address) string {
+	steps := make([]string, 0, len(addr))
+	for _, step := range addr {
+		steps = append(steps, addrStepName(step))
+	}
+	if steps[0] == "var" {
+		steps = steps[1:]
+	}
+	return strings.Join(steps, "_")
+}
+
+// inputName returns the name of the input variable for the given
+// object, declaring a new one if the object isn't passed yet.
+// A numeric suffix is added if the name is already taken
+// by another object, e.g. for var.local_region and local.region.
+func inputName(inputs map[string]lang.Address, addr lang.Address) string {
+	for name, inAddr := range inputs {
+		if inAddr.Equals(addr) {
+			return name
+		}
+	}
+
+	name := objectName(addr)
+	candidate := name
+	for i := 2; ; i++ {
+		if _, ok := inputs[candidate]; !ok {
+			break
+		}
+		candidate = fmt.Sprintf("%s_%d", name, i)
+	}
+	inputs[candidate] = addr
+	return candidate
+}
+
+// providerLocalName returns the local name of the provider used by the block,
+// i.e. the name in the provider attribute if there is one, or the prefix
+// of the resource type otherwise, e.g. aws for aws_instance.
+func providerLocalName(src []byte, block *hclsyntax.Block) string {
+	if attr, ok := block.Body.Attributes["provider"]; ok {
+		config := strings.TrimSpace(string(attr.Expr.Range().SliceBytes(src)))
+		localName, _, _ := strings.Cut(config, ".")
+		return localName
+	}
+	localName, _, _ := strings.Cut(block.Labels[0], "_")
+	return localName
+}
+
+// blockProviders returns the provider configurations which need to be passed
+// to the new module, mapped by local name, e.g. aws.west for aws. Blocks using
+// default configurations inherit them from the parent module, so these are
+// mapped to an empty string.
+//
+// It returns false if the blocks use different configurations of one provider.
+func blockProviders(src []byte, blocks []*hclsyntax.Block) (map[string]string, bool) {
+	providers := make(map[string]string)
+	for _, block := range blocks {
+		localName := providerLocalName(src, block)
+		config := ""
+		if attr, ok := block.Body.Attributes["provider"]; ok {
+			config = strings.TrimSpace(string(attr.Expr.Range().SliceBytes(src)))
+			if config == localName {
+				config = ""
+			}
+		}
+
+		if existing, ok := providers[localName]; ok && existing != config {
+			return nil, false
+		}
+		providers[localName] = config
+	}
+	return providers, true
+}
+
+// splitDependsOn returns the depends_on attribute of the block, if any,
+// along with the source of its entries which refer to any of the given
+// blocks and the source of entries which refer to other objects.
+func splitDependsOn(src []byte, block *hclsyntax.Block, blocks []*hclsyntax.Block) (*hclsyntax.Attribute, []string, []string) {
+	attr, ok := block.Body.Attributes["depends_on"]
+	if !ok {
+		return nil, nil, nil
+	}
+	tuple, ok := attr.Expr.(*hclsyntax.TupleConsExpr)
+	if !ok {
+		return attr, nil, nil
+	}
+
+	inner := make([]string, 0)
+	outer := make([]string, 0)
+	for _, expr := range tuple.Exprs {
+		text := strings.TrimSpace(string(expr.Range().SliceBytes(src)))
+		traversal, diags := hcl.AbsTraversalForExpr(expr)
+		if diags.HasErrors() {
+			outer = append(outer, text)
+			continue
+		}
+		addr, err := lang.TraversalToAddress(traversal)
+		if err != nil {
+			outer = append(outer, text)
+			continue
+		}
+		objLen := objectAddressLength(addr)
+		if objLen > 0 && len(addr) >= objLen {
+			if _, ok := selectedObject(blocks, addr.FirstSteps(uint(objLen))); ok {
+				inner = append(inner, text)
+				continue
+			}
+		}
+		outer = append(outer, text)
+	}
+	return attr, inner, outer
+}
+
+// isMetaArgumentRange checks whether the range is within the provider
+// or depends_on attribute of the block, which are not passed as inputs.
+func isMetaArgumentRange(block *hclsyntax.Block, rng hcl.Range) bool {
+	for _, name := range []string{"provider", "depends_on"} {
+		if attr, ok := block.Body.Attributes[name]; ok && rangeContains(attr.SrcRange, rng) {
+			return true
+		}
+	}
+	return false
+}
+
+// isDependsOnRange checks whether the range is within
+// a depends_on attribute of any top-level block.
+func isDependsOnRange(files map[string]*hcl.File, rng hcl.Range) bool {
+	f, ok := files[rng.Filename]
+	if !ok {
+		return false
+	}
+	body, ok := f.Body.(*hclsyntax.Body)
+	if !ok {
+		return false
+	}
+	for _, block := range body.Blocks {
+		if attr, ok := block.Body.Attributes["depends_on"]; ok && rangeContains(attr.SrcRange, rng) {
+			return true
+		}
+	}
+	return false
+}
+
+func rangeContains(outer, inner hcl.Range) bool {
+	return outer.Filename == inner.Filename &&
+		outer.Start.Byte <= inner.Start.Byte && inner.End.Byte <= outer.End.Byte
+}
+
+// replaceInBlock returns the source of the block with the given
+// replacements, such as references replaced by input variables.
+func replaceInBlock(src []byte, block *hclsyntax.Block, replacements []textReplacement) string {
+	blockRng := block.Range()
+
+	sort.Slice(replacements, func(i, j int) bool {
+		return replacements[i].Range.Start.Byte > replacements[j].Range.Start.Byte
+	})
+
+	text := string(blockRng.SliceBytes(src))
+	for _, r := range replacements {
+		start := r.Range.Start.Byte - blockRng.Start.Byte
+		end := r.Range.End.Byte - blockRng.Start.Byte
+		text = text[:start] + r.Text + text[end:]
+	}
+	return text
+}
+
+// moduleBlock returns the module block calling the extracted module,
+// with arguments aligned as formatted by tofu fmt.
+func moduleBlock(name string, providers map[string]string, inputs map[string]lang.Address, dependsOn []string) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "module %q {\n", name)
+	fmt.Fprintf(&sb, "  source = %q\n", "./"+extractedModulesDir+"/"+name)
+
+	providerNames := make([]string, 0, len(providers))
+	for localName, config := range providers {
+		if config != "" {
+			providerNames = append(providerNames, localName)
+		}
+	}
+	sort.Strings(providerNames)
+	if len(providerNames) > 0 {
+		sb.WriteString("\n  providers = {\n")
+		for _, localName := range providerNames {
+			fmt.Fprintf(&sb, "    %-*s = %s\n", maxLen(providerNames), localName, providers[localName])
+		}
+		sb.WriteString("  }\n")
+	}
+
+	names := sortedKeys(inputs)
+	if len(names) > 0 {
+		sb.WriteString("\n")
+		for _, inName := range names {
+			fmt.Fprintf(&sb, "  %-*s = %s\n", maxLen(names), inName, inputs[inName])
+		}
+	}
+
+	if len(dependsOn) > 0 {
+		fmt.Fprintf(&sb, "\n  depends_on = [%s]\n", strings.Join(dependsOn, ", "))
+	}
+
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+func maxLen(names []string) int {
+	width := 0
+	for _, name := range names {
+		width = max(width, len(name))
+	}
+	return width
+}
+
+// uniqueModuleName returns the given name, or the name with a numeric
+// suffix if a module call or directory of the same name already exists.
+func uniqueModuleName(pathCtx *decoder.PathContext, fsys fs.StatFS, modPath, name string) string {
+	isTaken := func(name string) bool {
+		addr := lang.Address{
+			lang.RootStep{Name: "module"},
+			lang.AttrStep{Name: name},
+		}
+		if len(targetsWithAddr(pathCtx.ReferenceTargets, addr)) > 0 {
+			return true
+		}
+		_, err := fsys.Stat(filepath.Join(modPath, extractedModulesDir, name))
+		return err == nil
+	}
+
+	candidate := name
+	for i := 2; isTaken(candidate); i++ {
+		candidate = fmt.Sprintf("%s_%d", name, i)
+	}
+	return candidate
+}
+
+func sortedKeys(m map[string]lang.Address) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,476 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl-lang/decoder"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/reference"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestExtractToModule(t *testing.T) {
+	modPath := lang.Path{Path: "root", LanguageID: "opentofu"}
+	f, body := parseFile(t, "main.tf", `variable "region" {
+}
+
+resource "aws_vpc" "main" {
+  cidr_block = "10.0.0.0/16"
+}
+
+resource "aws_instance" "web" {
+  ami    = var.region
+  vpc_id = aws_vpc.main.id
+
+  tags = {
+    Name = "web-${count.index}"
+  }
+}
+
+output "ip" {
+  value = aws_instance.web.public_ip
+}
+`)
+
+	reader := extractModuleReader(t, modPath, f, body)
+
+	// Selection within the aws_instance block
+	rng := hcl.Range{
+		Filename: "main.tf",
+		Start:    hcl.Pos{Line: 9, Column: 3, Byte: 117},
+		End:      hcl.Pos{Line: 10, Column: 3, Byte: 139},
+	}
+	extracted, err := ExtractToModule(reader, fstest.MapFS{}, modPath, "main.tf", rng)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if extracted == nil {
+		t.Fatal("expected extracted module")
+	}
+
+	if extracted.Name != "web" {
+		t.Fatalf("unexpected module name: %q", extracted.Name)
+	}
+	if extracted.Dir != filepath.Join("root", "modules", "web") {
+		t.Fatalf("unexpected module directory: %q", extracted.Dir)
+	}
+
+	expectedFiles := map[string]string{
+		"main.tf": `resource "aws_instance" "web" {
+  ami    = var.region
+  vpc_id = var.aws_vpc_main.id
+
+  tags = {
+    Name = "web-${count.index}"
+  }
+}
+`,
+		"variables.tf": `variable "aws_vpc_main" {
+}
+
+variable "region" {
+}
+`,
+		"outputs.tf": `output "aws_instance_web" {
+  value = aws_instance.web
+}
+`,
+	}
+	if diff := cmp.Diff(expectedFiles, extracted.Files); diff != "" {
+		t.Fatalf("unexpected files: %s", diff)
+	}
+
+	expectedEdits := FileEdits{
+		filepath.Join("root", "main.tf"): {
+			{
+				Range: hcl.Range{
+					Filename: "main.tf",
+					Start:    hcl.Pos{Line: 8, Column: 1, Byte: 83},
+					End:      hcl.Pos{Line: 16, Column: 1, Byte: 214},
+				},
+				NewText: `module "web" {
+  source = "./modules/web"
+
+  aws_vpc_main = aws_vpc.main
+  region       = var.region
+}
+
+moved {
+  from = aws_instance.web
+  to   = module.web.aws_instance.web
+}
+`,
+			},
+			{
+				Range: hcl.Range{
+					Filename: "main.tf",
+					Start:    hcl.Pos{Line: 18, Column: 11, Byte: 239},
+					End:      hcl.Pos{Line: 18, Column: 27, Byte: 255},
+				},
+				NewText: "module.web.aws_instance_web",
+			},
+		},
+	}
+	for path, edits := range expectedEdits {
+		for i := range edits {
+			edits[i].Snippet = edits[i].NewText
+		}
+		expectedEdits[path] = edits
+	}
+	if diff := cmp.Diff(expectedEdits, extracted.Edits); diff != "" {
+		t.Fatalf("unexpected edits: %s", diff)
+	}
+}
+
+func TestExtractToModule_noBlocks(t *testing.T) {
+	modPath := lang.Path{Path: "root", LanguageID: "opentofu"}
+	f, _ := parseFile(t, "main.tf", `variable "region" {
+}
+`)
+	reader := testPathReader{
+		modPath: &decoder.PathContext{
+			Files: map[string]*hcl.File{"main.tf": f},
+		},
+	}
+
+	extracted, err := ExtractToModule(reader, fstest.MapFS{}, modPath, "main.tf", hcl.Range{
+		Filename: "main.tf",
+		Start:    hcl.InitialPos,
+		End:      hcl.Pos{Line: 2, Column: 2, Byte: 21},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if extracted != nil {
+		t.Fatalf("expected no extracted module, given %#v", extracted)
+	}
+}
+
+func TestExtractToModule_metaArguments(t *testing.T) {
+	modPath := lang.Path{Path: "root", LanguageID: "opentofu"}
+
+	testCases := []struct {
+		name           string
+		src            string
+		fsys           fstest.MapFS
+		expectedName   string
+		expectedFiles  map[string]string
+		expectedModule string
+	}{
+		{
+			"colliding input names",
+			`variable "local_region" {
+}
+
+locals {
+  region = "eu-west-1"
+}
+
+resource "aws_instance" "web" {
+  ami = "${var.local_region}-${local.region}"
+}
+`,
+			fstest.MapFS{},
+			"web",
+			map[string]string{
+				"main.tf": `resource "aws_instance" "web" {
+  ami = "${var.local_region}-${var.local_region_2}"
+}
+`,
+				"variables.tf": `variable "local_region" {
+}
+
+variable "local_region_2" {
+}
+`,
+			},
+			`module "web" {
+  source = "./modules/web"
+
+  local_region   = var.local_region
+  local_region_2 = local.region
+}
+
+moved {
+  from = aws_instance.web
+  to   = module.web.aws_instance.web
+}
+`,
+		},
+		{
+			"provider configuration",
+			`provider "aws" {
+  alias = "west"
+}
+
+resource "aws_instance" "web" {
+  provider = aws.west
+  ami      = "ami-123"
+}
+`,
+			fstest.MapFS{},
+			"web",
+			map[string]string{
+				"main.tf": `resource "aws_instance" "web" {
+  ami = "ami-123"
+}
+`,
+			},
+			`module "web" {
+  source = "./modules/web"
+
+  providers = {
+    aws = aws.west
+  }
+}
+
+moved {
+  from = aws_instance.web
+  to   = module.web.aws_instance.web
+}
+`,
+		},
+		{
+			"dependencies outside of selection",
+			`resource "aws_iam_role" "web" {
+}
+
+resource "aws_instance" "web" {
+  ami        = "ami-123"
+  depends_on = [aws_iam_role.web]
+}
+`,
+			fstest.MapFS{},
+			"web",
+			map[string]string{
+				"main.tf": `resource "aws_instance" "web" {
+  ami = "ami-123"
+}
+`,
+			},
+			`module "web" {
+  source = "./modules/web"
+
+  depends_on = [aws_iam_role.web]
+}
+
+moved {
+  from = aws_instance.web
+  to   = module.web.aws_instance.web
+}
+`,
+		},
+		{
+			"existing module directory",
+			`resource "aws_instance" "web" {
+  ami = "ami-123"
+}
+`,
+			fstest.MapFS{
+				"root/modules/web/main.tf": &fstest.MapFile{},
+			},
+			"web_2",
+			map[string]string{
+				"main.tf": `resource "aws_instance" "web" {
+  ami = "ami-123"
+}
+`,
+			},
+			`module "web_2" {
+  source = "./modules/web_2"
+}
+
+moved {
+  from = aws_instance.web
+  to   = module.web_2.aws_instance.web
+}
+`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f, body := parseFile(t, "main.tf", tc.src)
+			reader := extractModuleReader(t, modPath, f, body)
+
+			// Selection within the aws_instance block
+			start := bytes.Index(f.Bytes, []byte(`resource "aws_instance"`))
+			pos := hcl.Pos{Line: bytes.Count(f.Bytes[:start], []byte{'\n'}) + 1, Column: 1, Byte: start}
+			extracted, err := ExtractToModule(reader, tc.fsys, modPath, "main.tf", hcl.Range{
+				Filename: "main.tf",
+				Start:    pos,
+				End:      hcl.Pos{Line: pos.Line, Column: pos.Column + 1, Byte: pos.Byte + 1},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if extracted == nil {
+				t.Fatal("expected extracted module")
+			}
+
+			if extracted.Name != tc.expectedName {
+				t.Fatalf("unexpected module name: %q", extracted.Name)
+			}
+			if diff := cmp.Diff(tc.expectedFiles, extracted.Files); diff != "" {
+				t.Fatalf("unexpected files: %s", diff)
+			}
+			edits := extracted.Edits[filepath.Join("root", "main.tf")]
+			if len(edits) != 1 {
+				t.Fatalf("expected 1 edit, given: %#v", edits)
+			}
+			if diff := cmp.Diff(tc.expectedModule, edits[0].NewText); diff != "" {
+				t.Fatalf("unexpected module block: %s", diff)
+			}
+		})
+	}
+}
+
+func TestExtractToModule_dependencyOnSelection(t *testing.T) {
+	modPath := lang.Path{Path: "root", LanguageID: "opentofu"}
+	f, body := parseFile(t, "main.tf", `resource "aws_instance" "web" {
+  ami = "ami-123"
+}
+
+output "ip" {
+  value      = "127.0.0.1"
+  depends_on = [aws_instance.web]
+}
+`)
+	reader := extractModuleReader(t, modPath, f, body)
+
+	extracted, err := ExtractToModule(reader, fstest.MapFS{}, modPath, "main.tf", hcl.Range{
+		Filename: "main.tf",
+		Start:    hcl.InitialPos,
+		End:      hcl.Pos{Line: 1, Column: 2, Byte: 1},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if extracted == nil {
+		t.Fatal("expected extracted module")
+	}
+
+	if _, ok := extracted.Files["outputs.tf"]; ok {
+		t.Fatal("expected no outputs for dependencies")
+	}
+	edits := extracted.Edits[filepath.Join("root", "main.tf")]
+	if len(edits) != 2 {
+		t.Fatalf("expected 2 edits, given: %#v", edits)
+	}
+	expectedEdit := lang.TextEdit{
+		Range: hcl.Range{
+			Filename: "main.tf",
+			Start:    hcl.Pos{Line: 7, Column: 17, Byte: 110},
+			End:      hcl.Pos{Line: 7, Column: 33, Byte: 126},
+		},
+		NewText: "module.web",
+		Snippet: "module.web",
+	}
+	if diff := cmp.Diff(expectedEdit, edits[1]); diff != "" {
+		t.Fatalf("unexpected edit: %s", diff)
+	}
+}
+
+func TestExtractToModule_differentProviderConfigurations(t *testing.T) {
+	modPath := lang.Path{Path: "root", LanguageID: "opentofu"}
+	f, body := parseFile(t, "main.tf", `resource "aws_instance" "web" {
+  provider = aws.west
+}
+
+resource "aws_instance" "db" {
+}
+`)
+	reader := extractModuleReader(t, modPath, f, body)
+
+	extracted, err := ExtractToModule(reader, fstest.MapFS{}, modPath, "main.tf", hcl.Range{
+		Filename: "main.tf",
+		Start:    hcl.InitialPos,
+		End:      hcl.Pos{Line: 6, Column: 1, Byte: 87},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if extracted != nil {
+		t.Fatalf("expected no extracted module, given %#v", extracted)
+	}
+}
+
+// extractModuleReader returns a reader for the module with the given file,
+// where every traversal is a reference origin and every resource, data
+// source, provider configuration, variable and local value is a target.
+func extractModuleReader(t *testing.T, modPath lang.Path, f *hcl.File, body *hclsyntax.Body) testPathReader {
+	origins := make(reference.Origins, 0)
+	hclsyntax.VisitAll(body, func(node hclsyntax.Node) hcl.Diagnostics {
+		expr, ok := node.(*hclsyntax.ScopeTraversalExpr)
+		if !ok {
+			return nil
+		}
+		addr, err := lang.TraversalToAddress(expr.Traversal)
+		if err != nil {
+			t.Fatal(err)
+		}
+		origins = append(origins, reference.LocalOrigin{
+			Addr:  addr,
+			Range: expr.Range(),
+			Constraints: reference.OriginConstraints{
+				{OfType: cty.DynamicPseudoType},
+			},
+		})
+		return nil
+	})
+
+	targets := make(reference.Targets, 0)
+	for _, block := range body.Blocks {
+		var addr lang.Address
+		switch block.Type {
+		case "resource", "data":
+			addr = blockAddress(block)
+		case "provider":
+			addr = lang.Address{lang.RootStep{Name: block.Labels[0]}}
+			if alias, ok := block.Body.Attributes["alias"]; ok {
+				value, _ := alias.Expr.Value(nil)
+				addr = append(addr, lang.AttrStep{Name: value.AsString()})
+			}
+		case "variable":
+			addr = lang.Address{
+				lang.RootStep{Name: "var"},
+				lang.AttrStep{Name: block.Labels[0]},
+			}
+		case "locals":
+			for name := range block.Body.Attributes {
+				targets = append(targets, reference.Target{
+					Addr: lang.Address{
+						lang.RootStep{Name: "local"},
+						lang.AttrStep{Name: name},
+					},
+					Type: cty.DynamicPseudoType,
+				})
+			}
+			continue
+		default:
+			continue
+		}
+		targets = append(targets, reference.Target{
+			Addr:     addr,
+			Type:     cty.DynamicPseudoType,
+			RangePtr: block.Range().Ptr(),
+		})
+	}
+
+	return testPathReader{
+		modPath: &decoder.PathContext{
+			Files:            map[string]*hcl.File{f.Body.MissingItemRange().Filename: f},
+			ReferenceOrigins: origins,
+			ReferenceTargets: targets,
+		},
+	}
+}
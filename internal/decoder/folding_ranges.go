@@ -0,0 +1,181 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+// FoldingRange represents a range of lines which can be folded
+type FoldingRange struct {
+	// StartLine is the line which stays visible when folded
+	StartLine int
+	// EndLine is the last folded line, which excludes
+	// any closing brackets or heredoc markers
+	EndLine   int
+	IsComment bool
+}
+
+// FoldingRanges returns ranges of blocks, multi-line objects and tuples,
+// heredoc templates and runs of comments within the file, either in
+// the native syntax or JSON. Lines start at 1, as in [hcl.Pos].
+func FoldingRanges(f *hcl.File) []FoldingRange {
+	fr := &foldingRanges{
+		ranges: make(map[int]FoldingRange),
+	}
+
+	if body, ok := f.Body.(*hclsyntax.Body); ok {
+		hclsyntax.VisitAll(body, func(node hclsyntax.Node) hcl.Diagnostics {
+			switch n := node.(type) {
+			case *hclsyntax.Block:
+				fr.addEnclosed(n.OpenBraceRange.Start.Line, n.CloseBraceRange.Start.Line)
+			case *hclsyntax.ObjectConsExpr:
+				fr.addEnclosed(n.SrcRange.Start.Line, n.SrcRange.End.Line)
+			case *hclsyntax.TupleConsExpr:
+				fr.addEnclosed(n.SrcRange.Start.Line, n.SrcRange.End.Line)
+			case *hclsyntax.ForExpr:
+				fr.addEnclosed(n.OpenRange.Start.Line, n.CloseRange.Start.Line)
+			}
+			return nil
+		})
+		fr.addTokenRanges(f.Bytes)
+	} else {
+		fr.addJSONRanges(f.Bytes)
+	}
+
+	return fr.sorted()
+}
+
+type foldingRanges struct {
+	// ranges by their start line, since clients
+	// can only fold one range per line
+	ranges map[int]FoldingRange
+}
+
+func (fr *foldingRanges) add(rng FoldingRange) {
+	if rng.EndLine <= rng.StartLine {
+		return
+	}
+	if existing, ok := fr.ranges[rng.StartLine]; ok && existing.EndLine >= rng.EndLine {
+		return
+	}
+	fr.ranges[rng.StartLine] = rng
+}
+
+// addEnclosed adds a range between the given lines of opening
+// and closing brackets, keeping the closing line visible.
+func (fr *foldingRanges) addEnclosed(openLine, closeLine int) {
+	fr.add(FoldingRange{
+		StartLine: openLine,
+		EndLine:   closeLine - 1,
+	})
+}
+
+// addTokenRanges adds ranges of heredoc templates and comments,
+// which are not represented in the syntax tree.
+func (fr *foldingRanges) addTokenRanges(src []byte) {
+	tokens, _ := hclsyntax.LexConfig(src, "", hcl.InitialPos)
+
+	var heredocStart int
+	var commentRun *FoldingRange
+	for i, token := range tokens {
+		switch token.Type {
+		case hclsyntax.TokenOHeredoc:
+			heredocStart = token.Range.Start.Line
+		case hclsyntax.TokenCHeredoc:
+			fr.addEnclosed(heredocStart, token.Range.Start.Line)
+		case hclsyntax.TokenComment:
+			// Line comments include the trailing newline
+			endLine := token.Range.End.Line
+			if bytes.HasSuffix(token.Bytes, []byte("\n")) {
+				endLine--
+			}
+			isLeading := i == 0 || tokens[i-1].Range.End.Line < token.Range.Start.Line ||
+				tokens[i-1].Type == hclsyntax.TokenComment || tokens[i-1].Type == hclsyntax.TokenNewline
+
+			if commentRun != nil && isLeading && token.Range.Start.Line == commentRun.EndLine+1 {
+				commentRun.EndLine = endLine
+				continue
+			}
+			if commentRun != nil {
+				fr.add(*commentRun)
+				commentRun = nil
+			}
+			if isLeading {
+				commentRun = &FoldingRange{
+					StartLine: token.Range.Start.Line,
+					EndLine:   endLine,
+					IsComment: true,
+				}
+			}
+		case hclsyntax.TokenNewline:
+			// Blank lines are represented by newline tokens
+			// and separate runs of comments
+			if commentRun != nil && token.Range.Start.Line > commentRun.EndLine {
+				fr.add(*commentRun)
+				commentRun = nil
+			}
+		default:
+			if commentRun != nil {
+				fr.add(*commentRun)
+				commentRun = nil
+			}
+		}
+	}
+	if commentRun != nil {
+		fr.add(*commentRun)
+	}
+}
+
+// addJSONRanges adds ranges of multi-line objects and arrays
+func (fr *foldingRanges) addJSONRanges(src []byte) {
+	line := 1
+	openLines := make([]int, 0)
+	inString, escaped := false, false
+
+	for _, b := range src {
+		if b == '\n' {
+			line++
+		}
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case b == '\\':
+				escaped = true
+			case b == '"':
+				inString = false
+			}
+			continue
+		}
+
+		switch b {
+		case '"':
+			inString = true
+		case '{', '[':
+			openLines = append(openLines, line)
+		case '}', ']':
+			if len(openLines) == 0 {
+				continue
+			}
+			fr.addEnclosed(openLines[len(openLines)-1], line)
+			openLines = openLines[:len(openLines)-1]
+		}
+	}
+}
+
+func (fr *foldingRanges) sorted() []FoldingRange {
+	ranges := make([]FoldingRange, 0, len(fr.ranges))
+	for _, rng := range fr.ranges {
+		ranges = append(ranges, rng)
+	}
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i].StartLine < ranges[j].StartLine
+	})
+	return ranges
+}
@@ -0,0 +1,158 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/json"
+)
+
+func TestFoldingRanges(t *testing.T) {
+	testCases := []struct {
+		name           string
+		filename       string
+		src            string
+		expectedRanges []FoldingRange
+	}{
+		{
+			"single-line constructs",
+			"main.tf",
+			`# comment
+locals { a = [1, 2] }
+variable "foo" {}
+`,
+			[]FoldingRange{},
+		},
+		{
+			"nested blocks",
+			"main.tf",
+			`resource "aws_security_group" "example" {
+  name = "example"
+
+  dynamic "ingress" {
+    for_each = var.ports
+    content {
+      from_port = ingress.value
+    }
+  }
+}
+`,
+			[]FoldingRange{
+				{StartLine: 1, EndLine: 9},
+				{StartLine: 4, EndLine: 8},
+				{StartLine: 6, EndLine: 7},
+			},
+		},
+		{
+			"objects and tuples",
+			"main.tf",
+			`locals {
+  tags = {
+    Name = "example"
+  }
+  zones = [
+    "a",
+    "b",
+  ]
+  names = [for z in local.zones :
+    upper(z)
+  ]
+}
+`,
+			[]FoldingRange{
+				{StartLine: 1, EndLine: 11},
+				{StartLine: 2, EndLine: 3},
+				{StartLine: 5, EndLine: 7},
+				{StartLine: 9, EndLine: 10},
+			},
+		},
+		{
+			"heredoc",
+			"main.tf",
+			`output "policy" {
+  value = <<-EOT
+    {
+      "Version": "2012-10-17"
+    }
+  EOT
+}
+`,
+			[]FoldingRange{
+				{StartLine: 1, EndLine: 6},
+				{StartLine: 2, EndLine: 5},
+			},
+		},
+		{
+			"comments",
+			"main.tf",
+			`# first
+# second
+// third
+
+# separate
+# run
+variable "foo" { # trailing
+  # inside
+  # block
+  default = 1 # trailing
+  # after
+}
+
+/*
+  block
+*/
+`,
+			[]FoldingRange{
+				{StartLine: 1, EndLine: 3, IsComment: true},
+				{StartLine: 5, EndLine: 6, IsComment: true},
+				{StartLine: 7, EndLine: 11},
+				{StartLine: 8, EndLine: 9, IsComment: true},
+				{StartLine: 14, EndLine: 16, IsComment: true},
+			},
+		},
+		{
+			"json",
+			"main.tf.json",
+			`{
+  "variable": {
+    "foo": {
+      "default": "{ not an object ["
+    }
+  },
+  "locals": { "a": [1, 2] }
+}
+`,
+			[]FoldingRange{
+				{StartLine: 1, EndLine: 7},
+				{StartLine: 2, EndLine: 5},
+				{StartLine: 3, EndLine: 4},
+			},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%d-%s", i, tc.name), func(t *testing.T) {
+			var f *hcl.File
+			var diags hcl.Diagnostics
+			if tc.filename == "main.tf.json" {
+				f, diags = json.Parse([]byte(tc.src), tc.filename)
+			} else {
+				f, diags = hclsyntax.ParseConfig([]byte(tc.src), tc.filename, hcl.InitialPos)
+			}
+			if diags.HasErrors() {
+				t.Fatal(diags)
+			}
+
+			ranges := FoldingRanges(f)
+			if diff := cmp.Diff(tc.expectedRanges, ranges); diff != "" {
+				t.Fatalf("unexpected folding ranges: %s", diff)
+			}
+		})
+	}
+}
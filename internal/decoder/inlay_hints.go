@@ -0,0 +1,116 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/hashicorp/hcl-lang/decoder"
+	"github.com/hashicorp/hcl-lang/reference"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+// maxInlayHintLength is the number of characters of a value
+// displayed in a hint, before it gets truncated
+const maxInlayHintLength = 40
+
+// InlayHint represents a label displayed inline at a position in a file
+type InlayHint struct {
+	Pos     hcl.Pos
+	Label   string
+	Tooltip string
+}
+
+// VariableValue represents the value an input variable resolves to
+type VariableValue struct {
+	// Value is the value formatted as an HCL expression
+	Value string
+	// Description explains where the value comes from,
+	// e.g. a variable definitions file
+	Description string
+}
+
+// InlayHints returns hints within the given range of the file, showing the
+// values of referenced input variables and installed versions of modules.
+//
+// values maps names of input variables to their values and moduleVersions
+// maps names of module calls to versions of the installed modules.
+func InlayHints(pathCtx *decoder.PathContext, filename string, rng hcl.Range, values map[string]VariableValue, moduleVersions map[string]string) []InlayHint {
+	hints := make([]InlayHint, 0)
+	inRange := func(pos hcl.Pos) bool {
+		return rng.Start.Byte <= pos.Byte && pos.Byte <= rng.End.Byte
+	}
+
+	seen := make(map[hcl.Range]struct{})
+	for _, origin := range pathCtx.ReferenceOrigins {
+		localOrigin, ok := origin.(reference.LocalOrigin)
+		if !ok || localOrigin.Range.Filename != filename || !inRange(localOrigin.Range.End) {
+			continue
+		}
+		// Only references to whole variables, i.e. not var.foo.bar
+		if len(localOrigin.Addr) != 2 || localOrigin.Addr[0].String() != "var" {
+			continue
+		}
+		if _, ok := seen[localOrigin.Range]; ok {
+			continue
+		}
+		seen[localOrigin.Range] = struct{}{}
+
+		value, ok := values[addrStepName(localOrigin.Addr[1])]
+		if !ok {
+			continue
+		}
+		hints = append(hints, InlayHint{
+			Pos:     localOrigin.Range.End,
+			Label:   "= " + shortValue(value.Value),
+			Tooltip: value.Description,
+		})
+	}
+
+	if f, ok := pathCtx.Files[filename]; ok {
+		if body, ok := f.Body.(*hclsyntax.Body); ok {
+			for _, block := range body.Blocks {
+				if block.Type != "module" || len(block.Labels) != 1 {
+					continue
+				}
+				v, ok := moduleVersions[block.Labels[0]]
+				if !ok {
+					continue
+				}
+				source, ok := block.Body.Attributes["source"]
+				if !ok || !inRange(source.Expr.Range().End) {
+					continue
+				}
+				hints = append(hints, InlayHint{
+					Pos:     source.Expr.Range().End,
+					Label:   "v" + v,
+					Tooltip: "Installed version",
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(hints, func(i, j int) bool {
+		return hints[i].Pos.Byte < hints[j].Pos.Byte
+	})
+
+	return hints
+}
+
+// shortValue turns a (possibly multi-line) value into a single line
+// and truncates it if it is too long
+func shortValue(value string) string {
+	lines := strings.Split(value, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+	value = strings.Join(lines, " ")
+	if utf8.RuneCountInString(value) <= maxInlayHintLength {
+		return value
+	}
+	return string([]rune(value)[:maxInlayHintLength-1]) + "…"
+}
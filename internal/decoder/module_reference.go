@@ -0,0 +1,124 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+// ModuleReference represents a reference from a module
+// to a declaration within a module it calls
+type ModuleReference struct {
+	// ModuleName is the name of the module call
+	ModuleName string
+	// BlockType is the type of the referenced block,
+	// i.e. either output or variable
+	BlockType string
+	// Name is the name of the output or variable
+	Name  string
+	Range hcl.Range
+}
+
+// Address returns the address of the referenced
+// output or variable within the called module
+func (ref ModuleReference) Address() lang.Address {
+	rootName := ref.BlockType
+	if ref.BlockType == "variable" {
+		rootName = "var"
+	}
+	return lang.Address{
+		lang.RootStep{Name: rootName},
+		lang.AttrStep{Name: ref.Name},
+	}
+}
+
+// moduleMetaArguments are arguments of module blocks,
+// which don't set any variables of the module
+var moduleMetaArguments = map[string]bool{
+	"source":     true,
+	"version":    true,
+	"count":      true,
+	"for_each":   true,
+	"providers":  true,
+	"depends_on": true,
+}
+
+// ModuleReferenceAtPos returns the reference at pos either to an output of
+// a called module, such as module.vpc.vpc_id, or to a variable of a called
+// module, as an argument in the module block. Only the native syntax is
+// supported.
+func ModuleReferenceAtPos(f *hcl.File, pos hcl.Pos) (ModuleReference, bool) {
+	body, ok := f.Body.(*hclsyntax.Body)
+	if !ok {
+		return ModuleReference{}, false
+	}
+
+	var ref ModuleReference
+	var found bool
+	hclsyntax.VisitAll(body, func(node hclsyntax.Node) hcl.Diagnostics {
+		if found {
+			return nil
+		}
+		expr, ok := node.(*hclsyntax.ScopeTraversalExpr)
+		if !ok || !expr.SrcRange.ContainsPos(pos) {
+			return nil
+		}
+		ref, found = moduleOutputReference(expr.Traversal)
+		return nil
+	})
+	if found {
+		return ref, true
+	}
+
+	for _, block := range body.Blocks {
+		if block.Type != "module" || len(block.Labels) != 1 || !block.Range().ContainsPos(pos) {
+			continue
+		}
+		for name, attr := range block.Body.Attributes {
+			if moduleMetaArguments[name] || !attr.NameRange.ContainsPos(pos) {
+				continue
+			}
+			return ModuleReference{
+				ModuleName: block.Labels[0],
+				BlockType:  "variable",
+				Name:       name,
+				Range:      attr.NameRange,
+			}, true
+		}
+	}
+
+	return ModuleReference{}, false
+}
+
+func moduleOutputReference(traversal hcl.Traversal) (ModuleReference, bool) {
+	if len(traversal) < 3 || traversal.RootName() != "module" {
+		return ModuleReference{}, false
+	}
+	moduleName, ok := traversal[1].(hcl.TraverseAttr)
+	if !ok {
+		return ModuleReference{}, false
+	}
+
+	// Skip any index of modules with count or for_each
+	rest := traversal[2:]
+	if _, ok := rest[0].(hcl.TraverseIndex); ok {
+		rest = rest[1:]
+	}
+	if len(rest) == 0 {
+		return ModuleReference{}, false
+	}
+	outputName, ok := rest[0].(hcl.TraverseAttr)
+	if !ok {
+		return ModuleReference{}, false
+	}
+
+	return ModuleReference{
+		ModuleName: moduleName.Name,
+		BlockType:  "output",
+		Name:       outputName.Name,
+		Range:      hcl.RangeBetween(traversal[0].SourceRange(), outputName.SrcRange),
+	}, true
+}
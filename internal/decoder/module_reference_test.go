@@ -0,0 +1,110 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+func TestModuleReferenceAtPos(t *testing.T) {
+	src := `module "vpc" {
+  source = "terraform-aws-modules/vpc/aws"
+  name   = "main"
+}
+
+output "vpc_id" {
+  value = module.vpc.vpc_id
+}
+
+output "subnet" {
+  value = module.vpc[0].subnets[1]
+}
+`
+	testCases := []struct {
+		name        string
+		pos         hcl.Pos
+		expectedRef *ModuleReference
+	}{
+		{
+			"meta argument",
+			hcl.Pos{Line: 2, Column: 4, Byte: 18},
+			nil,
+		},
+		{
+			"module input",
+			hcl.Pos{Line: 3, Column: 4, Byte: 61},
+			&ModuleReference{
+				ModuleName: "vpc",
+				BlockType:  "variable",
+				Name:       "name",
+				Range: hcl.Range{
+					Filename: "main.tf",
+					Start:    hcl.Pos{Line: 3, Column: 3, Byte: 60},
+					End:      hcl.Pos{Line: 3, Column: 7, Byte: 64},
+				},
+			},
+		},
+		{
+			"module output",
+			hcl.Pos{Line: 7, Column: 22, Byte: 118},
+			&ModuleReference{
+				ModuleName: "vpc",
+				BlockType:  "output",
+				Name:       "vpc_id",
+				Range: hcl.Range{
+					Filename: "main.tf",
+					Start:    hcl.Pos{Line: 7, Column: 11, Byte: 107},
+					End:      hcl.Pos{Line: 7, Column: 28, Byte: 124},
+				},
+			},
+		},
+		{
+			"indexed module output",
+			hcl.Pos{Line: 11, Column: 14, Byte: 159},
+			&ModuleReference{
+				ModuleName: "vpc",
+				BlockType:  "output",
+				Name:       "subnets",
+				Range: hcl.Range{
+					Filename: "main.tf",
+					Start:    hcl.Pos{Line: 11, Column: 11, Byte: 156},
+					End:      hcl.Pos{Line: 11, Column: 32, Byte: 177},
+				},
+			},
+		},
+		{
+			"outside of references",
+			hcl.Pos{Line: 6, Column: 2, Byte: 80},
+			nil,
+		},
+	}
+
+	f, diags := hclsyntax.ParseConfig([]byte(src), "main.tf", hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%d-%s", i, tc.name), func(t *testing.T) {
+			ref, ok := ModuleReferenceAtPos(f, tc.pos)
+			if tc.expectedRef == nil {
+				if ok {
+					t.Fatalf("expected no reference, given: %#v", ref)
+				}
+				return
+			}
+			if !ok {
+				t.Fatal("expected reference to be found")
+			}
+			if diff := cmp.Diff(*tc.expectedRef, ref); diff != "" {
+				t.Fatalf("unexpected reference: %s", diff)
+			}
+		})
+	}
+}
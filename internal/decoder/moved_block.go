@@ -0,0 +1,59 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl-lang/decoder"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+// MovedBlock returns edits which insert a moved block right after
+// the declaration of a renamed resource, so that the resource
+// doesn't get replaced on the next apply.
+//
+// It returns nil if the resource is not declared in the given file,
+// or if its block does not overlap with the given range.
+func MovedBlock(pr decoder.PathReader, path lang.Path, filename string, rng hcl.Range, from, to lang.Address) (FileEdits, error) {
+	pathCtx, err := pr.PathContext(path)
+	if err != nil {
+		return nil, err
+	}
+
+	f, ok := pathCtx.Files[filename]
+	if !ok {
+		return nil, fmt.Errorf("%s: file not found", filename)
+	}
+	body, ok := f.Body.(*hclsyntax.Body)
+	if !ok {
+		// JSON is not supported
+		return nil, nil
+	}
+
+	for _, block := range body.Blocks {
+		if block.Type != "resource" || len(block.Labels) != 2 || !blockAddress(block).Equals(to) {
+			continue
+		}
+		blockRng := block.Range()
+		if !blockRng.Overlaps(rng) && !blockRng.ContainsPos(rng.Start) {
+			return nil, nil
+		}
+
+		text := fmt.Sprintf("\nmoved {\n  from = %s\n  to   = %s\n}\n", from, to)
+		insertPos := WholeLinesRange(f.Bytes, blockRng).End
+		if insertPos.Column > 1 {
+			// The block is at the end of a file without trailing newline
+			text = "\n" + text
+		}
+
+		edits := make(FileEdits)
+		edits.Add(path.Path, hcl.Range{Filename: filename, Start: insertPos, End: insertPos}, text)
+		return edits, nil
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,466 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/hashicorp/hcl-lang/decoder"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/reference"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/opentofu/tofu-ls/internal/lsp"
+)
+
+// renamableRoots represents the first address steps of targets
+// which can be renamed, i.e. input variables, local values,
+// outputs and module calls.
+var renamableRoots = map[string]struct{}{
+	"var":    {},
+	"local":  {},
+	"output": {},
+	"module": {},
+}
+
+// RenameTarget represents a declaration which can be renamed
+// together with all references to it.
+type RenameTarget struct {
+	// Path is the path where the target is declared
+	Path lang.Path
+	// Targets represents all targets of the declaration, since a single
+	// block can be targetable in more ways, e.g. as a reference
+	// and as a value of certain type.
+	Targets reference.Targets
+
+	// NameRange is the range of the name at the position
+	// the rename was requested for, which may be either
+	// the declaration or any reference to it.
+	NameRange hcl.Range
+
+	// ModuleCalls are the module blocks calling the module
+	// in which an output is declared. Callers refer to the output
+	// as module.<name>.<output>, which isn't known from the
+	// declaration alone.
+	ModuleCalls []ModuleCall
+}
+
+// ModuleCall represents a module block declared in the module at Path
+type ModuleCall struct {
+	Path lang.Path
+	Name string
+}
+
+// Name returns the current name of the target, e.g. foo for var.foo
+func (rt *RenameTarget) Name() string {
+	addr := rt.Targets[0].Addr
+	return addrStepName(addr[len(addr)-1])
+}
+
+// IsOutput returns true if the target is an output
+func (rt *RenameTarget) IsOutput() bool {
+	return addrStepName(rt.Targets[0].Addr[0]) == "output"
+}
+
+// PrepareRename finds a renamable target which is either
+// declared or referenced at the given position.
+// It returns nil if there is nothing to rename.
+func PrepareRename(pr decoder.PathReader, path lang.Path, filename string, pos hcl.Pos) (*RenameTarget, error) {
+	pathCtx, err := pr.PathContext(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Reference to a target
+	origins, _ := pathCtx.ReferenceOrigins.AtPos(filename, pos)
+	for _, origin := range origins {
+		matchableOrigin, ok := origin.(reference.MatchableOrigin)
+		if !ok {
+			continue
+		}
+
+		targetPath := path
+		targetCtx := pathCtx
+		if pathOrigin, ok := origin.(reference.PathOrigin); ok {
+			targetPath = lang.Path{
+				Path:       pathOrigin.TargetPath.Path,
+				LanguageID: lsp.ParseLanguageID(pathOrigin.TargetPath.LanguageID).String(),
+			}
+			targetCtx, err = pr.PathContext(targetPath)
+			if err != nil {
+				continue
+			}
+		}
+
+		target, ok := renamableTargetForOrigin(targetCtx.ReferenceTargets, matchableOrigin)
+		if !ok {
+			continue
+		}
+
+		f, ok := pathCtx.Files[filename]
+		if !ok {
+			continue
+		}
+		nameRng, ok := originNameRange(f, matchableOrigin, target)
+		if !ok {
+			continue
+		}
+
+		return &RenameTarget{
+			Path:      targetPath,
+			Targets:   targetsWithAddr(targetCtx.ReferenceTargets, target.Addr),
+			NameRange: nameRng,
+		}, nil
+	}
+
+	// Declaration of a target
+	for _, target := range pathCtx.ReferenceTargets {
+		if !isRenamable(target) || target.DefRangePtr == nil {
+			continue
+		}
+		if target.DefRangePtr.Filename != filename || !target.DefRangePtr.ContainsPos(pos) {
+			continue
+		}
+
+		f, ok := pathCtx.Files[filename]
+		if !ok {
+			continue
+		}
+		nameRng, ok := declarationNameRange(f, target)
+		if !ok {
+			continue
+		}
+
+		return &RenameTarget{
+			Path:      path,
+			Targets:   targetsWithAddr(pathCtx.ReferenceTargets, target.Addr),
+			NameRange: nameRng,
+		}, nil
+	}
+
+	return nil, nil
+}
+
+// Rename returns edits which rename the given target to newName,
+// at its declaration and at all references to it, including
+// references from other paths, such as module blocks calling
+// the module or *.tfvars files.
+func Rename(ctx context.Context, pr decoder.PathReader, rt *RenameTarget, newName string) (FileEdits, error) {
+	if !hclsyntax.ValidIdentifier(newName) {
+		return nil, fmt.Errorf("%q is not a valid name", newName)
+	}
+
+	edits := make(FileEdits)
+	seen := make(map[string]map[hcl.Range]struct{})
+	addEdit := func(dirPath string, rng hcl.Range) {
+		path := filepath.Join(dirPath, rng.Filename)
+		if _, ok := seen[path]; !ok {
+			seen[path] = make(map[hcl.Range]struct{})
+		}
+		if _, ok := seen[path][rng]; ok {
+			return
+		}
+		seen[path][rng] = struct{}{}
+		edits.Add(dirPath, rng, newName)
+	}
+
+	targetCtx, err := pr.PathContext(rt.Path)
+	if err != nil {
+		return nil, err
+	}
+	if isNameDeclared(targetCtx.ReferenceTargets, rt.Targets[0].Addr, newName) {
+		return nil, fmt.Errorf("%q is already declared", newName)
+	}
+
+	for _, target := range rt.Targets {
+		if target.DefRangePtr == nil {
+			continue
+		}
+		f, ok := targetCtx.Files[target.DefRangePtr.Filename]
+		if !ok {
+			continue
+		}
+		nameRng, ok := declarationNameRange(f, target)
+		if ok {
+			addEdit(rt.Path.Path, nameRng)
+		}
+	}
+
+	for _, path := range pr.Paths(ctx) {
+		pathCtx, err := pr.PathContext(path)
+		if err != nil {
+			continue
+		}
+
+		for _, target := range rt.Targets {
+			for _, origin := range matchOrigins(pathCtx.ReferenceOrigins, path, target, rt.Path) {
+				f, ok := pathCtx.Files[origin.OriginRange().Filename]
+				if !ok {
+					continue
+				}
+				nameRng, ok := originNameRange(f, origin, target)
+				if !ok {
+					continue
+				}
+				addEdit(path.Path, nameRng)
+			}
+		}
+	}
+
+	if rt.IsOutput() {
+		for _, mc := range rt.ModuleCalls {
+			pathCtx, err := pr.PathContext(mc.Path)
+			if err != nil {
+				continue
+			}
+
+			for _, origin := range pathCtx.ReferenceOrigins {
+				localOrigin, ok := origin.(reference.LocalOrigin)
+				if !ok {
+					continue
+				}
+				// Callers reference the output as module.<name>.<output>,
+				// or module.<name>[<key>].<output> when using count or for_each
+				outputAddr, ok := moduleOutputAddr(localOrigin.Addr, mc.Name, rt.Name())
+				if !ok {
+					continue
+				}
+				f, ok := pathCtx.Files[localOrigin.OriginRange().Filename]
+				if !ok {
+					continue
+				}
+				nameRng, ok := originNameRange(f, localOrigin, reference.Target{Addr: outputAddr})
+				if !ok {
+					continue
+				}
+				addEdit(mc.Path.Path, nameRng)
+			}
+		}
+	}
+
+	for path := range edits {
+		sort.SliceStable(edits[path], func(i, j int) bool {
+			return edits[path][i].Range.Start.Byte < edits[path][j].Range.Start.Byte
+		})
+	}
+
+	return edits, nil
+}
+
+func isRenamable(target reference.Target) bool {
+	if len(target.Addr) != 2 {
+		return false
+	}
+	root, ok := target.Addr[0].(lang.RootStep)
+	if !ok {
+		return false
+	}
+	_, ok = renamableRoots[root.Name]
+	return ok
+}
+
+// renamableTargetForOrigin finds the renamable target which the origin
+// refers to, or which contains the (nested) target the origin refers to,
+// e.g. var.foo for var.foo.bar.
+func renamableTargetForOrigin(targets reference.Targets, origin reference.MatchableOrigin) (reference.Target, bool) {
+	for _, target := range targets {
+		if !isRenamable(target) {
+			continue
+		}
+		if _, ok := reference.Targets([]reference.Target{target}).Match(origin); ok {
+			return target, true
+		}
+	}
+	return reference.Target{}, false
+}
+
+// targetsWithAddr returns all top-level targets with the given address
+func targetsWithAddr(targets reference.Targets, addr lang.Address) reference.Targets {
+	matched := make(reference.Targets, 0)
+	for _, target := range targets {
+		if target.Addr.Equals(addr) {
+			matched = append(matched, target)
+		}
+	}
+	return matched
+}
+
+// matchOrigins returns origins from localPath which refer to the given target
+// declared in targetPath, or to any of its nested targets.
+//
+// Unlike [reference.Origins.Match] it treats paths with Terraform
+// language IDs as equal to their OpenTofu counterparts, since origins
+// pointing to other modules may come with either language ID.
+func matchOrigins(origins reference.Origins, localPath lang.Path, target reference.Target, targetPath lang.Path) []reference.MatchableOrigin {
+	matched := make([]reference.MatchableOrigin, 0)
+
+	for _, refOrigin := range origins {
+		switch origin := refOrigin.(type) {
+		case reference.LocalOrigin:
+			if pathsEqual(localPath, targetPath) && target.Matches(origin) {
+				matched = append(matched, origin)
+			}
+		case reference.PathOrigin:
+			if pathsEqual(origin.TargetPath, targetPath) && target.Matches(origin) {
+				matched = append(matched, origin)
+			}
+		}
+	}
+
+	for _, nestedTarget := range target.NestedTargets {
+		matched = append(matched, matchOrigins(origins, localPath, nestedTarget, targetPath)...)
+	}
+
+	return matched
+}
+
+// moduleOutputAddr returns the part of addr which refers to the given
+// output of a module call, e.g. module.foo.out for module.foo.out.id
+// or module.foo[0].out for module.foo[0].out.id
+func moduleOutputAddr(addr lang.Address, moduleName, outputName string) (lang.Address, bool) {
+	if len(addr) < 3 {
+		return nil, false
+	}
+	if root, ok := addr[0].(lang.RootStep); !ok || root.Name != "module" {
+		return nil, false
+	}
+	if name, ok := addr[1].(lang.AttrStep); !ok || name.Name != moduleName {
+		return nil, false
+	}
+
+	idx := 2
+	if _, ok := addr[idx].(lang.IndexStep); ok {
+		idx++
+	}
+	if idx >= len(addr) {
+		return nil, false
+	}
+	if output, ok := addr[idx].(lang.AttrStep); !ok || output.Name != outputName {
+		return nil, false
+	}
+
+	return addr[:idx+1], true
+}
+
+// isNameDeclared returns true if a renamable target of the same kind
+// as the one at addr is already declared under the given name,
+// e.g. local.bar when renaming local.foo to bar
+func isNameDeclared(targets reference.Targets, addr lang.Address, name string) bool {
+	for _, target := range targets {
+		if !isRenamable(target) || target.Addr.Equals(addr) {
+			continue
+		}
+		if addrStepName(target.Addr[0]) == addrStepName(addr[0]) &&
+			addrStepName(target.Addr[1]) == name {
+			return true
+		}
+	}
+	return false
+}
+
+func pathsEqual(a, b lang.Path) bool {
+	return a.Path == b.Path &&
+		lsp.ParseLanguageID(a.LanguageID) == lsp.ParseLanguageID(b.LanguageID)
+}
+
+// originNameRange returns the range of the target's name within
+// the origin, e.g. range of foo in var.foo.bar, module.foo.out,
+// or in the attribute name of a module input or *.tfvars entry.
+func originNameRange(f *hcl.File, origin reference.MatchableOrigin, target reference.Target) (hcl.Range, bool) {
+	rng := origin.OriginRange()
+	if rng.End.Byte > len(f.Bytes) || rng.Start.Byte > rng.End.Byte {
+		return hcl.Range{}, false
+	}
+
+	traversal, diags := hclsyntax.ParseTraversalAbs(f.Bytes[rng.Start.Byte:rng.End.Byte], rng.Filename, rng.Start)
+	if diags.HasErrors() {
+		return hcl.Range{}, false
+	}
+
+	// The origin address may differ from the traversal written in the
+	// configuration (e.g. module.foo.out is an origin for output.out),
+	// but both end with the same steps.
+	idx := len(traversal) - len(origin.Address()) + len(target.Addr) - 1
+	if idx < 0 || idx >= len(traversal) {
+		return hcl.Range{}, false
+	}
+
+	name := addrStepName(target.Addr[len(target.Addr)-1])
+	switch step := traversal[idx].(type) {
+	case hcl.TraverseRoot:
+		if step.Name == name {
+			return step.SrcRange, true
+		}
+	case hcl.TraverseAttr:
+		if step.Name == name {
+			// The range includes the leading dot
+			return trimRangeStart(step.SrcRange, step.SrcRange.End.Byte-step.SrcRange.Start.Byte-len(name)), true
+		}
+	}
+
+	return hcl.Range{}, false
+}
+
+// declarationNameRange returns the range of the target's name
+// in its declaration, i.e. the (unquoted) last block label of variable,
+// output, module, resource and data blocks, or the attribute name
+// of local values.
+func declarationNameRange(f *hcl.File, target reference.Target) (hcl.Range, bool) {
+	body, ok := f.Body.(*hclsyntax.Body)
+	if !ok {
+		// JSON is not supported
+		return hcl.Range{}, false
+	}
+
+	defRng := *target.DefRangePtr
+	name := addrStepName(target.Addr[len(target.Addr)-1])
+
+	for _, block := range body.Blocks {
+		if block.DefRange().Start.Byte == defRng.Start.Byte && len(block.Labels) > 0 {
+			nameIdx := len(block.Labels) - 1
+			if block.Labels[nameIdx] != name {
+				return hcl.Range{}, false
+			}
+			// Exclude the quotes around the label
+			labelRng := trimRangeStart(block.LabelRanges[nameIdx], 1)
+			return trimRangeEnd(labelRng, 1), true
+		}
+
+		if block.Type != "locals" || !block.Range().ContainsPos(defRng.Start) {
+			continue
+		}
+		for _, attr := range block.Body.Attributes {
+			if attr.NameRange == defRng && attr.Name == name {
+				return attr.NameRange, true
+			}
+		}
+	}
+
+	return hcl.Range{}, false
+}
+
+func addrStepName(step lang.AddressStep) string {
+	switch s := step.(type) {
+	case lang.RootStep:
+		return s.Name
+	case lang.AttrStep:
+		return s.Name
+	}
+	return ""
+}
+
+func trimRangeStart(rng hcl.Range, n int) hcl.Range {
+	rng.Start.Byte += n
+	rng.Start.Column += n
+	return rng
+}
+
+func trimRangeEnd(rng hcl.Range, n int) hcl.Range {
+	rng.End.Byte -= n
+	rng.End.Column -= n
+	return rng
+}
@@ -0,0 +1,477 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl-lang/decoder"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/reference"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+type testPathReader map[lang.Path]*decoder.PathContext
+
+func (r testPathReader) Paths(ctx context.Context) []lang.Path {
+	paths := make([]lang.Path, 0, len(r))
+	for path := range r {
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+func (r testPathReader) PathContext(path lang.Path) (*decoder.PathContext, error) {
+	pathCtx, ok := r[path]
+	if !ok {
+		return nil, fmt.Errorf("path not found: %#v", path)
+	}
+	return pathCtx, nil
+}
+
+func parseFile(t *testing.T, filename, src string) (*hcl.File, *hclsyntax.Body) {
+	f, diags := hclsyntax.ParseConfig([]byte(src), filename, hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+	return f, f.Body.(*hclsyntax.Body)
+}
+
+func TestRename_variableAcrossModules(t *testing.T) {
+	childPath := lang.Path{Path: filepath.Join("root", "child"), LanguageID: "opentofu"}
+	varsPath := lang.Path{Path: childPath.Path, LanguageID: "opentofu-vars"}
+	rootPath := lang.Path{Path: "root", LanguageID: "opentofu"}
+
+	childFile, childBody := parseFile(t, "main.tf", `variable "foo" {
+}
+
+output "out" {
+  value = var.foo
+}
+`)
+	varBlock := childBody.Blocks[0]
+	varRef := childBody.Blocks[1].Body.Attributes["value"].Expr.Range()
+
+	varsFile, varsBody := parseFile(t, "terraform.tfvars", `foo = "bar"
+`)
+	rootFile, rootBody := parseFile(t, "main.tf", `module "child" {
+  source = "./child"
+  foo    = "baz"
+}
+`)
+
+	varAddr := lang.Address{
+		lang.RootStep{Name: "var"},
+		lang.AttrStep{Name: "foo"},
+	}
+	reader := testPathReader{
+		childPath: {
+			Files: map[string]*hcl.File{"main.tf": childFile},
+			ReferenceTargets: reference.Targets{
+				{
+					Addr:        varAddr,
+					ScopeId:     lang.ScopeId("variable"),
+					Type:        cty.String,
+					RangePtr:    varBlock.Range().Ptr(),
+					DefRangePtr: varBlock.DefRange().Ptr(),
+				},
+			},
+			ReferenceOrigins: reference.Origins{
+				reference.LocalOrigin{
+					Addr:  varAddr,
+					Range: varRef,
+					Constraints: reference.OriginConstraints{
+						{OfType: cty.DynamicPseudoType},
+					},
+				},
+			},
+		},
+		varsPath: {
+			Files: map[string]*hcl.File{"terraform.tfvars": varsFile},
+			ReferenceOrigins: reference.Origins{
+				reference.PathOrigin{
+					Range:      varsBody.Attributes["foo"].NameRange,
+					TargetAddr: varAddr,
+					TargetPath: childPath,
+					Constraints: reference.OriginConstraints{
+						{OfScopeId: lang.ScopeId("variable"), OfType: cty.String},
+					},
+				},
+			},
+		},
+		rootPath: {
+			Files: map[string]*hcl.File{"main.tf": rootFile},
+			ReferenceOrigins: reference.Origins{
+				reference.PathOrigin{
+					Range:      rootBody.Blocks[0].Body.Attributes["foo"].NameRange,
+					TargetAddr: varAddr,
+					// Module inputs may point to the module
+					// with the Terraform language ID
+					TargetPath: lang.Path{Path: childPath.Path, LanguageID: "terraform"},
+					Constraints: reference.OriginConstraints{
+						{OfScopeId: lang.ScopeId("variable"), OfType: cty.String},
+					},
+				},
+			},
+		},
+	}
+
+	rt, err := PrepareRename(reader, rootPath, "main.tf", hcl.Pos{Line: 3, Column: 4, Byte: 41})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rt == nil {
+		t.Fatal("expected rename target")
+	}
+	if rt.Name() != "foo" {
+		t.Fatalf("unexpected name: %q", rt.Name())
+	}
+
+	edits, err := Rename(context.Background(), reader, rt, "renamed")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedEdits := FileEdits{
+		filepath.Join(childPath.Path, "main.tf"): {
+			{
+				Range: hcl.Range{
+					Filename: "main.tf",
+					Start:    hcl.Pos{Line: 1, Column: 11, Byte: 10},
+					End:      hcl.Pos{Line: 1, Column: 14, Byte: 13},
+				},
+				NewText: "renamed",
+				Snippet: "renamed",
+			},
+			{
+				Range: hcl.Range{
+					Filename: "main.tf",
+					Start:    hcl.Pos{Line: 5, Column: 15, Byte: 49},
+					End:      hcl.Pos{Line: 5, Column: 18, Byte: 52},
+				},
+				NewText: "renamed",
+				Snippet: "renamed",
+			},
+		},
+		filepath.Join(childPath.Path, "terraform.tfvars"): {
+			{
+				Range: hcl.Range{
+					Filename: "terraform.tfvars",
+					Start:    hcl.Pos{Line: 1, Column: 1, Byte: 0},
+					End:      hcl.Pos{Line: 1, Column: 4, Byte: 3},
+				},
+				NewText: "renamed",
+				Snippet: "renamed",
+			},
+		},
+		filepath.Join(rootPath.Path, "main.tf"): {
+			{
+				Range: hcl.Range{
+					Filename: "main.tf",
+					Start:    hcl.Pos{Line: 3, Column: 3, Byte: 40},
+					End:      hcl.Pos{Line: 3, Column: 6, Byte: 43},
+				},
+				NewText: "renamed",
+				Snippet: "renamed",
+			},
+		},
+	}
+	if diff := cmp.Diff(expectedEdits, edits); diff != "" {
+		t.Fatalf("unexpected edits: %s", diff)
+	}
+}
+
+func TestRename_outputAcrossModules(t *testing.T) {
+	childPath := lang.Path{Path: filepath.Join("root", "child"), LanguageID: "opentofu"}
+	rootPath := lang.Path{Path: "root", LanguageID: "opentofu"}
+
+	childFile, childBody := parseFile(t, "outputs.tf", `output "out" {
+  value = {}
+}
+`)
+	outputBlock := childBody.Blocks[0]
+
+	rootFile, rootBody := parseFile(t, "main.tf", `module "child" {
+  source = "./child"
+}
+
+output "whole" {
+  value = module.child.out
+}
+
+output "nested" {
+  value = module.child.out.id
+}
+`)
+	wholeRng := rootBody.Blocks[1].Body.Attributes["value"].Expr.Range()
+	nestedRng := rootBody.Blocks[2].Body.Attributes["value"].Expr.Range()
+
+	outputAddr := lang.Address{
+		lang.RootStep{Name: "output"},
+		lang.AttrStep{Name: "out"},
+	}
+	reader := testPathReader{
+		childPath: {
+			Files: map[string]*hcl.File{"outputs.tf": childFile},
+			ReferenceTargets: reference.Targets{
+				{
+					Addr:        outputAddr,
+					ScopeId:     lang.ScopeId("output"),
+					RangePtr:    outputBlock.Range().Ptr(),
+					DefRangePtr: outputBlock.DefRange().Ptr(),
+				},
+			},
+		},
+		rootPath: {
+			Files: map[string]*hcl.File{"main.tf": rootFile},
+			ReferenceOrigins: reference.Origins{
+				reference.LocalOrigin{
+					Addr: lang.Address{
+						lang.RootStep{Name: "module"},
+						lang.AttrStep{Name: "child"},
+						lang.AttrStep{Name: "out"},
+					},
+					Range: wholeRng,
+				},
+				// The whole output is also an implied origin of the output
+				reference.PathOrigin{
+					Range:      wholeRng,
+					TargetAddr: outputAddr,
+					TargetPath: lang.Path{Path: childPath.Path, LanguageID: "terraform"},
+					Constraints: reference.OriginConstraints{
+						{OfScopeId: lang.ScopeId("output")},
+					},
+				},
+				reference.LocalOrigin{
+					Addr: lang.Address{
+						lang.RootStep{Name: "module"},
+						lang.AttrStep{Name: "child"},
+						lang.AttrStep{Name: "out"},
+						lang.AttrStep{Name: "id"},
+					},
+					Range: nestedRng,
+				},
+			},
+		},
+	}
+
+	rt, err := PrepareRename(reader, childPath, "outputs.tf", hcl.Pos{Line: 1, Column: 10, Byte: 9})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rt == nil {
+		t.Fatal("expected rename target")
+	}
+	if !rt.IsOutput() {
+		t.Fatalf("expected output, given: %s", rt.Targets[0].Addr)
+	}
+	rt.ModuleCalls = []ModuleCall{
+		{Path: rootPath, Name: "child"},
+	}
+
+	edits, err := Rename(context.Background(), reader, rt, "renamed")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedEdits := FileEdits{
+		filepath.Join(childPath.Path, "outputs.tf"): {
+			{
+				Range: hcl.Range{
+					Filename: "outputs.tf",
+					Start:    hcl.Pos{Line: 1, Column: 9, Byte: 8},
+					End:      hcl.Pos{Line: 1, Column: 12, Byte: 11},
+				},
+				NewText: "renamed",
+				Snippet: "renamed",
+			},
+		},
+		filepath.Join(rootPath.Path, "main.tf"): {
+			{
+				Range: hcl.Range{
+					Filename: "main.tf",
+					Start:    hcl.Pos{Line: 6, Column: 24, Byte: 81},
+					End:      hcl.Pos{Line: 6, Column: 27, Byte: 84},
+				},
+				NewText: "renamed",
+				Snippet: "renamed",
+			},
+			{
+				Range: hcl.Range{
+					Filename: "main.tf",
+					Start:    hcl.Pos{Line: 10, Column: 24, Byte: 129},
+					End:      hcl.Pos{Line: 10, Column: 27, Byte: 132},
+				},
+				NewText: "renamed",
+				Snippet: "renamed",
+			},
+		},
+	}
+	if diff := cmp.Diff(expectedEdits, edits); diff != "" {
+		t.Fatalf("unexpected edits: %s", diff)
+	}
+}
+
+func TestRename_invalidName(t *testing.T) {
+	rt := &RenameTarget{
+		Targets: reference.Targets{
+			{
+				Addr: lang.Address{
+					lang.RootStep{Name: "local"},
+					lang.AttrStep{Name: "foo"},
+				},
+			},
+		},
+	}
+	_, err := Rename(context.Background(), testPathReader{}, rt, "not valid")
+	if err == nil {
+		t.Fatal("expected error for invalid name")
+	}
+}
+
+func TestRename_outputOfModuleWithCount(t *testing.T) {
+	childPath := lang.Path{Path: filepath.Join("root", "child"), LanguageID: "opentofu"}
+	rootPath := lang.Path{Path: "root", LanguageID: "opentofu"}
+
+	childFile, childBody := parseFile(t, "outputs.tf", `output "out" {
+  value = {}
+}
+`)
+	outputBlock := childBody.Blocks[0]
+
+	rootFile, rootBody := parseFile(t, "main.tf", `module "child" {
+  source = "./child"
+  count  = 1
+}
+
+output "indexed" {
+  value = module.child[0].out.id
+}
+`)
+	indexedRng := rootBody.Blocks[1].Body.Attributes["value"].Expr.Range()
+
+	reader := testPathReader{
+		childPath: {
+			Files: map[string]*hcl.File{"outputs.tf": childFile},
+			ReferenceTargets: reference.Targets{
+				{
+					Addr: lang.Address{
+						lang.RootStep{Name: "output"},
+						lang.AttrStep{Name: "out"},
+					},
+					ScopeId:     lang.ScopeId("output"),
+					RangePtr:    outputBlock.Range().Ptr(),
+					DefRangePtr: outputBlock.DefRange().Ptr(),
+				},
+			},
+		},
+		rootPath: {
+			Files: map[string]*hcl.File{"main.tf": rootFile},
+			ReferenceOrigins: reference.Origins{
+				reference.LocalOrigin{
+					Addr: lang.Address{
+						lang.RootStep{Name: "module"},
+						lang.AttrStep{Name: "child"},
+						lang.IndexStep{Key: cty.NumberIntVal(0)},
+						lang.AttrStep{Name: "out"},
+						lang.AttrStep{Name: "id"},
+					},
+					Range: indexedRng,
+				},
+			},
+		},
+	}
+
+	rt, err := PrepareRename(reader, childPath, "outputs.tf", hcl.Pos{Line: 1, Column: 10, Byte: 9})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rt == nil {
+		t.Fatal("expected rename target")
+	}
+	rt.ModuleCalls = []ModuleCall{
+		{Path: rootPath, Name: "child"},
+	}
+
+	edits, err := Rename(context.Background(), reader, rt, "renamed")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedEdits := FileEdits{
+		filepath.Join(childPath.Path, "outputs.tf"): {
+			{
+				Range: hcl.Range{
+					Filename: "outputs.tf",
+					Start:    hcl.Pos{Line: 1, Column: 9, Byte: 8},
+					End:      hcl.Pos{Line: 1, Column: 12, Byte: 11},
+				},
+				NewText: "renamed",
+				Snippet: "renamed",
+			},
+		},
+		filepath.Join(rootPath.Path, "main.tf"): {
+			{
+				Range: hcl.Range{
+					Filename: "main.tf",
+					Start:    hcl.Pos{Line: 7, Column: 27, Byte: 99},
+					End:      hcl.Pos{Line: 7, Column: 30, Byte: 102},
+				},
+				NewText: "renamed",
+				Snippet: "renamed",
+			},
+		},
+	}
+	if diff := cmp.Diff(expectedEdits, edits); diff != "" {
+		t.Fatalf("unexpected edits: %s", diff)
+	}
+}
+
+func TestRename_nameAlreadyDeclared(t *testing.T) {
+	modPath := lang.Path{Path: "root", LanguageID: "opentofu"}
+
+	f, body := parseFile(t, "main.tf", `locals {
+  foo = 1
+  bar = 2
+}
+`)
+	attrs := body.Blocks[0].Body.Attributes
+	targets := reference.Targets{}
+	for _, name := range []string{"foo", "bar"} {
+		targets = append(targets, reference.Target{
+			Addr: lang.Address{
+				lang.RootStep{Name: "local"},
+				lang.AttrStep{Name: name},
+			},
+			RangePtr:    attrs[name].Range().Ptr(),
+			DefRangePtr: attrs[name].NameRange.Ptr(),
+		})
+	}
+	reader := testPathReader{
+		modPath: {
+			Files:            map[string]*hcl.File{"main.tf": f},
+			ReferenceTargets: targets,
+		},
+	}
+
+	rt := &RenameTarget{
+		Path:    modPath,
+		Targets: targets[:1],
+	}
+	_, err := Rename(context.Background(), reader, rt, "bar")
+	if err == nil {
+		t.Fatal("expected error for name which is already declared")
+	}
+
+	_, err = Rename(context.Background(), reader, rt, "baz")
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"context"
+	"fmt"
+	"maps"
+
+	"github.com/hashicorp/hcl-lang/decoder"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+// SemanticTokensInRange returns semantic tokens of the top-level blocks
+// and attributes of the file which overlap with the given range.
+// Other parts of the file are not tokenized at all, while tokens
+// of an overlapping block may extend beyond the range.
+func SemanticTokensInRange(ctx context.Context, pr decoder.PathReader, path lang.Path, filename string, rng hcl.Range) ([]lang.SemanticToken, error) {
+	pathCtx, err := pr.PathContext(path)
+	if err != nil {
+		return nil, err
+	}
+
+	f, ok := pathCtx.Files[filename]
+	if !ok {
+		return nil, &decoder.FileNotFoundError{Filename: filename}
+	}
+	body, ok := f.Body.(*hclsyntax.Body)
+	if !ok {
+		return nil, &decoder.UnknownFileFormatError{Filename: filename}
+	}
+
+	rangeBody := &hclsyntax.Body{
+		Attributes: make(hclsyntax.Attributes),
+		SrcRange:   body.SrcRange,
+		EndRange:   body.EndRange,
+	}
+	for name, attr := range body.Attributes {
+		if attr.Range().Overlaps(rng) {
+			rangeBody.Attributes[name] = attr
+		}
+	}
+	for _, block := range body.Blocks {
+		if block.Range().Overlaps(rng) {
+			rangeBody.Blocks = append(rangeBody.Blocks, block)
+		}
+	}
+
+	rangeCtx := *pathCtx
+	rangeCtx.Files = maps.Clone(pathCtx.Files)
+	rangeCtx.Files[filename] = &hcl.File{
+		Body:  rangeBody,
+		Bytes: f.Bytes,
+		Nav:   f.Nav,
+	}
+
+	d, err := decoder.NewDecoder(&staticPathReader{path: path, pathCtx: &rangeCtx}).Path(path)
+	if err != nil {
+		return nil, err
+	}
+	return d.SemanticTokensInFile(ctx, filename)
+}
+
+// staticPathReader reads a single path context
+type staticPathReader struct {
+	path    lang.Path
+	pathCtx *decoder.PathContext
+}
+
+func (pr *staticPathReader) Paths(ctx context.Context) []lang.Path {
+	return []lang.Path{pr.path}
+}
+
+func (pr *staticPathReader) PathContext(path lang.Path) (*decoder.PathContext, error) {
+	if path != pr.path {
+		return nil, fmt.Errorf("path not found: %#v", path)
+	}
+	return pr.pathCtx, nil
+}
@@ -31,6 +31,23 @@ type Document struct {
 	// and to aid in calculating diff when formatting document.
 	// LSP positions contain just line+column but hcl.Pos requires offset.
 	Lines source.Lines
+
+	// SemanticTokens contains tokens last sent to the client,
+	// which may be of an older version of the document,
+	// so that only the difference can be sent next time.
+	SemanticTokens *SemanticTokens
+}
+
+// SemanticTokens represents encoded semantic tokens of a particular
+// version of a document, identified by a result ID
+type SemanticTokens struct {
+	ResultID string
+	Version  int
+	Data     []uint32
+
+	// IsOutdated indicates that the tokens may no longer reflect their
+	// version of the document, e.g. since schemas have changed
+	IsOutdated bool
 }
 
 func (doc *Document) FullPath() string {
@@ -46,5 +63,8 @@ func (d *Document) Copy() *Document {
 		Version:    d.Version,
 		Text:       d.Text,
 		Lines:      d.Lines.Copy(),
+
+		// Tokens are never modified once stored
+		SemanticTokens: d.SemanticTokens,
 	}
 }
@@ -6,6 +6,9 @@
 package decoder
 
 import (
+	"fmt"
+	"maps"
+
 	"github.com/hashicorp/go-version"
 	"github.com/hashicorp/hcl-lang/schema"
 	tfmodule "github.com/opentofu/opentofu-schema/module"
@@ -13,6 +16,10 @@ import (
 	"github.com/opentofu/tofu-ls/internal/features/modules/state"
 )
 
+// providerFunctionsVersion is the first OpenTofu version
+// supporting provider-defined functions
+var providerFunctionsVersion = version.Must(version.NewVersion("1.7.0"))
+
 func functionsForModule(mod *state.ModuleRecord, stateReader CombinedReader) (map[string]schema.FunctionSignature, error) {
 	resolvedVersion := tfschema.ResolveVersion(stateReader.TofuVersion(mod.Path()), mod.Meta.CoreRequirements)
 	sm := tfschema.NewFunctionsMerger(mustFunctionsForVersion(resolvedVersion))
@@ -25,7 +32,42 @@ func functionsForModule(mod *state.ModuleRecord, stateReader CombinedReader) (ma
 		ProviderReferences:   mod.Meta.ProviderReferences,
 	}
 
-	return sm.FunctionsForModule(meta)
+	functions, err := sm.FunctionsForModule(meta)
+	if err != nil {
+		return nil, err
+	}
+	if resolvedVersion.LessThan(providerFunctionsVersion) {
+		return functions, nil
+	}
+
+	// The merger follows Terraform, which only supports provider-defined
+	// functions since 1.8 and doesn't offer them for aliased configurations.
+	// We add those it left out, without overriding any it returned.
+	functions = maps.Clone(functions)
+	providerRefs := tfschema.ProviderReferences(mod.Meta.ProviderReferences)
+	for pAddr, pCons := range mod.Meta.ProviderRequirements {
+		pSchema, err := stateReader.ProviderSchema(mod.Path(), pAddr, pCons)
+		if err != nil || len(pSchema.Functions) == 0 {
+			continue
+		}
+
+		for _, ref := range providerRefs.ReferencesOfProvider(pAddr) {
+			// functions of aliased provider configurations are called
+			// as provider::<name>::<alias>::<function>
+			prefix := fmt.Sprintf("provider::%s::", ref.LocalName)
+			if ref.Alias != "" {
+				prefix += ref.Alias + "::"
+			}
+
+			for fName, fSig := range pSchema.Functions {
+				if _, ok := functions[prefix+fName]; !ok {
+					functions[prefix+fName] = *fSig.Copy()
+				}
+			}
+		}
+	}
+
+	return functions, nil
 }
 
 func mustFunctionsForVersion(v *version.Version) map[string]schema.FunctionSignature {
@@ -8,7 +8,9 @@ package validations
 import (
 	"context"
 	"fmt"
+	"strings"
 
+	"github.com/hashicorp/hcl-lang/lang"
 	"github.com/hashicorp/hcl-lang/schema"
 	"github.com/hashicorp/hcl-lang/schemacontext"
 	"github.com/hashicorp/hcl/v2"
@@ -51,6 +53,9 @@ func (mra MissingRequiredAttribute) Visit(ctx context.Context, node hclsyntax.No
 						Summary:  fmt.Sprintf("Required attribute %q not specified", name),
 						Detail:   fmt.Sprintf("An attribute named %q is required here", name),
 						Subject:  nodeType.SrcRange.Ptr(),
+						Extra: QuickFixes{
+							addAttributeFix(ctx, nodeType, name, attr),
+						},
 					})
 				}
 			}
@@ -60,6 +65,46 @@ func (mra MissingRequiredAttribute) Visit(ctx context.Context, node hclsyntax.No
 	return ctx, diags
 }
 
+// addAttributeFix inserts the attribute with a placeholder value
+// just before the closing brace of the body.
+func addAttributeFix(ctx context.Context, body *hclsyntax.Body, name string, attr *schema.AttributeSchema) QuickFix {
+	// Nesting level of the block this body belongs to
+	nestingLvl, _ := schemacontext.BlockNestingLevel(ctx)
+
+	value := `""`
+	if attr.Constraint != nil {
+		if data := attr.Constraint.EmptyCompletionData(ctx, 1, int(nestingLvl)); data.NewText != "" {
+			value = data.NewText
+		}
+	}
+
+	braceIndent := ""
+	if nestingLvl > 0 {
+		braceIndent = strings.Repeat("  ", int(nestingLvl)-1)
+	}
+	attrLine := fmt.Sprintf("%s  %s = %s\n", braceIndent, name, value)
+
+	closingBrace := body.EndRange.Start
+	var edit lang.TextEdit
+	if closingBrace.Line > body.SrcRange.Start.Line {
+		// Insert a new line above the closing brace
+		lineStart := hcl.Pos{
+			Line:   closingBrace.Line,
+			Column: 1,
+			Byte:   closingBrace.Byte - (closingBrace.Column - 1),
+		}
+		edit = insertEdit(lineStart, body.SrcRange.Filename, attrLine)
+	} else {
+		// Break a single-line body, such as {}
+		edit = insertEdit(closingBrace, body.SrcRange.Filename, "\n"+attrLine+braceIndent)
+	}
+
+	return QuickFix{
+		Title: fmt.Sprintf("Add required attribute %q", name),
+		Edits: []lang.TextEdit{edit},
+	}
+}
+
 type unknownRequiredAttrsCtxKey struct{}
 
 func HasUnknownRequiredAttributes(ctx context.Context) bool {
@@ -0,0 +1,105 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package validations
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	tfmod "github.com/opentofu/opentofu-schema/module"
+	tfaddr "github.com/opentofu/registry-address"
+	"github.com/opentofu/tofu-ls/internal/langserver/cmd"
+)
+
+// ProviderLockDrift reports entries in required_providers whose provider
+// is either missing from the dependency lock file, or locked in a version
+// which doesn't satisfy the declared version constraints.
+//
+// Constraints are those of the whole module, since a provider may be
+// required in multiple blocks. The offered fix upgrades the locked providers
+// of the module at dirUri within the constraints.
+func ProviderLockDrift(files map[string]*hcl.File, refs map[tfmod.ProviderRef]tfaddr.Provider,
+	requirements tfmod.ProviderRequirements, locked map[tfaddr.Provider]*version.Version, dirUri string) lang.DiagnosticsMap {
+	diagsMap := make(lang.DiagnosticsMap)
+
+	for filename, f := range files {
+		body, ok := f.Body.(*hclsyntax.Body)
+		if !ok {
+			continue
+		}
+
+		for _, attr := range requiredProviderAttributes(body) {
+			pAddr, ok := refs[tfmod.ProviderRef{LocalName: attr.Name}]
+			if !ok {
+				continue
+			}
+
+			var diag *hcl.Diagnostic
+			constraints := requirements[pAddr]
+			lockedVersion, ok := locked[pAddr]
+			if !ok {
+				diag = &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  fmt.Sprintf("Provider %s is missing from the lock file", pAddr.ForDisplay()),
+					Detail:   "The provider was added to required_providers after the module was initialized.",
+				}
+			} else if len(constraints) > 0 && !constraints.Check(lockedVersion) {
+				diag = &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  fmt.Sprintf("Locked version of provider %s doesn't match required_providers", pAddr.ForDisplay()),
+					Detail: fmt.Sprintf("Version %s is locked, which doesn't satisfy the constraints %q.",
+						lockedVersion, constraints.String()),
+				}
+			}
+			if diag == nil {
+				continue
+			}
+
+			diag.Subject = attr.SrcRange.Ptr()
+			diag.Extra = QuickFixes{
+				{
+					Title: "Run tofu init -upgrade",
+					Command: &lang.Command{
+						Title: "Run tofu init -upgrade",
+						ID:    cmd.Name("tofu.init"),
+						Arguments: []lang.CommandArgument{
+							commandArgument(fmt.Sprintf("uri=%s", dirUri)),
+							commandArgument("upgrade=true"),
+						},
+					},
+				},
+			}
+			diagsMap[filename] = append(diagsMap[filename], diag)
+		}
+	}
+
+	return diagsMap
+}
+
+// requiredProviderAttributes returns the entries of all
+// required_providers blocks within terraform blocks
+func requiredProviderAttributes(body *hclsyntax.Body) []*hclsyntax.Attribute {
+	attrs := make([]*hclsyntax.Attribute, 0)
+	for _, block := range body.Blocks {
+		if block.Type != "terraform" {
+			continue
+		}
+		for _, innerBlock := range block.Body.Blocks {
+			if innerBlock.Type != "required_providers" {
+				continue
+			}
+			for _, attr := range innerBlock.Body.Attributes {
+				attrs = append(attrs, attr)
+			}
+		}
+	}
+	sort.Slice(attrs, func(i, j int) bool {
+		return attrs[i].SrcRange.Start.Byte < attrs[j].SrcRange.Start.Byte
+	})
+	return attrs
+}
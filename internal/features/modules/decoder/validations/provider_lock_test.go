@@ -0,0 +1,114 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package validations
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	tfmod "github.com/opentofu/opentofu-schema/module"
+	tfaddr "github.com/opentofu/registry-address"
+)
+
+func TestProviderLockDrift(t *testing.T) {
+	src := []byte(`terraform {
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = "~> 5.0"
+    }
+    random = {
+      source = "hashicorp/random"
+    }
+    null = {
+      source  = "hashicorp/null"
+      version = ">= 3.0"
+    }
+  }
+}
+`)
+	f, pDiags := hclsyntax.ParseConfig(src, "main.tf", hcl.InitialPos)
+	if len(pDiags) > 0 {
+		t.Fatal(pDiags)
+	}
+
+	awsAddr := tfaddr.MustParseProviderSource("hashicorp/aws")
+	randomAddr := tfaddr.MustParseProviderSource("hashicorp/random")
+	nullAddr := tfaddr.MustParseProviderSource("hashicorp/null")
+
+	refs := map[tfmod.ProviderRef]tfaddr.Provider{
+		{LocalName: "aws"}:    awsAddr,
+		{LocalName: "random"}: randomAddr,
+		{LocalName: "null"}:   nullAddr,
+	}
+	requirements := tfmod.ProviderRequirements{
+		awsAddr:    version.MustConstraints(version.NewConstraint("~> 5.0")),
+		randomAddr: version.Constraints{},
+		nullAddr:   version.MustConstraints(version.NewConstraint(">= 3.0")),
+	}
+	locked := map[tfaddr.Provider]*version.Version{
+		awsAddr:  version.Must(version.NewVersion("4.67.0")),
+		nullAddr: version.Must(version.NewVersion("3.2.1")),
+	}
+
+	diags := ProviderLockDrift(map[string]*hcl.File{"main.tf": f}, refs, requirements, locked, "file:///test")
+
+	type result struct {
+		Summary string
+		Detail  string
+		Subject hcl.Range
+		Fix     string
+		Args    []string
+	}
+	results := make([]result, 0)
+	for _, diag := range diags["main.tf"] {
+		r := result{
+			Summary: diag.Summary,
+			Detail:  diag.Detail,
+			Subject: *diag.Subject,
+		}
+		for _, fix := range DiagnosticQuickFixes(diag) {
+			r.Fix = fix.Command.ID
+			for _, arg := range fix.Command.Arguments {
+				b, err := arg.MarshalJSON()
+				if err != nil {
+					t.Fatal(err)
+				}
+				r.Args = append(r.Args, string(b))
+			}
+		}
+		results = append(results, r)
+	}
+
+	expectedResults := []result{
+		{
+			Summary: "Locked version of provider hashicorp/aws doesn't match required_providers",
+			Detail:  `Version 4.67.0 is locked, which doesn't satisfy the constraints "~> 5.0".`,
+			Subject: hcl.Range{
+				Filename: "main.tf",
+				Start:    hcl.Pos{Line: 3, Column: 5, Byte: 39},
+				End:      hcl.Pos{Line: 6, Column: 6, Byte: 109},
+			},
+			Fix:  "tofu-ls.tofu.init",
+			Args: []string{`"uri=file:///test"`, `"upgrade=true"`},
+		},
+		{
+			Summary: "Provider hashicorp/random is missing from the lock file",
+			Detail:  "The provider was added to required_providers after the module was initialized.",
+			Subject: hcl.Range{
+				Filename: "main.tf",
+				Start:    hcl.Pos{Line: 7, Column: 5, Byte: 114},
+				End:      hcl.Pos{Line: 9, Column: 6, Byte: 164},
+			},
+			Fix:  "tofu-ls.tofu.init",
+			Args: []string{`"uri=file:///test"`, `"upgrade=true"`},
+		},
+	}
+	if diff := cmp.Diff(expectedResults, results); diff != "" {
+		t.Fatalf("unexpected diagnostics: %s", diff)
+	}
+}
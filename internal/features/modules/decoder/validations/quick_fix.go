@@ -0,0 +1,52 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package validations
+
+import (
+	"encoding/json"
+
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
+)
+
+// QuickFix represents a change which resolves the problem
+// reported by a diagnostic, such as declaring a missing variable.
+type QuickFix struct {
+	Title string
+	// Edits to apply, where the filename of each range
+	// is relative to the module directory
+	Edits []lang.TextEdit
+	// Command to execute after applying any edits, such as
+	// a command of the language server without its prefix
+	Command *lang.Command
+}
+
+// QuickFixes is attached to diagnostics as [hcl.Diagnostic.Extra]
+// so that fixes can be offered when the client asks for code actions
+// without having to re-validate the module.
+type QuickFixes []QuickFix
+
+// DiagnosticQuickFixes returns fixes attached to the given diagnostic, if any.
+func DiagnosticQuickFixes(diag *hcl.Diagnostic) QuickFixes {
+	fixes, _ := hcl.DiagnosticExtra[QuickFixes](diag)
+	return fixes
+}
+
+type commandArgument string
+
+func (a commandArgument) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(a))
+}
+
+func insertEdit(pos hcl.Pos, filename, text string) lang.TextEdit {
+	return lang.TextEdit{
+		Range: hcl.Range{
+			Filename: filename,
+			Start:    pos,
+			End:      pos,
+		},
+		NewText: text,
+		Snippet: text,
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/hashicorp/hcl-lang/lang"
 	"github.com/hashicorp/hcl-lang/reference"
 	"github.com/hashicorp/hcl/v2"
+	idecoder "github.com/opentofu/tofu-ls/internal/decoder"
 )
 
 func UnreferencedOrigins(ctx context.Context, pathCtx *decoder.PathContext) lang.DiagnosticsMap {
@@ -66,6 +67,11 @@ func UnreferencedOrigins(ctx context.Context, pathCtx *decoder.PathContext) lang
 				Summary:  fmt.Sprintf("No declaration found for %q", address),
 				Subject:  origin.OriginRange().Ptr(),
 			}
+			if nameStep, ok := address[len(address)-1].(lang.AttrStep); ok && firstStep == "var" {
+				if fix, ok := declareVariableFix(pathCtx, fileName, nameStep.Name); ok {
+					d.Extra = QuickFixes{fix}
+				}
+			}
 			diagsMap[fileName] = diagsMap[fileName].Append(d)
 
 			continue
@@ -75,3 +81,33 @@ func UnreferencedOrigins(ctx context.Context, pathCtx *decoder.PathContext) lang
 
 	return diagsMap
 }
+
+// declareVariableFix appends a variable block to variables.tf,
+// or to the file with the reference if there is no such file.
+func declareVariableFix(pathCtx *decoder.PathContext, originFile, name string) (QuickFix, bool) {
+	filename := "variables.tf"
+	f, ok := pathCtx.Files[filename]
+	if !ok {
+		filename = originFile
+		f, ok = pathCtx.Files[filename]
+		if !ok {
+			return QuickFix{}, false
+		}
+	}
+
+	text := fmt.Sprintf("variable %q {\n}\n", name)
+	if len(f.Bytes) > 0 {
+		// Separate the block from any preceding one
+		text = "\n" + text
+		if f.Bytes[len(f.Bytes)-1] != '\n' {
+			text = "\n" + text
+		}
+	}
+
+	return QuickFix{
+		Title: fmt.Sprintf("Declare variable %q", name),
+		Edits: []lang.TextEdit{
+			insertEdit(idecoder.EndOfFile(f.Bytes), filename, text),
+		},
+	}, true
+}
@@ -0,0 +1,74 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package validations
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/hashicorp/hcl-lang/decoder"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/reference"
+	"github.com/hashicorp/hcl/v2"
+	idecoder "github.com/opentofu/tofu-ls/internal/decoder"
+)
+
+// UnreferencedTargets reports variables and local values
+// which are declared, but not referenced anywhere in the module.
+func UnreferencedTargets(ctx context.Context, pathCtx *decoder.PathContext) lang.DiagnosticsMap {
+	diagsMap := make(lang.DiagnosticsMap)
+
+	referenced := make([]lang.Address, 0)
+	for _, origin := range pathCtx.ReferenceOrigins {
+		localOrigin, ok := origin.(reference.LocalOrigin)
+		if !ok || len(localOrigin.Addr) < 2 {
+			continue
+		}
+		referenced = append(referenced, localOrigin.Addr.FirstSteps(2))
+	}
+
+	// Declarations may be represented by more targets with the same address
+	reported := make([]lang.Address, 0)
+
+	supported := []string{"var", "local"}
+	for _, target := range pathCtx.ReferenceTargets {
+		if len(target.Addr) != 2 || target.DefRangePtr == nil || target.RangePtr == nil {
+			continue
+		}
+		if !slices.Contains(supported, target.Addr[0].String()) {
+			continue
+		}
+
+		sameAddr := func(addr lang.Address) bool {
+			return addr.Equals(target.Addr)
+		}
+		if slices.ContainsFunc(referenced, sameAddr) || slices.ContainsFunc(reported, sameAddr) {
+			continue
+		}
+		reported = append(reported, target.Addr)
+
+		fileName := target.DefRangePtr.Filename
+		d := &hcl.Diagnostic{
+			Severity: hcl.DiagWarning,
+			Summary:  fmt.Sprintf("%q is declared but not used", target.Addr),
+			Subject:  target.DefRangePtr,
+		}
+		if f, ok := pathCtx.Files[fileName]; ok {
+			d.Extra = QuickFixes{
+				{
+					Title: fmt.Sprintf("Remove unused declaration of %q", target.Addr),
+					Edits: []lang.TextEdit{
+						{
+							Range: idecoder.WholeLinesRange(f.Bytes, *target.RangePtr),
+						},
+					},
+				},
+			}
+		}
+		diagsMap[fileName] = diagsMap[fileName].Append(d)
+	}
+
+	return diagsMap
+}
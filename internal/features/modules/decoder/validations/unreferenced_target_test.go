@@ -0,0 +1,108 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package validations
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl-lang/decoder"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/reference"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestUnreferencedTargets(t *testing.T) {
+	src := []byte("variable \"foo\" {\n}\n\nvariable \"bar\" {\n}\n")
+	fooAddr := lang.Address{
+		lang.RootStep{Name: "var"},
+		lang.AttrStep{Name: "foo"},
+	}
+	barAddr := lang.Address{
+		lang.RootStep{Name: "var"},
+		lang.AttrStep{Name: "bar"},
+	}
+	barDefRange := hcl.Range{
+		Filename: "test.tf",
+		Start:    hcl.Pos{Line: 4, Column: 1, Byte: 20},
+		End:      hcl.Pos{Line: 4, Column: 15, Byte: 34},
+	}
+	barRange := hcl.Range{
+		Filename: "test.tf",
+		Start:    hcl.Pos{Line: 4, Column: 1, Byte: 20},
+		End:      hcl.Pos{Line: 5, Column: 2, Byte: 38},
+	}
+
+	pathCtx := &decoder.PathContext{
+		Files: map[string]*hcl.File{
+			"test.tf": {Bytes: src},
+		},
+		ReferenceTargets: reference.Targets{
+			{
+				Addr: fooAddr,
+				RangePtr: &hcl.Range{
+					Filename: "test.tf",
+					Start:    hcl.Pos{Line: 1, Column: 1, Byte: 0},
+					End:      hcl.Pos{Line: 2, Column: 2, Byte: 18},
+				},
+				DefRangePtr: &hcl.Range{
+					Filename: "test.tf",
+					Start:    hcl.Pos{Line: 1, Column: 1, Byte: 0},
+					End:      hcl.Pos{Line: 1, Column: 15, Byte: 14},
+				},
+			},
+			// Variables are targetable as both type-aware and type-unaware
+			{
+				Addr:        barAddr,
+				RangePtr:    barRange.Ptr(),
+				DefRangePtr: barDefRange.Ptr(),
+			},
+			{
+				Addr:        barAddr,
+				Type:        cty.String,
+				RangePtr:    barRange.Ptr(),
+				DefRangePtr: barDefRange.Ptr(),
+			},
+		},
+		ReferenceOrigins: reference.Origins{
+			reference.LocalOrigin{
+				Addr: lang.Address{
+					lang.RootStep{Name: "var"},
+					lang.AttrStep{Name: "foo"},
+					lang.AttrStep{Name: "attr"},
+				},
+				Range: hcl.Range{Filename: "other.tf"},
+			},
+		},
+	}
+
+	expectedDiags := hcl.Diagnostics{
+		&hcl.Diagnostic{
+			Severity: hcl.DiagWarning,
+			Summary:  "\"var.bar\" is declared but not used",
+			Subject:  barDefRange.Ptr(),
+			Extra: QuickFixes{
+				{
+					Title: "Remove unused declaration of \"var.bar\"",
+					Edits: []lang.TextEdit{
+						{
+							Range: hcl.Range{
+								Filename: "test.tf",
+								Start:    hcl.Pos{Line: 4, Column: 1, Byte: 20},
+								End:      hcl.Pos{Line: 6, Column: 1, Byte: 39},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	diags := UnreferencedTargets(context.Background(), pathCtx)
+	if diff := cmp.Diff(expectedDiags, diags["test.tf"]); diff != "" {
+		t.Fatalf("unexpected diagnostics: %s", diff)
+	}
+}
@@ -12,8 +12,6 @@ import (
 	"path/filepath"
 
 	"github.com/hashicorp/go-multierror"
-	tfmod "github.com/opentofu/opentofu-schema/module"
-	tfaddr "github.com/opentofu/registry-address"
 	lsctx "github.com/opentofu/tofu-ls/internal/context"
 	"github.com/opentofu/tofu-ls/internal/document"
 	"github.com/opentofu/tofu-ls/internal/features/modules/ast"
@@ -161,6 +159,31 @@ func (f *ModulesFeature) didChangeWatched(ctx context.Context, rawPath string, c
 	return ids, nil
 }
 
+// pluginLockChange revalidates the module against its changed lock file
+func (f *ModulesFeature) pluginLockChange(ctx context.Context, dir document.DirHandle) (job.IDs, error) {
+	ids := make(job.IDs, 0)
+
+	validationOptions, _ := lsctx.ValidationOptions(ctx)
+	if !validationOptions.EnableEnhancedValidation || !f.Store.Exists(dir.Path()) {
+		return ids, nil
+	}
+
+	id, err := f.stateStore.JobStore.EnqueueJob(ctx, job.Job{
+		Dir: dir,
+		Func: func(ctx context.Context) error {
+			return jobs.ProviderLockValidation(ctx, f.fs, f.Store, dir.Path())
+		},
+		Type:        op.OpTypeProviderLockValidation.String(),
+		IgnoreState: true,
+	})
+	if err != nil {
+		return ids, err
+	}
+	ids = append(ids, id)
+
+	return ids, nil
+}
+
 func (f *ModulesFeature) removeIndexedModule(rawPath string) {
 	modHandle := document.DirHandleFromPath(rawPath)
 
@@ -188,27 +211,8 @@ func (f *ModulesFeature) decodeDeclaredModuleCalls(ctx context.Context, dir docu
 	var errs *multierror.Error
 
 	for _, mc := range declared {
-		var mcPath string
-		switch source := mc.SourceAddr.(type) {
-		// For local module sources, we can construct the path directly from the configuration
-		case tfmod.LocalSourceAddr:
-			mcPath = filepath.Join(dir.Path(), filepath.FromSlash(source.String()))
-		// For registry modules, we need to find the local installation path (if installed)
-		case tfaddr.Module:
-			installedDir, ok := f.rootFeature.InstalledModulePath(dir.Path(), source.String())
-			if !ok {
-				continue
-			}
-			mcPath = filepath.Join(dir.Path(), filepath.FromSlash(installedDir))
-		// For other remote modules, we need to find the local installation path (if installed)
-		case tfmod.RemoteSourceAddr:
-			installedDir, ok := f.rootFeature.InstalledModulePath(dir.Path(), source.String())
-			if !ok {
-				continue
-			}
-			mcPath = filepath.Join(dir.Path(), filepath.FromSlash(installedDir))
-		default:
-			// Unknown source address, we can't resolve the path
+		mcPath, ok := f.ModuleCallPath(dir.Path(), mc)
+		if !ok {
 			continue
 		}
 
@@ -306,6 +310,25 @@ func (f *ModulesFeature) decodeModule(ctx context.Context, dir document.DirHandl
 			}
 			deferIds = append(deferIds, eSchemaId)
 
+			if isFirstLevel && f.schemaMirrorURL != "" {
+				// This job may make HTTP requests, so we schedule it in
+				// the low-priority queue and don't wait for it.
+				_, err = f.stateStore.JobStore.EnqueueJob(ctx, job.Job{
+					Dir: dir,
+					Func: func(ctx context.Context) error {
+						return jobs.FetchProviderSchemas(ctx, f.logger, f.registryClient, f.schemaMirrorURL,
+							f.schemaCache, f.Store, f.stateStore.ProviderSchemas, path)
+					},
+					Priority:    job.LowPriority,
+					Type:        op.OpTypeFetchProviderSchemas.String(),
+					DependsOn:   job.IDs{eSchemaId},
+					IgnoreState: ignoreState,
+				})
+				if err != nil {
+					return deferIds, err
+				}
+			}
+
 			refTargetsId, err := f.stateStore.JobStore.EnqueueJob(ctx, job.Job{
 				Dir: dir,
 				Func: func(ctx context.Context) error {
@@ -361,6 +384,18 @@ func (f *ModulesFeature) decodeModule(ctx context.Context, dir document.DirHandl
 				if err != nil {
 					return deferIds, err
 				}
+
+				_, err = f.stateStore.JobStore.EnqueueJob(ctx, job.Job{
+					Dir: dir,
+					Func: func(ctx context.Context) error {
+						return jobs.ProviderLockValidation(ctx, f.fs, f.Store, dir.Path())
+					},
+					Type:        op.OpTypeProviderLockValidation.String(),
+					IgnoreState: ignoreState,
+				})
+				if err != nil {
+					return deferIds, err
+				}
 			}
 
 			return deferIds, nil
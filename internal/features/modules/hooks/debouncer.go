@@ -0,0 +1,49 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package hooks
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Debouncer delays calls, such as registry searches triggered
+// by completion requests while typing, until no other call
+// was made for the duration of the delay.
+type Debouncer struct {
+	delay time.Duration
+
+	mu     sync.Mutex
+	latest uint64
+}
+
+func NewDebouncer(delay time.Duration) *Debouncer {
+	return &Debouncer{
+		delay: delay,
+	}
+}
+
+// Wait blocks for the duration of the delay and reports whether
+// the caller should proceed, i.e. it wasn't superseded by a more
+// recent call to Wait and the context wasn't cancelled meanwhile.
+func (d *Debouncer) Wait(ctx context.Context) bool {
+	d.mu.Lock()
+	d.latest++
+	call := d.latest
+	d.mu.Unlock()
+
+	timer := time.NewTimer(d.delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return call == d.latest
+}
@@ -12,12 +12,15 @@ import (
 	"log"
 
 	"github.com/opentofu/tofu-ls/internal/registry"
+	globalState "github.com/opentofu/tofu-ls/internal/state"
 
 	"github.com/opentofu/tofu-ls/internal/features/modules/state"
 )
 
 type Hooks struct {
-	ModStore       *state.ModuleStore
-	RegistryClient registry.Client
-	Logger         *log.Logger
+	ModStore        *state.ModuleStore
+	RegistryModules *globalState.RegistryModuleStore
+	RegistryClient  registry.Client
+	SearchDebouncer *Debouncer
+	Logger          *log.Logger
 }
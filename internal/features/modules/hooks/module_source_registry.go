@@ -7,17 +7,16 @@ package hooks
 
 import (
 	"context"
+	"fmt"
 	"strings"
 
+	"github.com/hashicorp/go-version"
 	"github.com/hashicorp/hcl-lang/decoder"
+	"github.com/hashicorp/hcl-lang/lang"
+	globalState "github.com/opentofu/tofu-ls/internal/state"
 	"github.com/zclconf/go-cty/cty"
 )
 
-type RegistryModule struct {
-	FullName    string `json:"full-name"`
-	Description string `json:"description"`
-}
-
 func (h *Hooks) RegistryModuleSources(ctx context.Context, value cty.Value) ([]decoder.Candidate, error) {
 	candidates := make([]decoder.Candidate, 0)
 	prefix := value.AsString()
@@ -27,7 +26,89 @@ func (h *Hooks) RegistryModuleSources(ctx context.Context, value cty.Value) ([]d
 		// A search for "." will not return any results
 		return candidates, nil
 	}
+	if !isRegistrySearchPrefix(prefix) {
+		return candidates, nil
+	}
+
+	results, err := h.RegistryModules.SearchResults(prefix)
+	if err != nil {
+		if !globalState.IsRecordNotFound(err) {
+			return candidates, err
+		}
+
+		// Only the last of several completion requests sent while
+		// typing a source address results in a registry search.
+		if h.SearchDebouncer != nil && !h.SearchDebouncer.Wait(ctx) {
+			return candidates, nil
+		}
+
+		results, err = h.searchRegistryModules(ctx, prefix)
+		if err != nil {
+			return candidates, err
+		}
+	}
+
+	maxCandidates, ok := decoder.MaxCandidatesFromContext(ctx)
+	for i, result := range results {
+		if ok && uint(i) >= maxCandidates {
+			break
+		}
+
+		detail := "registry"
+		if result.LatestVersion != nil {
+			detail = fmt.Sprintf("registry (latest: %s)", result.LatestVersion)
+		}
+		candidates = append(candidates, decoder.Candidate{
+			Label:         fmt.Sprintf("%q", result.Source),
+			Detail:        detail,
+			Kind:          lang.StringCandidateKind,
+			Description:   lang.PlainText(result.Description),
+			RawInsertText: fmt.Sprintf("%q", result.Source),
+			// Keep the order of relevance as returned by the registry
+			SortText: fmt.Sprintf("%3d", i),
+		})
+	}
 
-	// TODO: Because we disabled the old logic that was fetching modules from Algolia, we'll need to figure out our own solution for modules auto completion
 	return candidates, nil
 }
+
+// searchRegistryModules searches the registry for modules matching
+// the prefix and caches the results
+func (h *Hooks) searchRegistryModules(ctx context.Context, prefix string) ([]globalState.RegistryModuleSearchResult, error) {
+	modules, err := h.RegistryClient.SearchModules(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]globalState.RegistryModuleSearchResult, 0, len(modules))
+	for _, mod := range modules {
+		result := globalState.RegistryModuleSearchResult{
+			Source:      mod.Addr,
+			Description: mod.Description,
+		}
+		if v, err := version.NewVersion(mod.Version); err == nil {
+			result.LatestVersion = v
+		}
+		results = append(results, result)
+	}
+
+	err = h.RegistryModules.CacheSearchResults(prefix, results)
+	if err != nil {
+		h.Logger.Printf("failed to cache module search results for %q: %s", prefix, err)
+	}
+
+	return results, nil
+}
+
+// isRegistrySearchPrefix checks whether the prefix of a source address
+// could be completed to the address of a module in the registry, as
+// opposed to e.g. a Git repository or archive URL.
+func isRegistrySearchPrefix(prefix string) bool {
+	if strings.TrimSpace(prefix) == "" {
+		return false
+	}
+	if strings.HasPrefix(prefix, "/") || strings.HasPrefix(prefix, "git@") {
+		return false
+	}
+	return !strings.Contains(prefix, "::") && !strings.Contains(prefix, "://")
+}
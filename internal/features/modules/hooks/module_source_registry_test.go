@@ -7,10 +7,15 @@ package hooks
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"log"
 	"net"
+	"net/http"
+	"net/http/httptest"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -18,12 +23,64 @@ import (
 	"github.com/hashicorp/hcl-lang/decoder"
 	"github.com/hashicorp/hcl-lang/lang"
 	"github.com/opentofu/tofu-ls/internal/features/modules/state"
+	"github.com/opentofu/tofu-ls/internal/registry"
 	globalState "github.com/opentofu/tofu-ls/internal/state"
 	"github.com/zclconf/go-cty/cty"
 )
 
+// moduleSearchMockResponse represents the shortened response from https://api.opentofu.org/registry/docs/search?q=aws
+var moduleSearchMockResponse = `[
+  {
+    "id": "terraform-aws-modules/vpc/aws",
+    "type": "module",
+    "addr": "terraform-aws-modules/vpc/aws",
+    "version": "v5.21.0",
+    "title": "vpc",
+    "description": "Terraform module which creates VPC resources on AWS"
+  },
+  {
+    "id": "providers/hashicorp/aws",
+    "type": "provider",
+    "addr": "hashicorp/aws",
+    "version": "v5.97.0",
+    "title": "aws",
+    "description": "The AWS provider"
+  },
+  {
+    "id": "terraform-aws-modules/eks/aws",
+    "type": "module",
+    "addr": "terraform-aws-modules/eks/aws",
+    "version": "v20.36.0",
+    "title": "eks",
+    "description": "Terraform module to create an Elastic Kubernetes (EKS) cluster and associated resources"
+  }
+]`
+
+func newRegistrySearchServer(t *testing.T, requests *atomic.Int32) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		switch r.RequestURI {
+		case "/registry/docs/search?q=aws":
+			w.Write([]byte(moduleSearchMockResponse))
+			return
+		case "/registry/docs/search?q=foo":
+			w.Write([]byte(`[]`))
+			return
+		case "/registry/docs/search?q=slow":
+			time.Sleep(500 * time.Millisecond)
+			w.Write([]byte(`[]`))
+			return
+		case "/registry/docs/search?q=err":
+			http.Error(w, "unauthorized", 401)
+			return
+		}
+		http.Error(w, fmt.Sprintf("unexpected request: %q", r.RequestURI), 400)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
 func TestHooks_RegistryModuleSources(t *testing.T) {
-	t.Skip("Skipping because currently we don't support modules auto completion")
 	ctx := context.Background()
 
 	s, err := globalState.NewStateStore()
@@ -35,9 +92,15 @@ func TestHooks_RegistryModuleSources(t *testing.T) {
 		t.Fatal(err)
 	}
 
+	var requests atomic.Int32
+	regClient := registry.NewClient()
+	regClient.BaseAPIURL = newRegistrySearchServer(t, &requests).URL
+
 	h := &Hooks{
-		ModStore: store,
-		Logger:   log.New(io.Discard, "", 0),
+		ModStore:        store,
+		RegistryModules: s.RegistryModules,
+		RegistryClient:  regClient,
+		Logger:          log.New(io.Discard, "", 0),
 	}
 
 	tests := []struct {
@@ -52,17 +115,19 @@ func TestHooks_RegistryModuleSources(t *testing.T) {
 			[]decoder.Candidate{
 				{
 					Label:         `"terraform-aws-modules/vpc/aws"`,
-					Detail:        "registry",
+					Detail:        "registry (latest: 5.21.0)",
 					Kind:          lang.StringCandidateKind,
 					Description:   lang.PlainText("Terraform module which creates VPC resources on AWS"),
 					RawInsertText: `"terraform-aws-modules/vpc/aws"`,
+					SortText:      "  0",
 				},
 				{
 					Label:         `"terraform-aws-modules/eks/aws"`,
-					Detail:        "registry",
+					Detail:        "registry (latest: 20.36.0)",
 					Kind:          lang.StringCandidateKind,
 					Description:   lang.PlainText("Terraform module to create an Elastic Kubernetes (EKS) cluster and associated resources"),
 					RawInsertText: `"terraform-aws-modules/eks/aws"`,
+					SortText:      "  1",
 				},
 			},
 			false,
@@ -79,6 +144,12 @@ func TestHooks_RegistryModuleSources(t *testing.T) {
 			[]decoder.Candidate{},
 			true,
 		},
+		{
+			"git source",
+			cty.StringVal("git::https://example.com/vpc.git"),
+			[]decoder.Candidate{},
+			false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -95,10 +166,72 @@ func TestHooks_RegistryModuleSources(t *testing.T) {
 			}
 		})
 	}
+
+	// Repeated searches are served from the cache
+	requests.Store(0)
+	candidates, err := h.RegistryModuleSources(ctx, cty.StringVal("aws"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(candidates) != 2 {
+		t.Fatalf("expected 2 candidates, given: %#v", candidates)
+	}
+	if requests.Load() != 0 {
+		t.Fatalf("expected no registry requests, given: %d", requests.Load())
+	}
+}
+
+func TestHooks_RegistryModuleSourcesDebounce(t *testing.T) {
+	ctx := context.Background()
+
+	s, err := globalState.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	store, err := state.NewModuleStore(s.ProviderSchemas, s.RegistryModules, s.ChangeStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var requests atomic.Int32
+	regClient := registry.NewClient()
+	regClient.BaseAPIURL = newRegistrySearchServer(t, &requests).URL
+
+	h := &Hooks{
+		ModStore:        store,
+		RegistryModules: s.RegistryModules,
+		RegistryClient:  regClient,
+		SearchDebouncer: NewDebouncer(50 * time.Millisecond),
+		Logger:          log.New(io.Discard, "", 0),
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	var firstCandidates []decoder.Candidate
+	go func() {
+		defer wg.Done()
+		firstCandidates, _ = h.RegistryModuleSources(ctx, cty.StringVal("foo"))
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	candidates, err := h.RegistryModuleSources(ctx, cty.StringVal("aws"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wg.Wait()
+
+	if len(firstCandidates) != 0 {
+		t.Fatalf("expected superseded search to return no candidates, given: %#v", firstCandidates)
+	}
+	if len(candidates) != 2 {
+		t.Fatalf("expected 2 candidates, given: %#v", candidates)
+	}
+	if requests.Load() != 1 {
+		t.Fatalf("expected a single registry request, given: %d", requests.Load())
+	}
 }
 
 func TestHooks_RegistryModuleSourcesCtxCancel(t *testing.T) {
-	t.Skip("Skipping because currently we don't support modules auto completion")
 	ctx := context.Background()
 	ctx, cancelFunc := context.WithTimeout(ctx, 50*time.Millisecond)
 	t.Cleanup(cancelFunc)
@@ -112,12 +245,18 @@ func TestHooks_RegistryModuleSourcesCtxCancel(t *testing.T) {
 		t.Fatal(err)
 	}
 
+	var requests atomic.Int32
+	regClient := registry.NewClient()
+	regClient.BaseAPIURL = newRegistrySearchServer(t, &requests).URL
+
 	h := &Hooks{
-		ModStore: store,
-		Logger:   log.New(io.Discard, "", 0),
+		ModStore:        store,
+		RegistryModules: s.RegistryModules,
+		RegistryClient:  regClient,
+		Logger:          log.New(io.Discard, "", 0),
 	}
 
-	_, err = h.RegistryModuleSources(ctx, cty.StringVal("aws"))
+	_, err = h.RegistryModuleSources(ctx, cty.StringVal("slow"))
 	e, ok := err.(net.Error)
 	if !ok {
 		t.Fatalf("expected error, got %#v", err)
@@ -141,8 +280,9 @@ func TestHooks_RegistryModuleSourcesIgnore(t *testing.T) {
 	}
 
 	h := &Hooks{
-		ModStore: store,
-		Logger:   log.New(io.Discard, "", 0),
+		ModStore:        store,
+		RegistryModules: s.RegistryModules,
+		Logger:          log.New(io.Discard, "", 0),
 	}
 
 	tests := []struct {
@@ -0,0 +1,113 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package jobs
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/opentofu/tofu-exec/tfexec"
+	"github.com/opentofu/tofu-ls/internal/features/modules/ast"
+	"github.com/opentofu/tofu-ls/internal/features/modules/state"
+	"github.com/opentofu/tofu-ls/internal/langserver/diagnostics"
+	globalAst "github.com/opentofu/tofu-ls/internal/tofu/ast"
+	"github.com/opentofu/tofu-ls/internal/tofu/module"
+	op "github.com/opentofu/tofu-ls/internal/tofu/module/operation"
+)
+
+// TofuPlan runs tofu plan for the root module at modPath and stores
+// the resulting diagnostics separately from those of tofu validate.
+//
+// Diagnostics in files of other modules, such as local modules called
+// by the root module, are stored with those modules, if they're known.
+func TofuPlan(ctx context.Context, modStore *state.ModuleStore, modPath string, opts ...tfexec.PlanOption) error {
+	mod, err := modStore.ModuleRecordByPath(modPath)
+	if err != nil {
+		return err
+	}
+
+	err = modStore.SetModuleDiagnosticsState(modPath, globalAst.TofuPlanSource, op.OpStateLoading)
+	if err != nil {
+		return err
+	}
+	// Diagnostics of the previous plan are kept if this one fails,
+	// but they must not remain loading in that case either.
+	defer modStore.SetModuleDiagnosticsState(modPath, globalAst.TofuPlanSource, op.OpStateLoaded)
+
+	tfExec, err := module.TofuExecutorForModule(ctx, mod.Path())
+	if err != nil {
+		return err
+	}
+
+	jsonDiags, _, planErr := tfExec.Plan(ctx, opts...)
+	if planErr != nil && len(jsonDiags) == 0 {
+		// Without any diagnostics, the plan failed for a reason
+		// which we cannot point to in the configuration.
+		return planErr
+	}
+	planDiags := diagnostics.HCLDiagsFromJSON(jsonDiags)
+
+	mods, err := modStore.List()
+	if err != nil {
+		return err
+	}
+
+	// Diagnostics of previous plans are replaced for any module within
+	// the root module, so that those which were resolved are cleared.
+	modDiags := make(map[string]ast.ModDiags, 0)
+	modDiags[modPath] = make(ast.ModDiags)
+	for _, m := range mods {
+		if isWithinDir(modPath, m.Path()) {
+			modDiags[m.Path()] = make(ast.ModDiags)
+		}
+	}
+
+	for filename, diags := range planDiags {
+		dirPath := filepath.Join(modPath, filepath.Dir(filename))
+		if _, ok := modDiags[dirPath]; ok && filename != "" {
+			modDiags[dirPath][ast.ModFilename(filepath.Base(filename))] = relativeDiags(diags, modPath, dirPath)
+			continue
+		}
+		modDiags[modPath][ast.ModFilename(filename)] = diags
+	}
+
+	for path, diags := range modDiags {
+		err = modStore.UpdateModuleDiagnostics(path, globalAst.TofuPlanSource, diags)
+		if err != nil {
+			return err
+		}
+	}
+
+	return planErr
+}
+
+// relativeDiags makes the filenames of diagnostic ranges
+// relative to the directory of the module they belong to
+func relativeDiags(diags hcl.Diagnostics, modPath, dirPath string) hcl.Diagnostics {
+	if modPath == dirPath {
+		return diags
+	}
+
+	relDiags := make(hcl.Diagnostics, 0, len(diags))
+	for _, diag := range diags {
+		d := *diag
+		if d.Subject != nil {
+			subject := *d.Subject
+			subject.Filename = filepath.Base(subject.Filename)
+			d.Subject = &subject
+		}
+		relDiags = append(relDiags, &d)
+	}
+	return relDiags
+}
+
+func isWithinDir(dirPath, path string) bool {
+	rel, err := filepath.Rel(dirPath, path)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
@@ -0,0 +1,47 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package jobs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/opentofu/tofu-ls/internal/features/modules/state"
+	globalState "github.com/opentofu/tofu-ls/internal/state"
+	"github.com/opentofu/tofu-ls/internal/tofu/ast"
+	op "github.com/opentofu/tofu-ls/internal/tofu/module/operation"
+)
+
+func TestTofuPlan_failedResetsState(t *testing.T) {
+	ctx := context.Background()
+	gs, err := globalState.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ms, err := state.NewModuleStore(gs.ProviderSchemas, gs.RegistryModules, gs.ChangeStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	modPath := t.TempDir()
+	err = ms.Add(modPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Without an executor in the context, the plan fails early
+	err = TofuPlan(ctx, ms, modPath)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+
+	mod, err := ms.ModuleRecordByPath(modPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mod.ModuleDiagnosticsState[ast.TofuPlanSource] != op.OpStateLoaded {
+		t.Fatalf("expected plan diagnostics to be loaded, given state: %s",
+			mod.ModuleDiagnosticsState[ast.TofuPlanSource])
+	}
+}
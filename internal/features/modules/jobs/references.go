@@ -16,6 +16,7 @@ import (
 	"github.com/opentofu/tofu-ls/internal/features/modules/state"
 	"github.com/opentofu/tofu-ls/internal/job"
 	ilsp "github.com/opentofu/tofu-ls/internal/lsp"
+	globalAst "github.com/opentofu/tofu-ls/internal/tofu/ast"
 	op "github.com/opentofu/tofu-ls/internal/tofu/module/operation"
 )
 
@@ -67,9 +68,32 @@ func DecodeReferenceTargets(ctx context.Context, modStore *state.ModuleStore, ro
 		return sErr
 	}
 
+	// Files with syntax errors are likely being edited and may be missing
+	// some blocks, so renames are only detected between valid versions
+	if !hasParsingErrors(mod) {
+		renames := mod.ResourceRenames
+		if mod.RenamesBaseFiles != nil {
+			renames = detectResourceRenames(mod.ResourceRenames, mod.RenamesBaseTargets, mod.RenamesBaseFiles,
+				targets, mod.ParsedModuleFiles)
+		}
+		sErr = modStore.UpdateResourceRenames(modPath, renames, targets, mod.ParsedModuleFiles)
+		if sErr != nil {
+			return sErr
+		}
+	}
+
 	return rErr
 }
 
+func hasParsingErrors(mod *state.ModuleRecord) bool {
+	for _, diags := range mod.ModuleDiagnostics[globalAst.HCLParsingSource] {
+		if diags.HasErrors() {
+			return true
+		}
+	}
+	return false
+}
+
 // DecodeReferenceOrigins collects reference origins,
 // using previously parsed AST (via [ParseModuleConfiguration]),
 // core schema of appropriate version (as obtained via [GetTofuVersion])
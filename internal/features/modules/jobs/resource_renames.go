@@ -0,0 +1,146 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package jobs
+
+import (
+	"slices"
+
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/reference"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/opentofu/tofu-ls/internal/features/modules/ast"
+	"github.com/opentofu/tofu-ls/internal/features/modules/state"
+	ihcl "github.com/opentofu/tofu-ls/internal/hcl"
+)
+
+var resourceScopeId = lang.ScopeId("resource")
+
+// detectResourceRenames compares resources of the base version of the module
+// with the current one and returns the updated list of renames.
+//
+// A resource is considered renamed when its address disappears and a new
+// resource of the same type appears at the corresponding line, such as when
+// its name label is being edited. Renames chain as the user keeps typing,
+// i.e. the original address is kept until a moved block is declared for it,
+// or until the resource gets its original name back.
+func detectResourceRenames(renames []state.ResourceRename, baseTargets reference.Targets, baseFiles ast.ModFiles,
+	targets reference.Targets, files ast.ModFiles) []state.ResourceRename {
+	baseResources := resourceTargets(baseTargets)
+	resources := resourceTargets(targets)
+
+	lineMappings := make(map[string]map[int]int)
+	lineMapping := func(filename string) map[int]int {
+		if mapping, ok := lineMappings[filename]; ok {
+			return mapping
+		}
+		baseFile, ok := baseFiles[ast.ModFilename(filename)]
+		if !ok {
+			return nil
+		}
+		f, ok := files[ast.ModFilename(filename)]
+		if !ok {
+			return nil
+		}
+		lineMappings[filename] = ihcl.LineMapping(filename, baseFile.Bytes, f.Bytes)
+		return lineMappings[filename]
+	}
+
+	detected := make([]state.ResourceRename, 0)
+	for _, base := range baseResources {
+		if hasResource(resources, base.Addr) {
+			continue
+		}
+		defRng := base.DefRangePtr
+		line, ok := lineMapping(defRng.Filename)[defRng.Start.Line]
+		if !ok {
+			continue
+		}
+
+		for _, res := range resources {
+			if res.DefRangePtr.Filename != defRng.Filename || res.DefRangePtr.Start.Line != line {
+				continue
+			}
+			if res.Addr[0].String() != base.Addr[0].String() || hasResource(baseResources, res.Addr) {
+				continue
+			}
+			detected = append(detected, state.ResourceRename{
+				From: base.Addr,
+				To:   res.Addr,
+			})
+		}
+	}
+
+	updated := make([]state.ResourceRename, 0, len(renames)+len(detected))
+	for _, rename := range renames {
+		idx := slices.IndexFunc(detected, func(d state.ResourceRename) bool {
+			return d.From.Equals(rename.To)
+		})
+		if idx >= 0 {
+			rename.To = detected[idx].To
+			detected = slices.Delete(detected, idx, idx+1)
+		}
+		updated = append(updated, rename)
+	}
+	updated = append(updated, detected...)
+
+	moved := movedFromAddresses(files)
+	return slices.DeleteFunc(updated, func(rename state.ResourceRename) bool {
+		return rename.From.Equals(rename.To) ||
+			!hasResource(resources, rename.To) ||
+			hasResource(resources, rename.From) ||
+			slices.ContainsFunc(moved, rename.From.Equals)
+	})
+}
+
+// resourceTargets returns a single target for each declared resource
+func resourceTargets(targets reference.Targets) reference.Targets {
+	resources := make(reference.Targets, 0)
+	for _, target := range targets {
+		if target.ScopeId != resourceScopeId || len(target.Addr) != 2 || target.DefRangePtr == nil {
+			continue
+		}
+		if hasResource(resources, target.Addr) {
+			continue
+		}
+		resources = append(resources, target)
+	}
+	return resources
+}
+
+func hasResource(resources reference.Targets, addr lang.Address) bool {
+	return slices.ContainsFunc(resources, func(target reference.Target) bool {
+		return target.Addr.Equals(addr)
+	})
+}
+
+// movedFromAddresses returns the from addresses of all moved blocks
+func movedFromAddresses(files ast.ModFiles) []lang.Address {
+	addrs := make([]lang.Address, 0)
+	for _, f := range files {
+		body, ok := f.Body.(*hclsyntax.Body)
+		if !ok {
+			continue
+		}
+		for _, block := range body.Blocks {
+			if block.Type != "moved" {
+				continue
+			}
+			attr, ok := block.Body.Attributes["from"]
+			if !ok {
+				continue
+			}
+			traversal, diags := hcl.AbsTraversalForExpr(attr.Expr)
+			if diags.HasErrors() {
+				continue
+			}
+			addr, err := lang.TraversalToAddress(traversal)
+			if err != nil {
+				continue
+			}
+			addrs = append(addrs, addr)
+		}
+	}
+	return addrs
+}
@@ -0,0 +1,198 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package jobs
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/reference"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/opentofu/tofu-ls/internal/features/modules/ast"
+	"github.com/opentofu/tofu-ls/internal/features/modules/state"
+)
+
+func TestDetectResourceRenames(t *testing.T) {
+	testCases := []struct {
+		name            string
+		renames         []state.ResourceRename
+		before, after   string
+		expectedRenames []state.ResourceRename
+	}{
+		{
+			"no change",
+			nil,
+			`resource "aws_instance" "web" {
+}
+`,
+			`resource "aws_instance" "web" {
+}
+`,
+			[]state.ResourceRename{},
+		},
+		{
+			"name label edited",
+			nil,
+			`resource "aws_instance" "web" {
+  ami = "ami-123"
+}
+`,
+			`resource "aws_instance" "server" {
+  ami = "ami-123"
+}
+`,
+			[]state.ResourceRename{
+				{From: resourceAddr("aws_instance", "web"), To: resourceAddr("aws_instance", "server")},
+			},
+		},
+		{
+			"lines added above",
+			nil,
+			`resource "aws_vpc" "main" {
+}
+
+resource "aws_instance" "web" {
+}
+`,
+			`# VPC
+resource "aws_vpc" "main" {
+}
+
+resource "aws_instance" "server" {
+}
+`,
+			[]state.ResourceRename{
+				{From: resourceAddr("aws_instance", "web"), To: resourceAddr("aws_instance", "server")},
+			},
+		},
+		{
+			"resource replaced by another type",
+			nil,
+			`resource "aws_instance" "web" {
+}
+`,
+			`resource "aws_vpc" "web" {
+}
+`,
+			[]state.ResourceRename{},
+		},
+		{
+			"resource replaced by different block",
+			nil,
+			`resource "aws_instance" "web" {
+  ami = "ami-123"
+}
+`,
+			`resource "aws_instance" "server" {
+  count = 2
+  ami   = "ami-123"
+}
+`,
+			[]state.ResourceRename{},
+		},
+		{
+			"chained renames",
+			[]state.ResourceRename{
+				{From: resourceAddr("aws_instance", "web"), To: resourceAddr("aws_instance", "serv")},
+			},
+			`resource "aws_instance" "serv" {
+}
+`,
+			`resource "aws_instance" "server" {
+}
+`,
+			[]state.ResourceRename{
+				{From: resourceAddr("aws_instance", "web"), To: resourceAddr("aws_instance", "server")},
+			},
+		},
+		{
+			"renamed back",
+			[]state.ResourceRename{
+				{From: resourceAddr("aws_instance", "web"), To: resourceAddr("aws_instance", "server")},
+			},
+			`resource "aws_instance" "server" {
+}
+`,
+			`resource "aws_instance" "web" {
+}
+`,
+			[]state.ResourceRename{},
+		},
+		{
+			"renamed resource removed",
+			[]state.ResourceRename{
+				{From: resourceAddr("aws_instance", "web"), To: resourceAddr("aws_instance", "server")},
+			},
+			`resource "aws_instance" "server" {
+}
+`,
+			``,
+			[]state.ResourceRename{},
+		},
+		{
+			"moved block declared",
+			[]state.ResourceRename{
+				{From: resourceAddr("aws_instance", "web"), To: resourceAddr("aws_instance", "server")},
+			},
+			`resource "aws_instance" "server" {
+}
+`,
+			`resource "aws_instance" "server" {
+}
+
+moved {
+  from = aws_instance.web
+  to   = aws_instance.server
+}
+`,
+			[]state.ResourceRename{},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%d-%s", i, tc.name), func(t *testing.T) {
+			baseTargets, baseFiles := parseResources(t, tc.before)
+			targets, files := parseResources(t, tc.after)
+
+			renames := detectResourceRenames(tc.renames, baseTargets, baseFiles, targets, files)
+			if diff := cmp.Diff(tc.expectedRenames, renames); diff != "" {
+				t.Fatalf("unexpected renames: %s", diff)
+			}
+		})
+	}
+}
+
+func resourceAddr(resourceType, name string) lang.Address {
+	return lang.Address{
+		lang.RootStep{Name: resourceType},
+		lang.AttrStep{Name: name},
+	}
+}
+
+// parseResources parses the given source as main.tf and returns
+// targets for resources declared in it, as the decoder would
+func parseResources(t *testing.T, src string) (reference.Targets, ast.ModFiles) {
+	f, diags := hclsyntax.ParseConfig([]byte(src), "main.tf", hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+
+	targets := make(reference.Targets, 0)
+	for _, block := range f.Body.(*hclsyntax.Body).Blocks {
+		if block.Type != "resource" {
+			continue
+		}
+		targets = append(targets, reference.Target{
+			Addr:        resourceAddr(block.Labels[0], block.Labels[1]),
+			ScopeId:     resourceScopeId,
+			RangePtr:    block.Range().Ptr(),
+			DefRangePtr: block.DefRange().Ptr(),
+		})
+	}
+
+	return targets, ast.ModFiles{"main.tf": f}
+}
@@ -14,7 +14,9 @@ import (
 	"io/fs"
 	"log"
 	"maps"
+	"path"
 	"slices"
+	"strings"
 	"time"
 
 	"github.com/hashicorp/go-multierror"
@@ -24,6 +26,7 @@ import (
 	tfregistry "github.com/opentofu/opentofu-schema/registry"
 	tfschema "github.com/opentofu/opentofu-schema/schema"
 	tfaddr "github.com/opentofu/registry-address"
+	"github.com/opentofu/tofu-ls/internal/cache"
 	"github.com/opentofu/tofu-ls/internal/document"
 	"github.com/opentofu/tofu-ls/internal/features/modules/state"
 	"github.com/opentofu/tofu-ls/internal/job"
@@ -200,6 +203,172 @@ func preloadSchemaForProviderAddr(ctx context.Context, pAddr tfaddr.Provider, fs
 	return nil
 }
 
+// FetchProviderSchemas downloads schemas of providers, which are required
+// by the module, but neither installed nor available in the bundled schemas,
+// from the given mirror. It is meant to run after [PreloadEmbeddedSchema].
+//
+// Downloaded schemas are cached in schemaCache, if it isn't nil, so that
+// they're available without network access for any later sessions.
+func FetchProviderSchemas(ctx context.Context, logger *log.Logger, regClient registry.Client, mirrorURL string, schemaCache *cache.Cache, modStore *state.ModuleStore, schemaStore *globalState.ProviderSchemaStore, modPath string) error {
+	mod, err := modStore.ModuleRecordByPath(modPath)
+	if err != nil {
+		return err
+	}
+
+	if mod.FetchProviderSchemasState != op.OpStateUnknown && !job.IgnoreState(ctx) {
+		return job.StateNotChangedErr{Dir: document.DirHandleFromPath(modPath)}
+	}
+
+	err = modStore.SetFetchProviderSchemasState(modPath, op.OpStateLoading)
+	if err != nil {
+		return err
+	}
+	defer modStore.SetFetchProviderSchemasState(modPath, op.OpStateLoaded)
+
+	pReqs, err := modStore.ProviderRequirementsForModule(modPath)
+	if err != nil {
+		return err
+	}
+
+	missingReqs, err := schemaStore.MissingSchemas(pReqs)
+	if err != nil {
+		return err
+	}
+
+	var errs *multierror.Error
+	for _, pAddr := range missingReqs {
+		if pAddr.IsBuiltIn() {
+			continue
+		}
+
+		err := fetchSchemaForProviderAddr(ctx, regClient, mirrorURL, schemaCache, pAddr, pReqs[pAddr], schemaStore, logger)
+		if err != nil {
+			errs = multierror.Append(errs, fmt.Errorf("%s: %w", pAddr.ForDisplay(), err))
+		}
+	}
+
+	return errs.ErrorOrNil()
+}
+
+func fetchSchemaForProviderAddr(ctx context.Context, regClient registry.Client, mirrorURL string, schemaCache *cache.Cache,
+	pAddr tfaddr.Provider, cons version.Constraints, schemaStore *globalState.ProviderSchemaStore, logger *log.Logger) error {
+
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "fetchProviderSchema",
+		trace.WithAttributes(attribute.KeyValue{
+			Key:   attribute.Key("ProviderAddress"),
+			Value: attribute.StringValue(pAddr.String()),
+		}))
+	defer span.End()
+
+	pv, b, ok := cachedProviderSchema(schemaCache, pAddr, cons)
+	if ok {
+		logger.Printf("using cached schema for %s %s", pAddr, pv)
+	} else {
+		versions, err := regClient.GetMirroredProviderVersions(ctx, mirrorURL, pAddr)
+		if err != nil {
+			return err
+		}
+		for _, v := range versions {
+			if cons.Check(v) {
+				pv = v
+				break
+			}
+		}
+		if pv == nil {
+			return fmt.Errorf("no schema matching %q found in %s", cons, mirrorURL)
+		}
+
+		b, err = regClient.GetMirroredProviderSchema(ctx, mirrorURL, pAddr, pv)
+		if err != nil {
+			return err
+		}
+
+		if schemaCache != nil {
+			err = schemaCache.Write(globalState.ProviderSchemaCacheKey(pAddr, pv), b)
+			if err != nil {
+				logger.Printf("failed to cache schema for %s %s: %s", pAddr, pv, err)
+			}
+		}
+	}
+
+	jsonSchemas := tfjson.ProviderSchemas{}
+	err := json.Unmarshal(b, &jsonSchemas)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "schema document not decodable")
+		return err
+	}
+
+	ps, ok := jsonSchemas.Schemas[pAddr.String()]
+	if !ok && len(jsonSchemas.Schemas) == 1 {
+		// The schema may have been obtained for the same provider
+		// installed from a different registry host
+		for _, s := range jsonSchemas.Schemas {
+			ps = s
+		}
+	} else if !ok {
+		return fmt.Errorf("%q: no schema found in document", pAddr)
+	}
+
+	pSchema := tfschema.ProviderSchemaFromJson(ps, pAddr)
+	pSchema.SetProviderVersion(pAddr, pv)
+
+	err = schemaStore.AddMirroredSchema(pAddr, pv, mirrorURL, pSchema)
+	if err != nil {
+		existsError := &globalState.AlreadyExistsError{}
+		if errors.As(err, &existsError) {
+			return nil
+		}
+		return err
+	}
+	logger.Printf("fetched schema for %s %s from %s", pAddr, pv, mirrorURL)
+	span.SetStatus(codes.Ok, "schema loaded successfully")
+
+	return nil
+}
+
+// cachedProviderSchema returns the newest schema of the provider
+// in schemaCache, whose version matches the constraints
+func cachedProviderSchema(schemaCache *cache.Cache, pAddr tfaddr.Provider, cons version.Constraints) (*version.Version, []byte, bool) {
+	if schemaCache == nil {
+		return nil, nil, false
+	}
+	keys, err := schemaCache.Keys(globalState.ProviderSchemaCacheDir(pAddr))
+	if err != nil {
+		return nil, nil, false
+	}
+
+	var newest *version.Version
+	newestKey := ""
+	for _, key := range keys {
+		rawVersion, ok := strings.CutSuffix(path.Base(key), ".json")
+		if !ok {
+			continue
+		}
+		v, err := version.NewVersion(rawVersion)
+		if err != nil || !cons.Check(v) {
+			continue
+		}
+		if newest == nil || v.GreaterThan(newest) {
+			newest = v
+			newestKey = key
+		}
+	}
+	if newest == nil {
+		return nil, nil, false
+	}
+
+	b, err := schemaCache.Read(newestKey)
+	if err != nil {
+		return nil, nil, false
+	}
+	// Errors are ignored, as this only affects
+	// the order in which entries are evicted
+	schemaCache.Touch(newestKey)
+
+	return newest, b, true
+}
+
 // GetModuleDataFromRegistry obtains data about any modules (inputs & outputs)
 // from the Registry API based on module calls which were previously parsed
 // via [LoadModuleMetadata]. The same data could be obtained via [ParseModuleManifest]
@@ -27,6 +27,7 @@ import (
 	"github.com/hashicorp/hcl-lang/lang"
 	tfregistry "github.com/opentofu/opentofu-schema/registry"
 	tfaddr "github.com/opentofu/registry-address"
+	"github.com/opentofu/tofu-ls/internal/cache"
 	lsctx "github.com/opentofu/tofu-ls/internal/context"
 	"github.com/opentofu/tofu-ls/internal/document"
 	"github.com/opentofu/tofu-ls/internal/features/modules/state"
@@ -946,3 +947,167 @@ var randomSchemaJSON = `{
 		}
 	}
 }`
+
+func TestFetchProviderSchemas(t *testing.T) {
+	modPath := "testmod"
+	cfgFS := fstest.MapFS{
+		// These are somewhat awkward double entries
+		// to account for io/fs and our own path separator differences
+		// See https://github.com/hashicorp/terraform-ls/issues/1025
+		modPath + "/main.tf": &fstest.MapFile{
+			Data: []byte{},
+		},
+		filepath.Join(modPath, "main.tf"): &fstest.MapFile{
+			Data: []byte(`terraform {
+	required_providers {
+		random = {
+			source = "hashicorp/random"
+			version = "~> 1.0"
+		}
+	}
+}
+`),
+		},
+	}
+
+	var requests []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.RequestURI)
+		switch r.RequestURI {
+		case "/schemas/registry.opentofu.org/hashicorp/random/index.json":
+			w.Write([]byte(`{"versions": {"1.0.0": {}, "1.1.0": {}, "2.0.0": {}}}`))
+			return
+		case "/schemas/registry.opentofu.org/hashicorp/random/1.1.0/schema.json":
+			w.Write([]byte(randomSchemaJSON))
+			return
+		}
+		http.Error(w, fmt.Sprintf("unexpected request: %q", r.RequestURI), 400)
+	}))
+	t.Cleanup(srv.Close)
+	mirrorURL := srv.URL + "/schemas"
+	diskCache := cache.New(t.TempDir(), 0)
+
+	fetchSchemas := func(t *testing.T) *globalState.ProviderSchemaStore {
+		ctx := context.Background()
+		gs, err := globalState.NewStateStore()
+		if err != nil {
+			t.Fatal(err)
+		}
+		ms, err := state.NewModuleStore(gs.ProviderSchemas, gs.RegistryModules, gs.ChangeStore)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = ms.Add(modPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ctx = lsctx.WithDocumentContext(ctx, lsctx.Document{})
+		err = ParseModuleConfiguration(ctx, cfgFS, ms, modPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = LoadModuleMetadata(ctx, ms, modPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = FetchProviderSchemas(ctx, log.Default(), registry.NewClient(), mirrorURL, diskCache,
+			ms, gs.ProviderSchemas, modPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return gs.ProviderSchemas
+	}
+
+	schemaStore := fetchSchemas(t)
+
+	pAddr := tfaddr.MustParseProviderSource("hashicorp/random")
+	vc := version.MustConstraints(version.NewConstraint("~> 1.0"))
+	s, err := schemaStore.ProviderSchema("unknown-path", pAddr, vc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := s.Provider.Attributes["test"]; !ok {
+		t.Fatalf("expected test attribute in provider schema, not found")
+	}
+
+	expectedRequests := []string{
+		"/schemas/registry.opentofu.org/hashicorp/random/index.json",
+		"/schemas/registry.opentofu.org/hashicorp/random/1.1.0/schema.json",
+	}
+	if diff := cmp.Diff(expectedRequests, requests); diff != "" {
+		t.Fatalf("unexpected requests: %s", diff)
+	}
+
+	// A new session loads the schema from the cache
+	requests = []string{}
+	schemaStore = fetchSchemas(t)
+	if len(requests) != 0 {
+		t.Fatalf("expected no requests, given: %q", requests)
+	}
+	_, err = schemaStore.ProviderSchema("unknown-path", pAddr, vc)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFetchProviderSchemas_noMatchingVersion(t *testing.T) {
+	ctx := context.Background()
+	gs, err := globalState.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ms, err := state.NewModuleStore(gs.ProviderSchemas, gs.RegistryModules, gs.ChangeStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+	modPath := "testmod"
+	cfgFS := fstest.MapFS{
+		modPath + "/main.tf": &fstest.MapFile{
+			Data: []byte{},
+		},
+		filepath.Join(modPath, "main.tf"): &fstest.MapFile{
+			Data: []byte(`terraform {
+	required_providers {
+		random = {
+			source = "hashicorp/random"
+			version = ">= 3.0"
+		}
+	}
+}
+`),
+		},
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.RequestURI == "/registry.opentofu.org/hashicorp/random/index.json" {
+			w.Write([]byte(`{"versions": {"1.0.0": {}}}`))
+			return
+		}
+		http.Error(w, fmt.Sprintf("unexpected request: %q", r.RequestURI), 400)
+	}))
+	t.Cleanup(srv.Close)
+
+	err = ms.Add(modPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx = lsctx.WithDocumentContext(ctx, lsctx.Document{})
+	err = ParseModuleConfiguration(ctx, cfgFS, ms, modPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = LoadModuleMetadata(ctx, ms, modPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = FetchProviderSchemas(ctx, log.Default(), registry.NewClient(), srv.URL, nil,
+		ms, gs.ProviderSchemas, modPath)
+	if err == nil {
+		t.Fatal("expected error for missing version")
+	}
+	if !strings.Contains(err.Error(), "no schema matching") {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
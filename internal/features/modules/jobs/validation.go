@@ -23,8 +23,10 @@ import (
 	"github.com/opentofu/tofu-ls/internal/langserver/diagnostics"
 	ilsp "github.com/opentofu/tofu-ls/internal/lsp"
 	globalAst "github.com/opentofu/tofu-ls/internal/tofu/ast"
+	"github.com/opentofu/tofu-ls/internal/tofu/datadir"
 	"github.com/opentofu/tofu-ls/internal/tofu/module"
 	op "github.com/opentofu/tofu-ls/internal/tofu/module/operation"
+	"github.com/opentofu/tofu-ls/internal/uri"
 )
 
 // SchemaModuleValidation does schema-based validation
@@ -97,7 +99,8 @@ func SchemaModuleValidation(ctx context.Context, modStore *state.ModuleStore, ro
 }
 
 // ReferenceValidation does validation based on (mis)matched
-// reference origins and targets, to flag up "orphaned" references.
+// reference origins and targets, to flag up "orphaned" references
+// and variables or local values which are never referenced.
 //
 // It relies on [DecodeReferenceTargets] and [DecodeReferenceOrigins]
 // to supply both origins and targets to compare.
@@ -130,9 +133,42 @@ func ReferenceValidation(ctx context.Context, modStore *state.ModuleStore, rootF
 	}
 
 	diags := validations.UnreferencedOrigins(ctx, pathCtx)
+	diags = diags.Extend(validations.UnreferencedTargets(ctx, pathCtx))
 	return modStore.UpdateModuleDiagnostics(modPath, globalAst.ReferenceValidationSource, ast.ModDiagsFromMap(diags))
 }
 
+// ProviderLockValidation compares the version constraints in required_providers
+// with the provider versions in the dependency lock file of the module.
+//
+// The lock file is parsed here rather than read from the root module, which
+// is parsed by another feature, so that the result doesn't depend on the
+// order of jobs. Modules without a lock file aren't validated.
+func ProviderLockValidation(ctx context.Context, fs ReadOnlyFS, modStore *state.ModuleStore, modPath string) error {
+	mod, err := modStore.ModuleRecordByPath(modPath)
+	if err != nil {
+		return err
+	}
+
+	// Avoid validation if it is already in progress or already finished
+	if mod.ModuleDiagnosticsState[globalAst.ProviderLockValidationSource] != op.OpStateUnknown && !job.IgnoreState(ctx) {
+		return job.StateNotChangedErr{Dir: document.DirHandleFromPath(modPath)}
+	}
+
+	err = modStore.SetModuleDiagnosticsState(modPath, globalAst.ProviderLockValidationSource, op.OpStateLoading)
+	if err != nil {
+		return err
+	}
+
+	diags := make(lang.DiagnosticsMap)
+	locked, err := datadir.ParsePluginVersions(fs, modPath)
+	if err == nil {
+		diags = validations.ProviderLockDrift(mod.ParsedModuleFiles.AsMap(), mod.Meta.ProviderReferences,
+			mod.Meta.ProviderRequirements, locked, uri.FromPath(modPath))
+	}
+
+	return modStore.UpdateModuleDiagnostics(modPath, globalAst.ProviderLockValidationSource, ast.ModDiagsFromMap(diags))
+}
+
 // TofuValidate uses Tofu CLI to run validate subcommand
 // and turn the provided (JSON) output into diagnostics associated
 // with "invalid" parts of code.
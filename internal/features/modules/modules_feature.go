@@ -10,22 +10,34 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"path/filepath"
+	"time"
 
+	"github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/go-version"
 	"github.com/hashicorp/hcl-lang/decoder"
 	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/reference"
 	tfmod "github.com/opentofu/opentofu-schema/module"
+	tfaddr "github.com/opentofu/registry-address"
+	"github.com/opentofu/tofu-ls/internal/cache"
 	"github.com/opentofu/tofu-ls/internal/document"
 	"github.com/opentofu/tofu-ls/internal/eventbus"
 	fdecoder "github.com/opentofu/tofu-ls/internal/features/modules/decoder"
 	"github.com/opentofu/tofu-ls/internal/features/modules/hooks"
 	"github.com/opentofu/tofu-ls/internal/features/modules/jobs"
 	"github.com/opentofu/tofu-ls/internal/features/modules/state"
+	"github.com/opentofu/tofu-ls/internal/job"
 	"github.com/opentofu/tofu-ls/internal/langserver/diagnostics"
 	"github.com/opentofu/tofu-ls/internal/registry"
 	globalState "github.com/opentofu/tofu-ls/internal/state"
+	"github.com/opentofu/tofu-ls/internal/tofu/datadir"
 )
 
+// moduleSearchDelay is how long completion of registry module sources
+// waits for further typing before searching the registry
+const moduleSearchDelay = 300 * time.Millisecond
+
 // ModulesFeature groups everything related to modules. Its internal
 // state keeps track of all modules in the workspace.
 type ModulesFeature struct {
@@ -38,6 +50,11 @@ type ModulesFeature struct {
 	stateStore     *globalState.StateStore
 	registryClient registry.Client
 	fs             jobs.ReadOnlyFS
+
+	moduleSearchDebouncer *hooks.Debouncer
+
+	schemaMirrorURL string
+	schemaCache     *cache.Cache
 }
 
 func NewModulesFeature(eventbus *eventbus.EventBus, stateStore *globalState.StateStore, fs jobs.ReadOnlyFS, rootFeature fdecoder.RootReader, registryClient registry.Client) (*ModulesFeature, error) {
@@ -56,6 +73,8 @@ func NewModulesFeature(eventbus *eventbus.EventBus, stateStore *globalState.Stat
 		rootFeature:    rootFeature,
 		fs:             fs,
 		registryClient: registryClient,
+
+		moduleSearchDebouncer: hooks.NewDebouncer(moduleSearchDelay),
 	}, nil
 }
 
@@ -64,6 +83,14 @@ func (f *ModulesFeature) SetLogger(logger *log.Logger) {
 	f.Store.SetLogger(logger)
 }
 
+// SetProviderSchemaMirror enables fetching schemas of providers,
+// which are neither installed nor bundled, from the given mirror.
+// Fetched schemas are kept in schemaCache, if it isn't nil.
+func (f *ModulesFeature) SetProviderSchemaMirror(mirrorURL string, schemaCache *cache.Cache) {
+	f.schemaMirrorURL = mirrorURL
+	f.schemaCache = schemaCache
+}
+
 // Start starts the features separate goroutine.
 // It listens to various events from the EventBus and performs corresponding actions.
 func (f *ModulesFeature) Start(ctx context.Context) {
@@ -82,6 +109,9 @@ func (f *ModulesFeature) Start(ctx context.Context) {
 	didChangeWatchedDone := make(chan struct{}, 10)
 	didChangeWatched := f.eventbus.OnDidChangeWatched("feature.modules", didChangeWatchedDone)
 
+	pluginLockChangeDone := make(chan struct{}, 10)
+	pluginLockChange := f.eventbus.OnPluginLockChange("feature.modules", pluginLockChangeDone)
+
 	go func() {
 		for {
 			select {
@@ -101,6 +131,10 @@ func (f *ModulesFeature) Start(ctx context.Context) {
 				// TODO? collect errors
 				f.didChangeWatched(didChangeWatched.Context, didChangeWatched.RawPath, didChangeWatched.ChangeType, didChangeWatched.IsDir)
 				didChangeWatchedDone <- struct{}{}
+			case pluginLockChange := <-pluginLockChange:
+				// TODO? collect errors
+				f.pluginLockChange(pluginLockChange.Context, pluginLockChange.Dir)
+				pluginLockChangeDone <- struct{}{}
 
 			case <-ctx.Done():
 				return
@@ -136,6 +170,81 @@ func (f *ModulesFeature) DeclaredModuleCalls(modPath string) (map[string]tfmod.D
 	return f.Store.DeclaredModuleCalls(modPath)
 }
 
+// ModuleCallPath returns the path to the directory of the module called
+// by the given module call declared in modPath. Local modules are resolved
+// relative to modPath. Registry and other remote modules are resolved
+// through the modules installed in the root module, which is either modPath
+// itself or the module whose data directory modPath is installed in.
+func (f *ModulesFeature) ModuleCallPath(modPath string, mc tfmod.DeclaredModuleCall) (string, bool) {
+	switch source := mc.SourceAddr.(type) {
+	case tfmod.LocalSourceAddr:
+		return filepath.Join(modPath, filepath.FromSlash(source.String())), true
+	case tfaddr.Module, tfmod.RemoteSourceAddr:
+		rootPath := modPath
+		if installedRoot, ok := datadir.InstalledModuleRoot(modPath); ok {
+			rootPath = installedRoot
+		}
+
+		installedDir, ok := f.rootFeature.InstalledModulePath(rootPath, source.String())
+		if ok {
+			return filepath.Join(rootPath, filepath.FromSlash(installedDir)), true
+		}
+
+		// The root module may not be indexed yet, or the module may be called
+		// from a nested local module, in which case it can only be found
+		// by its position in the module tree recorded in the manifest.
+		return f.installedModuleCallPath(modPath, mc.LocalName)
+	}
+
+	// Unknown source address, we can't resolve the path
+	return "", false
+}
+
+// installedModuleCallPath looks up the named module call of the module
+// at modPath in the module manifest of the closest root module above it.
+func (f *ModulesFeature) installedModuleCallPath(modPath, name string) (string, bool) {
+	for dir := modPath; ; dir = filepath.Dir(dir) {
+		manifestPath, ok := datadir.ModuleManifestFilePath(f.fs, dir)
+		if ok {
+			mm, err := datadir.ParseModuleManifestFromFile(manifestPath)
+			if err != nil {
+				f.logger.Printf("failed to parse module manifest %q: %s", manifestPath, err)
+				return "", false
+			}
+			return mm.ModuleCallDir(modPath, name)
+		}
+
+		if filepath.Dir(dir) == dir {
+			return "", false
+		}
+	}
+}
+
+// DecodeModules schedules decoding of the modules at the given paths,
+// which need not be open, in the same way as modules called from an
+// open module. This makes their reference origins available, e.g. for
+// finding references to outputs of a module they call.
+func (f *ModulesFeature) DecodeModules(ctx context.Context, paths []string) (job.IDs, error) {
+	ids := make(job.IDs, 0)
+	var errs *multierror.Error
+
+	for _, path := range paths {
+		err := f.Store.AddIfNotExists(path)
+		if err != nil {
+			errs = multierror.Append(errs, err)
+			continue
+		}
+
+		modIds, err := f.decodeModule(ctx, document.DirHandleFromPath(path), false, false)
+		ids = append(ids, modIds...)
+		if err != nil {
+			errs = multierror.Append(errs, err)
+		}
+	}
+
+	return ids, errs.ErrorOrNil()
+}
+
 func (f *ModulesFeature) ProviderRequirements(modPath string) (tfmod.ProviderRequirements, error) {
 	mod, err := f.Store.ModuleRecordByPath(modPath)
 	if err != nil {
@@ -154,6 +263,10 @@ func (f *ModulesFeature) CoreRequirements(modPath string) (version.Constraints,
 	return mod.Meta.CoreRequirements, nil
 }
 
+func (f *ModulesFeature) LocalModuleMeta(modPath string) (*tfmod.Meta, error) {
+	return f.Store.LocalModuleMeta(modPath)
+}
+
 func (f *ModulesFeature) ModuleInputs(modPath string) (map[string]tfmod.Variable, error) {
 	mod, err := f.Store.ModuleRecordByPath(modPath)
 	if err != nil {
@@ -163,11 +276,35 @@ func (f *ModulesFeature) ModuleInputs(modPath string) (map[string]tfmod.Variable
 	return mod.Meta.Variables, nil
 }
 
+// ModuleReferenceTargets returns the reference targets
+// collected from the module at the given path.
+func (f *ModulesFeature) ModuleReferenceTargets(modPath string) (reference.Targets, error) {
+	mod, err := f.Store.ModuleRecordByPath(modPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return mod.RefTargets, nil
+}
+
+// ResourceRenames returns resources renamed while editing the module
+// at the given path, which are not yet recorded by a moved block.
+func (f *ModulesFeature) ResourceRenames(modPath string) ([]state.ResourceRename, error) {
+	mod, err := f.Store.ModuleRecordByPath(modPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return mod.ResourceRenames, nil
+}
+
 func (f *ModulesFeature) AppendCompletionHooks(srvCtx context.Context, decoderContext decoder.DecoderContext) {
 	h := hooks.Hooks{
-		ModStore:       f.Store,
-		RegistryClient: f.registryClient,
-		Logger:         f.logger,
+		ModStore:        f.Store,
+		RegistryModules: f.stateStore.RegistryModules,
+		RegistryClient:  f.registryClient,
+		SearchDebouncer: f.moduleSearchDebouncer,
+		Logger:          f.logger,
 	}
 
 	decoderContext.CompletionHooks["CompleteLocalModuleSources"] = h.LocalModuleSources
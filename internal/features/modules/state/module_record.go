@@ -6,6 +6,9 @@
 package state
 
 import (
+	"slices"
+
+	"github.com/hashicorp/hcl-lang/lang"
 	"github.com/hashicorp/hcl-lang/reference"
 	"github.com/hashicorp/hcl/v2"
 
@@ -22,6 +25,9 @@ type ModuleRecord struct {
 	// PreloadEmbeddedSchemaState tracks if we tried loading all provider
 	// schemas from our embedded schema data
 	PreloadEmbeddedSchemaState op.OpState
+	// FetchProviderSchemasState tracks if we tried fetching provider
+	// schemas, which weren't embedded, from the configured mirror
+	FetchProviderSchemasState op.OpState
 
 	RefTargets      reference.Targets
 	RefTargetsErr   error
@@ -40,6 +46,21 @@ type ModuleRecord struct {
 
 	ModuleDiagnostics      ast.SourceModDiags
 	ModuleDiagnosticsState globalAst.DiagnosticSourceState
+
+	// ResourceRenames tracks resources which were renamed
+	// while the module was being edited and have no moved block yet
+	ResourceRenames []ResourceRename
+	// RenamesBaseTargets and RenamesBaseFiles represent the last
+	// valid state of the module which further renames are detected against
+	RenamesBaseTargets reference.Targets
+	RenamesBaseFiles   ast.ModFiles
+}
+
+// ResourceRename represents a change of resource labels,
+// e.g. from aws_instance.web to aws_instance.server
+type ResourceRename struct {
+	From lang.Address
+	To   lang.Address
 }
 
 func (m *ModuleRecord) Copy() *ModuleRecord {
@@ -50,6 +71,7 @@ func (m *ModuleRecord) Copy() *ModuleRecord {
 		path: m.path,
 
 		PreloadEmbeddedSchemaState: m.PreloadEmbeddedSchemaState,
+		FetchProviderSchemasState:  m.FetchProviderSchemasState,
 
 		RefTargets:      m.RefTargets.Copy(),
 		RefTargetsErr:   m.RefTargetsErr,
@@ -66,6 +88,13 @@ func (m *ModuleRecord) Copy() *ModuleRecord {
 		MetaState: m.MetaState,
 
 		ModuleDiagnosticsState: m.ModuleDiagnosticsState.Copy(),
+
+		ResourceRenames:    slices.Clone(m.ResourceRenames),
+		RenamesBaseTargets: m.RenamesBaseTargets.Copy(),
+	}
+
+	if m.RenamesBaseFiles != nil {
+		newMod.RenamesBaseFiles = m.RenamesBaseFiles.Copy()
 	}
 
 	if m.ParsedModuleFiles != nil {
@@ -100,14 +129,17 @@ func newModule(modPath string) *ModuleRecord {
 	return &ModuleRecord{
 		path:                       modPath,
 		PreloadEmbeddedSchemaState: op.OpStateUnknown,
+		FetchProviderSchemasState:  op.OpStateUnknown,
 		RefOriginsState:            op.OpStateUnknown,
 		RefTargetsState:            op.OpStateUnknown,
 		MetaState:                  op.OpStateUnknown,
 		ModuleDiagnosticsState: globalAst.DiagnosticSourceState{
-			globalAst.HCLParsingSource:          op.OpStateUnknown,
-			globalAst.SchemaValidationSource:    op.OpStateUnknown,
-			globalAst.ReferenceValidationSource: op.OpStateUnknown,
-			globalAst.TofuValidateSource:        op.OpStateUnknown,
+			globalAst.HCLParsingSource:             op.OpStateUnknown,
+			globalAst.SchemaValidationSource:       op.OpStateUnknown,
+			globalAst.ReferenceValidationSource:    op.OpStateUnknown,
+			globalAst.TofuValidateSource:           op.OpStateUnknown,
+			globalAst.TofuPlanSource:               op.OpStateUnknown,
+			globalAst.ProviderLockValidationSource: op.OpStateUnknown,
 		},
 	}
 }
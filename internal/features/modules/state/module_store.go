@@ -350,6 +350,25 @@ func (s *ModuleStore) SetPreloadEmbeddedSchemaState(path string, state op.OpStat
 	return nil
 }
 
+func (s *ModuleStore) SetFetchProviderSchemasState(path string, state op.OpState) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	mod, err := moduleCopyByPath(txn, path)
+	if err != nil {
+		return err
+	}
+
+	mod.FetchProviderSchemasState = state
+	err = txn.Insert(s.tableName, mod)
+	if err != nil {
+		return err
+	}
+
+	txn.Commit()
+	return nil
+}
+
 func (s *ModuleStore) UpdateParsedModuleFiles(path string, pFiles ast.ModFiles, pErr error) error {
 	txn := s.db.Txn(true)
 	defer txn.Abort()
@@ -525,6 +544,30 @@ func (s *ModuleStore) UpdateReferenceTargets(path string, refs reference.Targets
 	return nil
 }
 
+// UpdateResourceRenames replaces the tracked resource renames, along with
+// the targets and files which the next renames are to be detected against.
+func (s *ModuleStore) UpdateResourceRenames(path string, renames []ResourceRename, baseTargets reference.Targets, baseFiles ast.ModFiles) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	mod, err := moduleCopyByPath(txn, path)
+	if err != nil {
+		return err
+	}
+
+	mod.ResourceRenames = renames
+	mod.RenamesBaseTargets = baseTargets
+	mod.RenamesBaseFiles = baseFiles
+
+	err = txn.Insert(s.tableName, mod)
+	if err != nil {
+		return err
+	}
+
+	txn.Commit()
+	return nil
+}
+
 func (s *ModuleStore) SetReferenceOriginsState(path string, state op.OpState) error {
 	txn := s.db.Txn(true)
 	defer txn.Abort()
@@ -92,10 +92,12 @@ func TestModuleStore_ModuleByPath(t *testing.T) {
 	expectedModule := &ModuleRecord{
 		path: modPath,
 		ModuleDiagnosticsState: globalAst.DiagnosticSourceState{
-			globalAst.HCLParsingSource:          operation.OpStateUnknown,
-			globalAst.SchemaValidationSource:    operation.OpStateUnknown,
-			globalAst.ReferenceValidationSource: operation.OpStateUnknown,
-			globalAst.TofuValidateSource:        operation.OpStateUnknown,
+			globalAst.HCLParsingSource:             operation.OpStateUnknown,
+			globalAst.SchemaValidationSource:       operation.OpStateUnknown,
+			globalAst.ReferenceValidationSource:    operation.OpStateUnknown,
+			globalAst.TofuValidateSource:           operation.OpStateUnknown,
+			globalAst.TofuPlanSource:               operation.OpStateUnknown,
+			globalAst.ProviderLockValidationSource: operation.OpStateUnknown,
 		},
 	}
 	if diff := cmp.Diff(expectedModule, mod, cmpOpts); diff != "" {
@@ -136,28 +138,34 @@ func TestModuleStore_List(t *testing.T) {
 		{
 			path: filepath.Join(tmpDir, "alpha"),
 			ModuleDiagnosticsState: globalAst.DiagnosticSourceState{
-				globalAst.HCLParsingSource:          operation.OpStateUnknown,
-				globalAst.SchemaValidationSource:    operation.OpStateUnknown,
-				globalAst.ReferenceValidationSource: operation.OpStateUnknown,
-				globalAst.TofuValidateSource:        operation.OpStateUnknown,
+				globalAst.HCLParsingSource:             operation.OpStateUnknown,
+				globalAst.SchemaValidationSource:       operation.OpStateUnknown,
+				globalAst.ReferenceValidationSource:    operation.OpStateUnknown,
+				globalAst.TofuValidateSource:           operation.OpStateUnknown,
+				globalAst.TofuPlanSource:               operation.OpStateUnknown,
+				globalAst.ProviderLockValidationSource: operation.OpStateUnknown,
 			},
 		},
 		{
 			path: filepath.Join(tmpDir, "beta"),
 			ModuleDiagnosticsState: globalAst.DiagnosticSourceState{
-				globalAst.HCLParsingSource:          operation.OpStateUnknown,
-				globalAst.SchemaValidationSource:    operation.OpStateUnknown,
-				globalAst.ReferenceValidationSource: operation.OpStateUnknown,
-				globalAst.TofuValidateSource:        operation.OpStateUnknown,
+				globalAst.HCLParsingSource:             operation.OpStateUnknown,
+				globalAst.SchemaValidationSource:       operation.OpStateUnknown,
+				globalAst.ReferenceValidationSource:    operation.OpStateUnknown,
+				globalAst.TofuValidateSource:           operation.OpStateUnknown,
+				globalAst.TofuPlanSource:               operation.OpStateUnknown,
+				globalAst.ProviderLockValidationSource: operation.OpStateUnknown,
 			},
 		},
 		{
 			path: filepath.Join(tmpDir, "gamma"),
 			ModuleDiagnosticsState: globalAst.DiagnosticSourceState{
-				globalAst.HCLParsingSource:          operation.OpStateUnknown,
-				globalAst.SchemaValidationSource:    operation.OpStateUnknown,
-				globalAst.ReferenceValidationSource: operation.OpStateUnknown,
-				globalAst.TofuValidateSource:        operation.OpStateUnknown,
+				globalAst.HCLParsingSource:             operation.OpStateUnknown,
+				globalAst.SchemaValidationSource:       operation.OpStateUnknown,
+				globalAst.ReferenceValidationSource:    operation.OpStateUnknown,
+				globalAst.TofuValidateSource:           operation.OpStateUnknown,
+				globalAst.TofuPlanSource:               operation.OpStateUnknown,
+				globalAst.ProviderLockValidationSource: operation.OpStateUnknown,
 			},
 		},
 	}
@@ -222,10 +230,12 @@ func TestModuleStore_UpdateMetadata(t *testing.T) {
 		},
 		MetaState: operation.OpStateLoaded,
 		ModuleDiagnosticsState: globalAst.DiagnosticSourceState{
-			globalAst.HCLParsingSource:          operation.OpStateUnknown,
-			globalAst.SchemaValidationSource:    operation.OpStateUnknown,
-			globalAst.ReferenceValidationSource: operation.OpStateUnknown,
-			globalAst.TofuValidateSource:        operation.OpStateUnknown,
+			globalAst.HCLParsingSource:             operation.OpStateUnknown,
+			globalAst.SchemaValidationSource:       operation.OpStateUnknown,
+			globalAst.ReferenceValidationSource:    operation.OpStateUnknown,
+			globalAst.TofuValidateSource:           operation.OpStateUnknown,
+			globalAst.TofuPlanSource:               operation.OpStateUnknown,
+			globalAst.ProviderLockValidationSource: operation.OpStateUnknown,
 		},
 	}
 
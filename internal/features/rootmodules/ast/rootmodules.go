@@ -5,9 +5,45 @@
 
 package ast
 
+import (
+	"path/filepath"
+)
+
+// StateFilename is the name of the file in which
+// the local backend stores the state of a root module.
+const StateFilename = "terraform.tfstate"
+
 // IsRootModuleFilename checks if the given filename is a root module file.
 // TODO: extend this for OpenTofu specific files
 func IsRootModuleFilename(name string) bool {
 	return (name == ".terraform.lock.hcl" ||
 		name == ".terraform-version")
 }
+
+// PlanStateGlobPatterns returns glob patterns matching the local state
+// and the saved plan at planFile of any root module, which is shown on hover.
+func PlanStateGlobPatterns(planFile string) []string {
+	patterns := []string{"**/" + StateFilename}
+	if planFile == "" {
+		return patterns
+	}
+	if filepath.IsAbs(planFile) {
+		return append(patterns, filepath.ToSlash(planFile))
+	}
+	return append(patterns, "**/"+filepath.ToSlash(planFile))
+}
+
+// IsPlanStateFile checks if path is the local state
+// or the saved plan at planFile of the root module at modPath.
+func IsPlanStateFile(modPath, planFile, path string) bool {
+	if path == filepath.Join(modPath, StateFilename) {
+		return true
+	}
+	if planFile == "" {
+		return false
+	}
+	if !filepath.IsAbs(planFile) {
+		planFile = filepath.Join(modPath, planFile)
+	}
+	return path == planFile
+}
@@ -8,6 +8,7 @@ package rootmodules
 import (
 	"context"
 
+	lsctx "github.com/opentofu/tofu-ls/internal/context"
 	"github.com/opentofu/tofu-ls/internal/document"
 	"github.com/opentofu/tofu-ls/internal/features/rootmodules/ast"
 	"github.com/opentofu/tofu-ls/internal/features/rootmodules/jobs"
@@ -113,9 +114,65 @@ func (f *RootModulesFeature) didOpen(ctx context.Context, dir document.DirHandle
 	}
 	ids = append(ids, pSchemaId)
 
+	expFeatures, err := lsctx.ExperimentalFeatures(ctx)
+	if err == nil && expFeatures.PlanStateHover.Enable {
+		planStateId, err := f.obtainPlanState(ctx, dir, expFeatures.PlanStateHover.PlanFile, false)
+		if err != nil {
+			return ids, err
+		}
+		ids = append(ids, planStateId)
+	}
+
 	return ids, nil
 }
 
+// didChangeWatched obtains the plan or state again for root modules which
+// already obtained it, when their local state or saved plan file changes.
+func (f *RootModulesFeature) didChangeWatched(ctx context.Context, rawPath string, isDir bool) (job.IDs, error) {
+	ids := make(job.IDs, 0)
+	if isDir {
+		return ids, nil
+	}
+
+	expFeatures, err := lsctx.ExperimentalFeatures(ctx)
+	if err != nil || !expFeatures.PlanStateHover.Enable {
+		return ids, nil
+	}
+	planFile := expFeatures.PlanStateHover.PlanFile
+
+	records, err := f.Store.List()
+	if err != nil {
+		return ids, err
+	}
+
+	for _, record := range records {
+		if record.TofuShowState == op.OpStateUnknown ||
+			!ast.IsPlanStateFile(record.Path(), planFile, rawPath) {
+			continue
+		}
+
+		planStateId, err := f.obtainPlanState(ctx, document.DirHandleFromPath(record.Path()), planFile, true)
+		if err != nil {
+			return ids, err
+		}
+		ids = append(ids, planStateId)
+	}
+
+	return ids, nil
+}
+
+func (f *RootModulesFeature) obtainPlanState(ctx context.Context, dir document.DirHandle, planFile string, ignoreState bool) (job.ID, error) {
+	return f.stateStore.JobStore.EnqueueJob(ctx, job.Job{
+		Dir: dir,
+		Func: func(ctx context.Context) error {
+			ctx = exec.WithExecutorFactory(ctx, f.tfExecFactory)
+			return jobs.ObtainPlanState(ctx, f.fs, f.Store, dir.Path(), planFile)
+		},
+		Type:        op.OpTypeObtainPlanState.String(),
+		IgnoreState: ignoreState,
+	})
+}
+
 func (f *RootModulesFeature) pluginLockChange(ctx context.Context, dir document.DirHandle) (job.IDs, error) {
 	ids := make(job.IDs, 0)
 	path := dir.Path()
@@ -0,0 +1,72 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package jobs
+
+import (
+	"context"
+	"path/filepath"
+
+	"github.com/opentofu/tofu-ls/internal/document"
+	"github.com/opentofu/tofu-ls/internal/features/rootmodules/state"
+	"github.com/opentofu/tofu-ls/internal/job"
+	"github.com/opentofu/tofu-ls/internal/tofu/module"
+	op "github.com/opentofu/tofu-ls/internal/tofu/module/operation"
+)
+
+// ObtainPlanState obtains the saved plan at planFile via tofu show,
+// if such a file exists, or the current state of the root module otherwise.
+// A relative planFile is relative to the root module.
+//
+// Reading the state may involve reaching a remote backend,
+// which is why this only runs when enabled in settings.
+func ObtainPlanState(ctx context.Context, fs ReadOnlyFS, rootStore *state.RootStore, modPath, planFile string) error {
+	record, err := rootStore.RootRecordByPath(modPath)
+	if err != nil {
+		return err
+	}
+
+	// Avoid running tofu show if it is already in progress or already done
+	if record.TofuShowState != op.OpStateUnknown && !job.IgnoreState(ctx) {
+		return job.StateNotChangedErr{Dir: document.DirHandleFromPath(modPath)}
+	}
+
+	err = rootStore.SetTofuShowState(modPath, op.OpStateLoading)
+	if err != nil {
+		return err
+	}
+
+	tfExec, err := module.TofuExecutorForModule(ctx, modPath)
+	if err != nil {
+		sErr := rootStore.UpdateTofuShow(modPath, nil, nil, err)
+		if sErr != nil {
+			return sErr
+		}
+		return err
+	}
+
+	if planFile != "" {
+		planPath := planFile
+		if !filepath.IsAbs(planPath) {
+			planPath = filepath.Join(modPath, planPath)
+		}
+
+		_, err = fs.Stat(planPath)
+		if err == nil {
+			plan, err := tfExec.ShowPlanFile(ctx, planPath)
+			sErr := rootStore.UpdateTofuShow(modPath, nil, plan, err)
+			if sErr != nil {
+				return sErr
+			}
+			return err
+		}
+	}
+
+	tfState, err := tfExec.Show(ctx)
+	sErr := rootStore.UpdateTofuShow(modPath, tfState, nil, err)
+	if sErr != nil {
+		return sErr
+	}
+
+	return err
+}
@@ -8,6 +8,7 @@ package jobs
 import (
 	"context"
 
+	"github.com/hashicorp/go-multierror"
 	tfschema "github.com/opentofu/opentofu-schema/schema"
 	tfaddr "github.com/opentofu/registry-address"
 	"github.com/opentofu/tofu-ls/internal/document"
@@ -21,6 +22,9 @@ import (
 // ObtainSchema obtains provider schemas via Terraform CLI.
 // This is useful if we do not have the schemas available
 // from the embedded FS (i.e. in [PreloadEmbeddedSchema]).
+//
+// Obtained schemas are written to the persistent cache, if any,
+// and the CLI isn't run if all installed versions are cached.
 func ObtainSchema(ctx context.Context, rootStore *state.RootStore, schemaStore *globalState.ProviderSchemaStore, modPath string) error {
 	record, err := rootStore.RootRecordByPath(modPath)
 	if err != nil {
@@ -36,6 +40,18 @@ func ObtainSchema(ctx context.Context, rootStore *state.RootStore, schemaStore *
 	// 1. it will run whenever we open a root module for the first time
 	// 2. it will run when we detect changes to a lockfile
 
+	// Schemas of all installed providers may have been cached
+	// by an earlier session, so there is no need to run tofu.
+	if record.InstalledProvidersErr == nil {
+		cached, err := schemaStore.UseCachedSchemas(record.InstalledProviders)
+		if err != nil {
+			return err
+		}
+		if cached {
+			return rootStore.FinishProviderSchemaLoading(modPath, nil)
+		}
+	}
+
 	tfExec, err := module.TofuExecutorForModule(ctx, modPath)
 	if err != nil {
 		sErr := rootStore.FinishProviderSchemaLoading(modPath, err)
@@ -54,6 +70,8 @@ func ObtainSchema(ctx context.Context, rootStore *state.RootStore, schemaStore *
 		return err
 	}
 
+	// failing to persist schemas doesn't affect the current session
+	var cacheErrs *multierror.Error
 	for rawAddr, pJsonSchema := range ps.Schemas {
 		pAddr, err := tfaddr.ParseProviderSource(rawAddr)
 		if err != nil {
@@ -71,6 +89,13 @@ func ObtainSchema(ctx context.Context, rootStore *state.RootStore, schemaStore *
 		if err != nil {
 			return err
 		}
+
+		if pv, ok := record.InstalledProviders[pAddr]; ok {
+			err = schemaStore.CacheSchema(pAddr, pv, pJsonSchema)
+			if err != nil {
+				cacheErrs = multierror.Append(cacheErrs, err)
+			}
+		}
 	}
 
 	err = rootStore.FinishProviderSchemaLoading(modPath, nil)
@@ -78,5 +103,5 @@ func ObtainSchema(ctx context.Context, rootStore *state.RootStore, schemaStore *
 		return err
 	}
 
-	return nil
+	return cacheErrs.ErrorOrNil()
 }
@@ -6,6 +6,7 @@
 package jobs
 
 import (
+	"context"
 	"errors"
 	"path/filepath"
 	"testing"
@@ -16,12 +17,17 @@ import (
 	"github.com/hashicorp/hcl-lang/schema"
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
+	tfjson "github.com/hashicorp/terraform-json"
 	tfschema "github.com/opentofu/opentofu-schema/schema"
 	tfaddr "github.com/opentofu/registry-address"
+	"github.com/opentofu/tofu-ls/internal/cache"
 	"github.com/opentofu/tofu-ls/internal/features/rootmodules/state"
 	globalState "github.com/opentofu/tofu-ls/internal/state"
 	"github.com/opentofu/tofu-ls/internal/tofu/datadir"
+	"github.com/opentofu/tofu-ls/internal/tofu/exec"
+	"github.com/stretchr/testify/mock"
 	"github.com/zclconf/go-cty-debug/ctydebug"
+	"github.com/zclconf/go-cty/cty"
 )
 
 var cmpOpts = cmp.Options{
@@ -898,6 +904,100 @@ func addAnySchema(t testOrBench, ss *globalState.ProviderSchemaStore, rs *state.
 	}
 }
 
+func TestObtainSchema_persistentCache(t *testing.T) {
+	diskCache := cache.New(t.TempDir(), 0)
+	modPath := "testdir"
+	pAddr := tfaddr.MustParseProviderSource("hashicorp/aws")
+	pVersions := map[tfaddr.Provider]*version.Version{
+		pAddr: testVersion(t, "5.0.0"),
+	}
+
+	newStores := func(t *testing.T) (*globalState.StateStore, *state.RootStore) {
+		gs, err := globalState.NewStateStore()
+		if err != nil {
+			t.Fatal(err)
+		}
+		gs.SetCache(diskCache)
+		err = gs.LoadCache()
+		if err != nil {
+			t.Fatal(err)
+		}
+		rs, err := state.NewRootStore(gs.ChangeStore, gs.ProviderSchemas)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = rs.Add(modPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = rs.UpdateInstalledProviders(modPath, pVersions, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return gs, rs
+	}
+
+	ctx := exec.WithExecutorOpts(context.Background(), &exec.ExecutorOpts{
+		ExecPath: "mock",
+	})
+
+	// first session obtains the schema via the CLI
+	gs, rs := newStores(t)
+	firstCtx := exec.WithExecutorFactory(ctx, exec.NewMockExecutor(&exec.TofuMockCalls{
+		PerWorkDir: map[string][]*mock.Call{
+			modPath: {
+				{
+					Method:        "ProviderSchemas",
+					Repeatability: 1,
+					Arguments: []interface{}{
+						mock.AnythingOfType(""),
+					},
+					ReturnArguments: []interface{}{
+						&tfjson.ProviderSchemas{
+							FormatVersion: "1.0",
+							Schemas: map[string]*tfjson.ProviderSchema{
+								"registry.opentofu.org/hashicorp/aws": {
+									ConfigSchema: &tfjson.Schema{
+										Block: &tfjson.SchemaBlock{
+											Attributes: map[string]*tfjson.SchemaAttribute{
+												"region": {
+													AttributeType: cty.String,
+													Optional:      true,
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+						nil,
+					},
+				},
+			},
+		},
+	}))
+	err := ObtainSchema(firstCtx, rs, gs.ProviderSchemas, modPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// second session loads the schema from cache without running the CLI
+	gs, rs = newStores(t)
+	secondCtx := exec.WithExecutorFactory(ctx, exec.NewMockExecutor(nil))
+	err = ObtainSchema(secondCtx, rs, gs.ProviderSchemas, modPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := gs.ProviderSchemas.ProviderSchema(modPath, pAddr, testConstraint(t, "5.0.0"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := s.Provider.Attributes["region"]; !ok {
+		t.Fatal("expected attribute from cached provider schema, not found")
+	}
+}
+
 func testVersion(t testOrBench, v string) *version.Version {
 	ver, err := version.NewVersion(v)
 	if err != nil {
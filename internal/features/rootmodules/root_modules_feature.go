@@ -11,13 +11,17 @@ import (
 	"log"
 
 	"github.com/hashicorp/go-version"
+	tfjson "github.com/hashicorp/terraform-json"
 	tfmod "github.com/opentofu/opentofu-schema/module"
 	tfaddr "github.com/opentofu/registry-address"
+	"github.com/opentofu/tofu-ls/internal/document"
 	"github.com/opentofu/tofu-ls/internal/eventbus"
 	"github.com/opentofu/tofu-ls/internal/features/rootmodules/jobs"
 	"github.com/opentofu/tofu-ls/internal/features/rootmodules/state"
+	"github.com/opentofu/tofu-ls/internal/job"
 	globalState "github.com/opentofu/tofu-ls/internal/state"
 	"github.com/opentofu/tofu-ls/internal/tofu/exec"
+	op "github.com/opentofu/tofu-ls/internal/tofu/module/operation"
 )
 
 // RootModulesFeature groups everything related to root modules. Its internal
@@ -80,6 +84,9 @@ func (f *RootModulesFeature) Start(ctx context.Context) {
 	pluginLockChangeDone := make(chan struct{}, 10)
 	pluginLockChange := f.eventbus.OnPluginLockChange("feature.rootmodules", pluginLockChangeDone)
 
+	didChangeWatchedDone := make(chan struct{}, 10)
+	didChangeWatched := f.eventbus.OnDidChangeWatched("feature.rootmodules", didChangeWatchedDone)
+
 	go func() {
 		for {
 			select {
@@ -99,6 +106,10 @@ func (f *RootModulesFeature) Start(ctx context.Context) {
 				// TODO? collect errors
 				f.pluginLockChange(pluginLockChange.Context, pluginLockChange.Dir)
 				pluginLockChangeDone <- struct{}{}
+			case didChangeWatched := <-didChangeWatched:
+				// TODO? collect errors
+				f.didChangeWatched(didChangeWatched.Context, didChangeWatched.RawPath, didChangeWatched.IsDir)
+				didChangeWatchedDone <- struct{}{}
 
 			case <-ctx.Done():
 				return
@@ -150,6 +161,65 @@ func (f *RootModulesFeature) InstalledProviders(modPath string) (map[tfaddr.Prov
 	return record.InstalledProviders, nil
 }
 
+// InitState returns whether the dependency lock file and the module
+// manifest of the root module at modPath were found when last parsed
+func (f *RootModulesFeature) InitState(modPath string) (lockFile bool, manifest bool, err error) {
+	record, err := f.Store.RootRecordByPath(modPath)
+	if err != nil {
+		return false, false, err
+	}
+
+	lockFile = record.InstalledProvidersState == op.OpStateLoaded && record.InstalledProvidersErr == nil
+	manifest = record.ModManifest != nil
+
+	return lockFile, manifest, nil
+}
+
+// PlanState returns the output of tofu show for the given root module,
+// which is either a plan or the state, or neither, if it wasn't obtained.
+func (f *RootModulesFeature) PlanState(modPath string) (*tfjson.State, *tfjson.Plan, error) {
+	record, err := f.Store.RootRecordByPath(modPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return record.TofuState, record.TofuPlan, record.TofuShowErr
+}
+
+// ParseModuleManifests schedules parsing of the module manifest of each
+// discovered root module, which is otherwise only parsed when a file of
+// the root module is opened. This allows finding callers of modules
+// across the whole workspace.
+func (f *RootModulesFeature) ParseModuleManifests(ctx context.Context) (job.IDs, error) {
+	ids := make(job.IDs, 0)
+
+	records, err := f.Store.List()
+	if err != nil {
+		return ids, err
+	}
+
+	for _, record := range records {
+		if record.ModManifestState != op.OpStateUnknown {
+			continue
+		}
+
+		dir := document.DirHandleFromPath(record.Path())
+		id, err := f.stateStore.JobStore.EnqueueJob(ctx, job.Job{
+			Dir: dir,
+			Func: func(ctx context.Context) error {
+				return jobs.ParseModuleManifest(ctx, f.fs, f.Store, dir.Path())
+			},
+			Type: op.OpTypeParseModuleManifest.String(),
+		})
+		if err != nil {
+			return ids, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
 func (f *RootModulesFeature) CallersOfModule(modPath string) ([]string, error) {
 	return f.Store.CallersOfModule(modPath)
 }
@@ -7,6 +7,7 @@ package state
 
 import (
 	"github.com/hashicorp/go-version"
+	tfjson "github.com/hashicorp/terraform-json"
 	"github.com/opentofu/tofu-ls/internal/tofu/datadir"
 	op "github.com/opentofu/tofu-ls/internal/tofu/module/operation"
 )
@@ -36,6 +37,14 @@ type RootRecord struct {
 	InstalledProviders      InstalledProviders
 	InstalledProvidersErr   error
 	InstalledProvidersState op.OpState
+
+	// TofuState or TofuPlan is the output of tofu show, which is only
+	// obtained when enabled in settings. TofuPlan is set instead of
+	// TofuState when the configured plan file exists.
+	TofuState     *tfjson.State
+	TofuPlan      *tfjson.Plan
+	TofuShowErr   error
+	TofuShowState op.OpState
 }
 
 func (m *RootRecord) Copy() *RootRecord {
@@ -59,6 +68,12 @@ func (m *RootRecord) Copy() *RootRecord {
 
 		InstalledProvidersErr:   m.InstalledProvidersErr,
 		InstalledProvidersState: m.InstalledProvidersState,
+
+		// The output of tofu show is never modified once obtained
+		TofuState:     m.TofuState,
+		TofuPlan:      m.TofuPlan,
+		TofuShowErr:   m.TofuShowErr,
+		TofuShowState: m.TofuShowState,
 	}
 
 	if m.InstalledProviders != nil {
@@ -90,6 +105,7 @@ func newRootRecord(path string) *RootRecord {
 		ModManifestState:        op.OpStateUnknown,
 		TofuVersionState:        op.OpStateUnknown,
 		InstalledProvidersState: op.OpStateUnknown,
+		TofuShowState:           op.OpStateUnknown,
 	}
 }
 
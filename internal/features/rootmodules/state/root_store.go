@@ -11,6 +11,7 @@ import (
 
 	"github.com/hashicorp/go-memdb"
 	"github.com/hashicorp/go-version"
+	tfjson "github.com/hashicorp/terraform-json"
 	tfmod "github.com/opentofu/opentofu-schema/module"
 	tfaddr "github.com/opentofu/registry-address"
 	"github.com/opentofu/tofu-ls/internal/document"
@@ -335,6 +336,50 @@ func (s *RootStore) UpdateTofuAndProviderVersions(path string, tfVer *version.Ve
 	return nil
 }
 
+func (s *RootStore) SetTofuShowState(path string, state op.OpState) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	record, err := rootRecordCopyByPath(txn, path)
+	if err != nil {
+		return err
+	}
+
+	record.TofuShowState = state
+	err = txn.Insert(s.tableName, record)
+	if err != nil {
+		return err
+	}
+
+	txn.Commit()
+	return nil
+}
+
+func (s *RootStore) UpdateTofuShow(path string, tfState *tfjson.State, plan *tfjson.Plan, showErr error) error {
+	txn := s.db.Txn(true)
+	txn.Defer(func() {
+		s.SetTofuShowState(path, op.OpStateLoaded)
+	})
+	defer txn.Abort()
+
+	record, err := rootRecordCopyByPath(txn, path)
+	if err != nil {
+		return err
+	}
+
+	record.TofuState = tfState
+	record.TofuPlan = plan
+	record.TofuShowErr = showErr
+
+	err = txn.Insert(s.tableName, record)
+	if err != nil {
+		return err
+	}
+
+	txn.Commit()
+	return nil
+}
+
 func (s *RootStore) CallersOfModule(path string) ([]string, error) {
 	txn := s.db.Txn(false)
 	it, err := txn.Get(s.tableName, "id")
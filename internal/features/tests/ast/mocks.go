@@ -0,0 +1,119 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package ast
+
+import (
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	globalAst "github.com/opentofu/tofu-ls/internal/tofu/ast"
+)
+
+var mockExtensions = []string{".tfmock.hcl", ".tfmock.json"}
+
+type MockFilename string
+
+func (mf MockFilename) String() string {
+	return string(mf)
+}
+
+func (mf MockFilename) IsJSON() bool {
+	return strings.HasSuffix(string(mf), ".json")
+}
+
+func (mf MockFilename) IsIgnored() bool {
+	return globalAst.IsIgnoredFile(string(mf))
+}
+
+// IsMockFilename returns true if the given filename is a mock
+// data file, i.e. *.tfmock.hcl or *.tfmock.json.
+func IsMockFilename(name string) bool {
+	for _, ext := range mockExtensions {
+		if strings.HasSuffix(name, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+type MockFiles map[MockFilename]*hcl.File
+
+func MockFilesFromMap(m map[string]*hcl.File) MockFiles {
+	mf := make(MockFiles, len(m))
+	for name, file := range m {
+		mf[MockFilename(name)] = file
+	}
+	return mf
+}
+
+func (mf MockFiles) AsMap() map[string]*hcl.File {
+	m := make(map[string]*hcl.File, len(mf))
+	for name, file := range mf {
+		m[string(name)] = file
+	}
+	return m
+}
+
+func (mf MockFiles) Copy() MockFiles {
+	m := make(MockFiles, len(mf))
+	for name, file := range mf {
+		m[name] = file
+	}
+	return m
+}
+
+type MockDiags map[MockFilename]hcl.Diagnostics
+
+func MockDiagsFromMap(m map[string]hcl.Diagnostics) MockDiags {
+	md := make(MockDiags, len(m))
+	for name, diags := range m {
+		md[MockFilename(name)] = diags
+	}
+	return md
+}
+
+// AutoloadedOnly returns only diagnostics that are not from ignored files
+func (md MockDiags) AutoloadedOnly() MockDiags {
+	diags := make(MockDiags)
+	for name, f := range md {
+		if !name.IsIgnored() {
+			diags[name] = f
+		}
+	}
+	return diags
+}
+
+func (md MockDiags) AsMap() map[string]hcl.Diagnostics {
+	m := make(map[string]hcl.Diagnostics, len(md))
+	for name, diags := range md {
+		m[string(name)] = diags
+	}
+	return m
+}
+
+func (md MockDiags) Copy() MockDiags {
+	m := make(MockDiags, len(md))
+	for name, diags := range md {
+		m[name] = diags
+	}
+	return m
+}
+
+func (md MockDiags) Count() int {
+	count := 0
+	for _, diags := range md {
+		count += len(diags)
+	}
+	return count
+}
+
+type SourceMockDiags map[globalAst.DiagnosticSource]MockDiags
+
+func (smd SourceMockDiags) Count() int {
+	count := 0
+	for _, diags := range smd {
+		count += diags.Count()
+	}
+	return count
+}
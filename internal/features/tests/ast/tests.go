@@ -0,0 +1,140 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package ast
+
+import (
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	globalAst "github.com/opentofu/tofu-ls/internal/tofu/ast"
+)
+
+var (
+	tofuTestExtensions = []string{".tofutest.hcl", ".tofutest.json"}
+	tfTestExtensions   = []string{".tftest.hcl", ".tftest.json"}
+)
+
+type TestFilename string
+
+func (tf TestFilename) String() string {
+	return string(tf)
+}
+
+func (tf TestFilename) IsJSON() bool {
+	return strings.HasSuffix(string(tf), ".json")
+}
+
+func (tf TestFilename) IsIgnored() bool {
+	return globalAst.IsIgnoredFile(string(tf))
+}
+
+// IsTestFilename returns true if the given filename is an OpenTofu
+// test file, i.e. *.tftest.hcl, *.tofutest.hcl or their JSON variants.
+func IsTestFilename(name string) bool {
+	for _, ext := range tofuTestExtensions {
+		if strings.HasSuffix(name, ext) {
+			return true
+		}
+	}
+	for _, ext := range tfTestExtensions {
+		if strings.HasSuffix(name, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// TofuTestFilenameFor returns the name of the .tofutest.* file which
+// takes precedence over the given .tftest.* file, if there is any.
+// OpenTofu ignores foo.tftest.hcl whenever foo.tofutest.hcl exists
+// in the same directory.
+func TofuTestFilenameFor(name string) (string, bool) {
+	for i, ext := range tfTestExtensions {
+		if strings.HasSuffix(name, ext) {
+			return strings.TrimSuffix(name, ext) + tofuTestExtensions[i], true
+		}
+	}
+	return "", false
+}
+
+type TestFiles map[TestFilename]*hcl.File
+
+func TestFilesFromMap(m map[string]*hcl.File) TestFiles {
+	tf := make(TestFiles, len(m))
+	for name, file := range m {
+		tf[TestFilename(name)] = file
+	}
+	return tf
+}
+
+func (tf TestFiles) AsMap() map[string]*hcl.File {
+	m := make(map[string]*hcl.File, len(tf))
+	for name, file := range tf {
+		m[string(name)] = file
+	}
+	return m
+}
+
+func (tf TestFiles) Copy() TestFiles {
+	m := make(TestFiles, len(tf))
+	for name, file := range tf {
+		m[name] = file
+	}
+	return m
+}
+
+type TestDiags map[TestFilename]hcl.Diagnostics
+
+func TestDiagsFromMap(m map[string]hcl.Diagnostics) TestDiags {
+	td := make(TestDiags, len(m))
+	for name, diags := range m {
+		td[TestFilename(name)] = diags
+	}
+	return td
+}
+
+// AutoloadedOnly returns only diagnostics that are not from ignored files
+func (td TestDiags) AutoloadedOnly() TestDiags {
+	diags := make(TestDiags)
+	for name, f := range td {
+		if !name.IsIgnored() {
+			diags[name] = f
+		}
+	}
+	return diags
+}
+
+func (td TestDiags) AsMap() map[string]hcl.Diagnostics {
+	m := make(map[string]hcl.Diagnostics, len(td))
+	for name, diags := range td {
+		m[string(name)] = diags
+	}
+	return m
+}
+
+func (td TestDiags) Copy() TestDiags {
+	m := make(TestDiags, len(td))
+	for name, diags := range td {
+		m[name] = diags
+	}
+	return m
+}
+
+func (td TestDiags) Count() int {
+	count := 0
+	for _, diags := range td {
+		count += len(diags)
+	}
+	return count
+}
+
+type SourceTestDiags map[globalAst.DiagnosticSource]TestDiags
+
+func (std SourceTestDiags) Count() int {
+	count := 0
+	for _, diags := range std {
+		count += diags.Count()
+	}
+	return count
+}
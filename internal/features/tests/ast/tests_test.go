@@ -0,0 +1,105 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package ast
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty-debug/ctydebug"
+)
+
+func TestIsTestFilename(t *testing.T) {
+	testCases := []struct {
+		name     string
+		expected bool
+	}{
+		{"main.tftest.hcl", true},
+		{"main.tofutest.hcl", true},
+		{"main.tftest.json", true},
+		{"main.tofutest.json", true},
+		{"main.tf", false},
+		{"main.tfmock.hcl", false},
+		{"tftest.hcl.bak", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := IsTestFilename(tc.name); got != tc.expected {
+				t.Fatalf("expected %t, got %t", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestIsMockFilename(t *testing.T) {
+	testCases := []struct {
+		name     string
+		expected bool
+	}{
+		{"aws.tfmock.hcl", true},
+		{"aws.tfmock.json", true},
+		{"main.tftest.hcl", false},
+		{"main.tf", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := IsMockFilename(tc.name); got != tc.expected {
+				t.Fatalf("expected %t, got %t", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestTofuTestFilenameFor(t *testing.T) {
+	name, ok := TofuTestFilenameFor("main.tftest.hcl")
+	if !ok || name != "main.tofutest.hcl" {
+		t.Fatalf("unexpected name: %q (%t)", name, ok)
+	}
+
+	name, ok = TofuTestFilenameFor("main.tftest.json")
+	if !ok || name != "main.tofutest.json" {
+		t.Fatalf("unexpected name: %q (%t)", name, ok)
+	}
+
+	_, ok = TofuTestFilenameFor("main.tofutest.hcl")
+	if ok {
+		t.Fatal("expected no precedence for .tofutest.hcl file")
+	}
+}
+
+func TestTestDiags_autoloadedOnly(t *testing.T) {
+	td := TestDiagsFromMap(map[string]hcl.Diagnostics{
+		"main.tftest.hcl": {
+			{
+				Severity: hcl.DiagError,
+				Summary:  "Test error",
+				Detail:   "Test description",
+			},
+		},
+		".hidden.tftest.hcl": {
+			{
+				Severity: hcl.DiagError,
+				Summary:  "Test error",
+				Detail:   "Test description",
+			},
+		},
+	})
+	diags := td.AutoloadedOnly().AsMap()
+	expectedDiags := map[string]hcl.Diagnostics{
+		"main.tftest.hcl": {
+			{
+				Severity: hcl.DiagError,
+				Summary:  "Test error",
+				Detail:   "Test description",
+			},
+		},
+	}
+
+	if diff := cmp.Diff(expectedDiags, diags, ctydebug.CmpOptions); diff != "" {
+		t.Fatalf("unexpected diagnostics: %s", diff)
+	}
+}
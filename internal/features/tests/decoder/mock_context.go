@@ -0,0 +1,52 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"github.com/hashicorp/hcl-lang/decoder"
+	"github.com/hashicorp/hcl-lang/reference"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl/v2"
+	tfschema "github.com/opentofu/opentofu-schema/schema"
+	"github.com/opentofu/tofu-ls/internal/features/tests/state"
+)
+
+func mockPathContext(record *state.TestRecord, reader CombinedReader) (*decoder.PathContext, error) {
+	files := record.ParsedMockFiles.AsMap()
+	modPath := ModulePathForTests(record.Path())
+	resolvedVersion := resolveVersion(modPath, reader)
+
+	bodySchema, err := schemaForMock(record, resolvedVersion, reader)
+	if err != nil {
+		return nil, err
+	}
+	resources, dataSources := mockDependentBodies(reader.SchemaReader,
+		modPath, mockedProviders(files))
+	bodySchema = withBlockChange(bodySchema, "mock_resource", func(block *schema.BlockSchema) {
+		block.DependentBody = resources
+	})
+	bodySchema = withBlockChange(bodySchema, "mock_data", func(block *schema.BlockSchema) {
+		block.DependentBody = dataSources
+	})
+
+	functions, err := tfschema.FunctionsForVersion(resolvedVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	pathCtx := &decoder.PathContext{
+		Schema:           bodySchema,
+		ReferenceOrigins: make(reference.Origins, 0),
+		ReferenceTargets: make(reference.Targets, 0),
+		Files:            make(map[string]*hcl.File),
+		Functions:        functions,
+		Validators:       mockValidators,
+	}
+
+	for name, f := range files {
+		pathCtx.Files[name] = f
+	}
+
+	return pathCtx, nil
+}
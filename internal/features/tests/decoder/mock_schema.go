@@ -0,0 +1,196 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"strings"
+
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl/v2"
+	testschema "github.com/opentofu/opentofu-schema/schema/tests"
+	tftest "github.com/opentofu/opentofu-schema/test"
+	tfaddr "github.com/opentofu/registry-address"
+	"github.com/opentofu/tofu-ls/internal/features/tests/state"
+)
+
+// schemaForMock returns the schema for mock data
+// files (*.tfmock.hcl) relevant for the given version.
+func schemaForMock(record *state.TestRecord, v *version.Version, reader CombinedReader) (*schema.BodySchema, error) {
+	coreSchema, err := testschema.CoreMockSchemaForVersion(v)
+	if err != nil {
+		return nil, err
+	}
+
+	sm := testschema.NewMockSchemaMerger(coreSchema)
+	sm.SetStateReader(reader)
+
+	filenames := make([]string, 0, len(record.ParsedMockFiles))
+	for name := range record.ParsedMockFiles {
+		filenames = append(filenames, name.String())
+	}
+	return sm.SchemaForMock(&tftest.Meta{
+		Path:      record.Path(),
+		Filenames: filenames,
+	})
+}
+
+var mockBlocksSchema = &hcl.BodySchema{
+	Blocks: []hcl.BlockHeaderSchema{
+		{Type: "mock_provider", LabelNames: []string{"name"}},
+		{Type: "mock_resource", LabelNames: []string{"type"}},
+		{Type: "mock_data", LabelNames: []string{"type"}},
+	},
+}
+
+// mockedProviders returns names of providers which are mocked
+// within the given files, based on labels of mock_provider blocks
+// and on the prefix of resource types in mock_resource
+// and mock_data blocks (e.g. aws for aws_instance).
+func mockedProviders(files map[string]*hcl.File) []string {
+	seen := make(map[string]struct{})
+	names := make([]string, 0)
+	add := func(name string) {
+		if _, ok := seen[name]; ok || name == "" {
+			return
+		}
+		seen[name] = struct{}{}
+		names = append(names, name)
+	}
+
+	var collect func(body hcl.Body)
+	collect = func(body hcl.Body) {
+		content, _, _ := body.PartialContent(mockBlocksSchema)
+		for _, block := range content.Blocks {
+			switch block.Type {
+			case "mock_provider":
+				add(block.Labels[0])
+				collect(block.Body)
+			case "mock_resource", "mock_data":
+				name, _, _ := strings.Cut(block.Labels[0], "_")
+				add(name)
+			}
+		}
+	}
+
+	for _, f := range files {
+		if f == nil || f.Body == nil {
+			continue
+		}
+		collect(f.Body)
+	}
+
+	return names
+}
+
+// mockDependentBodies builds the dependent bodies of mock_resource
+// and mock_data blocks, such that the defaults attribute reflects
+// the schema of the given resource or data source type.
+func mockDependentBodies(schemaReader SchemaReader, modPath string, providerNames []string) (resources, dataSources map[schema.SchemaKey]*schema.BodySchema) {
+	resources = make(map[schema.SchemaKey]*schema.BodySchema)
+	dataSources = make(map[schema.SchemaKey]*schema.BodySchema)
+	if schemaReader == nil {
+		return
+	}
+
+	for _, name := range providerNames {
+		pType, err := tfaddr.ParseProviderPart(name)
+		if err != nil {
+			continue
+		}
+		// The mocked provider is only known by its local name,
+		// so we look it up as a legacy provider, which matches
+		// any provider of the same type.
+		addr := tfaddr.Provider{
+			Type:      pType,
+			Namespace: tfaddr.LegacyProviderNamespace,
+			Hostname:  tfaddr.DefaultProviderRegistryHost,
+		}
+		pSchema, err := schemaReader.ProviderSchema(modPath, addr, nil)
+		if err != nil || pSchema == nil {
+			continue
+		}
+
+		for rType, body := range pSchema.Resources {
+			resources[typeSchemaKey(rType)] = mockDefaultsBodySchema(body)
+		}
+		for dsType, body := range pSchema.DataSources {
+			dataSources[typeSchemaKey(dsType)] = mockDefaultsBodySchema(body)
+		}
+	}
+
+	return
+}
+
+func typeSchemaKey(typeName string) schema.SchemaKey {
+	return schema.NewSchemaKey(schema.DependencyKeys{
+		Labels: []schema.LabelDependent{
+			{Index: 0, Value: typeName},
+		},
+	})
+}
+
+func mockDefaultsBodySchema(body *schema.BodySchema) *schema.BodySchema {
+	return &schema.BodySchema{
+		Description: body.Description,
+		Attributes: map[string]*schema.AttributeSchema{
+			"defaults": {
+				Constraint: schema.Object{
+					Attributes: objectAttributesFromBody(body),
+				},
+				IsOptional:  true,
+				Description: lang.Markdown("Values to return for computed attributes"),
+			},
+		},
+	}
+}
+
+// objectAttributesFromBody turns attributes and nested blocks
+// of the given body into attributes of an object, since mocked
+// values are always expressed as an object.
+func objectAttributesFromBody(body *schema.BodySchema) schema.ObjectAttributes {
+	attrs := make(schema.ObjectAttributes)
+	if body == nil {
+		return attrs
+	}
+
+	for name, attr := range body.Attributes {
+		attrs[name] = &schema.AttributeSchema{
+			Description:  attr.Description,
+			IsOptional:   true,
+			IsDeprecated: attr.IsDeprecated,
+			IsSensitive:  attr.IsSensitive,
+			Constraint:   attr.Constraint,
+		}
+	}
+
+	for name, block := range body.Blocks {
+		obj := schema.Object{
+			Attributes:  objectAttributesFromBody(block.Body),
+			Description: block.Description,
+		}
+
+		var cons schema.Constraint
+		switch block.Type {
+		case schema.BlockTypeList:
+			cons = schema.List{Elem: obj}
+		case schema.BlockTypeSet:
+			cons = schema.Set{Elem: obj}
+		case schema.BlockTypeMap:
+			cons = schema.Map{Elem: obj}
+		default:
+			cons = obj
+		}
+
+		attrs[name] = &schema.AttributeSchema{
+			Description:  block.Description,
+			IsOptional:   true,
+			IsDeprecated: block.IsDeprecated,
+			Constraint:   cons,
+		}
+	}
+
+	return attrs
+}
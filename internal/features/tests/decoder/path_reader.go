@@ -0,0 +1,95 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"context"
+
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl-lang/decoder"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/reference"
+	tfmod "github.com/opentofu/opentofu-schema/module"
+	tfschema "github.com/opentofu/opentofu-schema/schema"
+	tfaddr "github.com/opentofu/registry-address"
+	"github.com/opentofu/tofu-ls/internal/features/tests/state"
+	ilsp "github.com/opentofu/tofu-ls/internal/lsp"
+)
+
+type StateReader interface {
+	List() ([]*state.TestRecord, error)
+	TestRecordByPath(path string) (*state.TestRecord, error)
+}
+
+type ModuleReader interface {
+	ModuleReferenceTargets(modPath string) (reference.Targets, error)
+	LocalModuleMeta(modPath string) (*tfmod.Meta, error)
+}
+
+type RootReader interface {
+	TofuVersion(modPath string) *version.Version
+}
+
+type SchemaReader interface {
+	ProviderSchema(modPath string, addr tfaddr.Provider, vc version.Constraints) (*tfschema.ProviderSchema, error)
+}
+
+type CombinedReader struct {
+	ModuleReader
+	RootReader
+	SchemaReader
+}
+
+type PathReader struct {
+	StateReader  StateReader
+	ModuleReader ModuleReader
+	RootReader   RootReader
+	SchemaReader SchemaReader
+}
+
+var _ decoder.PathReader = &PathReader{}
+
+func (pr *PathReader) Paths(ctx context.Context) []lang.Path {
+	paths := make([]lang.Path, 0)
+
+	testRecords, err := pr.StateReader.List()
+	if err != nil {
+		return paths
+	}
+
+	for _, record := range testRecords {
+		paths = append(paths, lang.Path{
+			Path:       record.Path(),
+			LanguageID: ilsp.OpenTofuTest.String(),
+		})
+		if len(record.ParsedMockFiles) > 0 {
+			paths = append(paths, lang.Path{
+				Path:       record.Path(),
+				LanguageID: ilsp.OpenTofuMock.String(),
+			})
+		}
+	}
+
+	return paths
+}
+
+// PathContext returns a PathContext for the given path based on the language ID.
+func (pr *PathReader) PathContext(path lang.Path) (*decoder.PathContext, error) {
+	record, err := pr.StateReader.TestRecordByPath(path.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	reader := CombinedReader{
+		ModuleReader: pr.ModuleReader,
+		RootReader:   pr.RootReader,
+		SchemaReader: pr.SchemaReader,
+	}
+
+	if path.LanguageID == ilsp.OpenTofuMock.String() {
+		return mockPathContext(record, reader)
+	}
+
+	return testPathContext(record, reader)
+}
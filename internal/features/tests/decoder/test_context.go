@@ -0,0 +1,103 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"path/filepath"
+
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl-lang/decoder"
+	"github.com/hashicorp/hcl-lang/reference"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl/v2"
+	tfschema "github.com/opentofu/opentofu-schema/schema"
+	"github.com/opentofu/tofu-ls/internal/features/tests/ast"
+	"github.com/opentofu/tofu-ls/internal/features/tests/state"
+)
+
+// defaultTestDirectory is the directory in which OpenTofu
+// looks for test files in addition to the module directory.
+const defaultTestDirectory = "tests"
+
+// ModulePathForTests returns the path of the module which
+// the test files in the given directory are testing.
+func ModulePathForTests(testPath string) string {
+	if filepath.Base(testPath) == defaultTestDirectory {
+		return filepath.Dir(testPath)
+	}
+	return testPath
+}
+
+func testPathContext(record *state.TestRecord, reader CombinedReader) (*decoder.PathContext, error) {
+	modPath := ModulePathForTests(record.Path())
+	resolvedVersion := resolveVersion(modPath, reader)
+
+	bodySchema, err := schemaForTest(record, resolvedVersion, reader)
+	if err != nil {
+		return nil, err
+	}
+	// Mocked resources and data sources of mock_provider blocks
+	// are completed and validated against the provider schema
+	if _, ok := bodySchema.Blocks["mock_provider"]; ok {
+		resources, dataSources := mockDependentBodies(reader.SchemaReader,
+			modPath, mockedProviders(record.ParsedTestFiles.AsMap()))
+		bodySchema = withBlockChange(bodySchema, "mock_provider", func(mockProvider *schema.BlockSchema) {
+			mockProvider.Body = withBlockChange(mockProvider.Body, "mock_resource", func(block *schema.BlockSchema) {
+				block.DependentBody = resources
+			})
+			mockProvider.Body = withBlockChange(mockProvider.Body, "mock_data", func(block *schema.BlockSchema) {
+				block.DependentBody = dataSources
+			})
+		})
+	}
+
+	functions, err := tfschema.FunctionsForVersion(resolvedVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	pathCtx := &decoder.PathContext{
+		Schema:           bodySchema,
+		ReferenceOrigins: make(reference.Origins, 0),
+		ReferenceTargets: make(reference.Targets, 0),
+		Files:            make(map[string]*hcl.File),
+		Functions:        functions,
+		Validators:       testValidators,
+	}
+
+	for _, origin := range record.RefOrigins {
+		if ast.IsTestFilename(origin.OriginRange().Filename) {
+			pathCtx.ReferenceOrigins = append(pathCtx.ReferenceOrigins, origin)
+		}
+	}
+	for _, target := range record.RefTargets {
+		pathCtx.ReferenceTargets = append(pathCtx.ReferenceTargets, target)
+	}
+
+	// Assertions are evaluated in the scope of the module under test,
+	// so we make its targets available for completion and hover.
+	// Origins pointing to these are stored as path origins, so
+	// go-to-definition still resolves them within the module.
+	moduleTargets, err := reader.ModuleReferenceTargets(modPath)
+	if err == nil {
+		pathCtx.ReferenceTargets = append(pathCtx.ReferenceTargets, moduleTargets...)
+	}
+
+	for name, f := range record.ParsedTestFiles {
+		pathCtx.Files[name.String()] = f
+	}
+
+	return pathCtx, nil
+}
+
+// resolveVersion resolves the OpenTofu version of the module
+// under test, the same way as for the module itself.
+func resolveVersion(modPath string, reader CombinedReader) *version.Version {
+	var coreRequirements version.Constraints
+	meta, err := reader.LocalModuleMeta(modPath)
+	if err == nil {
+		coreRequirements = meta.CoreRequirements
+	}
+	return tfschema.ResolveVersion(reader.TofuVersion(modPath), coreRequirements)
+}
@@ -0,0 +1,95 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"maps"
+
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/schema"
+	testschema "github.com/opentofu/opentofu-schema/schema/tests"
+	tftest "github.com/opentofu/opentofu-schema/test"
+	"github.com/opentofu/tofu-ls/internal/features/tests/state"
+)
+
+// These mirror the scopes used by the module schema,
+// so that references to module objects match up.
+var (
+	providerScopeId = lang.ScopeId("provider")
+	runScopeId      = lang.ScopeId("run")
+)
+
+// schemaForTest returns the schema for test files
+// (*.tftest.hcl or *.tofutest.hcl) relevant for the given version.
+func schemaForTest(record *state.TestRecord, v *version.Version, reader CombinedReader) (*schema.BodySchema, error) {
+	coreSchema, err := testschema.CoreTestSchemaForVersion(v)
+	if err != nil {
+		return nil, err
+	}
+
+	sm := testschema.NewTestSchemaMerger(coreSchema)
+	sm.SetStateReader(reader)
+
+	filenames := make([]string, 0, len(record.ParsedTestFiles))
+	for name := range record.ParsedTestFiles {
+		filenames = append(filenames, name.String())
+	}
+	bodySchema, err := sm.SchemaForTest(&tftest.Meta{
+		Path:      record.Path(),
+		Filenames: filenames,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Outputs of run blocks can be referenced by later run blocks
+	// and mocked providers can be passed to the module under test
+	// as any other provider configuration.
+	bodySchema = withBlockChange(bodySchema, "run", func(run *schema.BlockSchema) {
+		run.Address = &schema.BlockAddrSchema{
+			Steps: []schema.AddrStep{
+				schema.StaticStep{Name: "run"},
+				schema.LabelStep{Index: 0},
+			},
+			FriendlyName: "run",
+			ScopeId:      runScopeId,
+			AsReference:  true,
+		}
+	})
+	bodySchema = withBlockChange(bodySchema, "mock_provider", func(mockProvider *schema.BlockSchema) {
+		mockProvider.Address = &schema.BlockAddrSchema{
+			Steps: []schema.AddrStep{
+				schema.LabelStep{Index: 0},
+				schema.AttrValueStep{Name: "alias", IsOptional: true},
+			},
+			FriendlyName: "provider",
+			ScopeId:      providerScopeId,
+			AsReference:  true,
+		}
+	})
+
+	return bodySchema, nil
+}
+
+// withBlockChange returns a copy of body, in which the block of the given
+// type, if there is any, is replaced by a copy with the change applied.
+// The body itself is left unchanged, as it may be part of a versioned
+// schema, which is shared by all paths.
+func withBlockChange(body *schema.BodySchema, blockType string, change func(*schema.BlockSchema)) *schema.BodySchema {
+	if body == nil {
+		return body
+	}
+	block, ok := body.Blocks[blockType]
+	if !ok {
+		return body
+	}
+	block = block.Copy()
+	change(block)
+
+	newBody := *body
+	newBody.Blocks = maps.Clone(body.Blocks)
+	newBody.Blocks[blockType] = block
+	return &newBody
+}
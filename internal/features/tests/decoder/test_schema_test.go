@@ -0,0 +1,38 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl-lang/schema"
+)
+
+func TestWithBlockChange(t *testing.T) {
+	shared := &schema.BodySchema{
+		Blocks: map[string]*schema.BlockSchema{
+			"run": {
+				MaxItems: 1,
+			},
+		},
+	}
+
+	body := withBlockChange(shared, "run", func(run *schema.BlockSchema) {
+		run.MaxItems = 2
+	})
+	if body.Blocks["run"].MaxItems != 2 {
+		t.Fatalf("expected block to be changed, given: %d", body.Blocks["run"].MaxItems)
+	}
+	if shared.Blocks["run"].MaxItems != 1 {
+		t.Fatalf("expected shared block to be unchanged, given: %d", shared.Blocks["run"].MaxItems)
+	}
+
+	// Schemas without the block are returned as is
+	body = withBlockChange(shared, "mock_provider", func(*schema.BlockSchema) {
+		t.Fatal("unexpected change of missing block")
+	})
+	if body != shared {
+		t.Fatal("expected body without the block to be returned unchanged")
+	}
+}
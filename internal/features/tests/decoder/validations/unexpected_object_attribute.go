@@ -0,0 +1,111 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package validations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// UnexpectedObjectAttribute reports keys of object expressions
+// which are not declared by the object constraint of the attribute,
+// e.g. an unknown attribute in defaults of a mock_resource block.
+//
+// Objects without any declared attributes are not validated,
+// since we cannot tell which keys are expected there.
+type UnexpectedObjectAttribute struct{}
+
+func (v UnexpectedObjectAttribute) Visit(ctx context.Context, node hclsyntax.Node, nodeSchema schema.Schema) (context.Context, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	attr, ok := node.(*hclsyntax.Attribute)
+	if !ok {
+		return ctx, diags
+	}
+
+	attrSchema, ok := nodeSchema.(*schema.AttributeSchema)
+	if !ok || attrSchema == nil {
+		return ctx, diags
+	}
+
+	return ctx, validateObjectExpr(attr.Expr, attrSchema.Constraint)
+}
+
+func validateObjectExpr(expr hclsyntax.Expression, cons schema.Constraint) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	switch c := cons.(type) {
+	case schema.Object:
+		obj, ok := expr.(*hclsyntax.ObjectConsExpr)
+		if !ok || len(c.Attributes) == 0 {
+			return diags
+		}
+
+		for _, item := range obj.Items {
+			name, ok := objectKeyName(item.KeyExpr)
+			if !ok {
+				continue
+			}
+
+			aSchema, ok := c.Attributes[name]
+			if !ok {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Unexpected attribute",
+					Detail:   fmt.Sprintf("An attribute named %q is not expected here", name),
+					Subject:  item.KeyExpr.Range().Ptr(),
+				})
+				continue
+			}
+			diags = append(diags, validateObjectExpr(item.ValueExpr, aSchema.Constraint)...)
+		}
+	case schema.List:
+		diags = append(diags, validateTupleExpr(expr, c.Elem)...)
+	case schema.Set:
+		diags = append(diags, validateTupleExpr(expr, c.Elem)...)
+	case schema.Map:
+		obj, ok := expr.(*hclsyntax.ObjectConsExpr)
+		if !ok {
+			return diags
+		}
+		for _, item := range obj.Items {
+			diags = append(diags, validateObjectExpr(item.ValueExpr, c.Elem)...)
+		}
+	}
+
+	return diags
+}
+
+func validateTupleExpr(expr hclsyntax.Expression, elem schema.Constraint) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	tuple, ok := expr.(*hclsyntax.TupleConsExpr)
+	if !ok {
+		return diags
+	}
+	for _, elemExpr := range tuple.Exprs {
+		diags = append(diags, validateObjectExpr(elemExpr, elem)...)
+	}
+
+	return diags
+}
+
+// objectKeyName returns the static name of an object key,
+// i.e. either a bare keyword or a string without interpolation.
+func objectKeyName(expr hclsyntax.Expression) (string, bool) {
+	if name := hcl.ExprAsKeyword(expr); name != "" {
+		return name, true
+	}
+
+	val, diags := expr.Value(nil)
+	if diags.HasErrors() || !val.IsWhollyKnown() || val.Type() != cty.String || val.IsNull() {
+		return "", false
+	}
+	return val.AsString(), true
+}
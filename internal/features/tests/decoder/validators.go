@@ -0,0 +1,27 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"github.com/hashicorp/hcl-lang/validator"
+	"github.com/opentofu/tofu-ls/internal/features/tests/decoder/validations"
+)
+
+var testValidators = []validator.Validator{
+	validator.BlockLabelsLength{},
+	validator.MaxBlocks{},
+	validator.MinBlocks{},
+	validator.MissingRequiredAttribute{},
+	validator.UnexpectedAttribute{},
+	validator.UnexpectedBlock{},
+	validations.UnexpectedObjectAttribute{},
+}
+
+var mockValidators = []validator.Validator{
+	validator.BlockLabelsLength{},
+	validator.MissingRequiredAttribute{},
+	validator.UnexpectedAttribute{},
+	validator.UnexpectedBlock{},
+	validations.UnexpectedObjectAttribute{},
+}
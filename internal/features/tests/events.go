@@ -0,0 +1,282 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package tests
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	lsctx "github.com/opentofu/tofu-ls/internal/context"
+	"github.com/opentofu/tofu-ls/internal/document"
+	"github.com/opentofu/tofu-ls/internal/features/tests/ast"
+	"github.com/opentofu/tofu-ls/internal/features/tests/jobs"
+	"github.com/opentofu/tofu-ls/internal/job"
+	"github.com/opentofu/tofu-ls/internal/lsp"
+	"github.com/opentofu/tofu-ls/internal/protocol"
+	op "github.com/opentofu/tofu-ls/internal/tofu/module/operation"
+)
+
+func (f *TestsFeature) discover(path string, files []string) error {
+	for _, file := range files {
+		isTestFile := ast.IsTestFilename(file) && !ast.TestFilename(file).IsIgnored()
+		isMockFile := ast.IsMockFilename(file) && !ast.MockFilename(file).IsIgnored()
+		if isTestFile || isMockFile {
+			f.logger.Printf("discovered test file in %s", path)
+
+			err := f.store.AddIfNotExists(path)
+			if err != nil {
+				return err
+			}
+
+			break
+		}
+	}
+
+	return nil
+}
+
+func (f *TestsFeature) didOpen(ctx context.Context, dir document.DirHandle, languageID string) (job.IDs, error) {
+	ids := make(job.IDs, 0)
+	path := dir.Path()
+
+	// We need to decide if the path is relevant to us. It can be relevant because
+	// a) the walker discovered test files and created a state entry for them
+	// b) the opened file is a test or mock data file
+	//
+	// Add to state if language ID matches
+	if lsp.IsValidTestLanguage(languageID) || lsp.IsValidMockLanguage(languageID) {
+		err := f.store.AddIfNotExists(path)
+		if err != nil {
+			return ids, err
+		}
+	}
+
+	// Schedule jobs if state entry exists
+	hasTestRecord := f.store.Exists(path)
+	if !hasTestRecord {
+		return ids, nil
+	}
+
+	return f.decodeTest(ctx, dir, false)
+}
+
+func (f *TestsFeature) didChange(ctx context.Context, dir document.DirHandle) (job.IDs, error) {
+	hasTestRecord := f.store.Exists(dir.Path())
+	if !hasTestRecord {
+		return job.IDs{}, nil
+	}
+
+	return f.decodeTest(ctx, dir, true)
+}
+
+func (f *TestsFeature) didChangeWatched(ctx context.Context, rawPath string, changeType protocol.FileChangeType, isDir bool) (job.IDs, error) {
+	ids := make(job.IDs, 0)
+
+	if changeType == protocol.Deleted {
+		// We don't know whether file or dir is being deleted
+		// 1st we just blindly try to look it up as a directory
+		hasTestRecord := f.store.Exists(rawPath)
+		if hasTestRecord {
+			f.removeIndexedTest(rawPath)
+			return ids, nil
+		}
+
+		// 2nd we try again assuming it is a file
+		parentDir := filepath.Dir(rawPath)
+		hasTestRecord = f.store.Exists(parentDir)
+		if !hasTestRecord {
+			// Nothing relevant found in the feature state
+			return ids, nil
+		}
+
+		// and check the parent directory still exists
+		fi, err := os.Stat(parentDir)
+		if err != nil {
+			if os.IsNotExist(err) {
+				// if not, we remove the indexed test directory
+				f.removeIndexedTest(rawPath)
+				return ids, nil
+			}
+			f.logger.Printf("error checking existence (%q deleted): %s", parentDir, err)
+			return ids, nil
+		}
+		if !fi.IsDir() {
+			// Should never happen
+			f.logger.Printf("error: %q (deleted) is not a directory", parentDir)
+			return ids, nil
+		}
+
+		// If the parent directory exists, we just need to
+		// check if the there are open documents for the path and the
+		// path is a test path. If so, we need to reparse the test files
+		dir := document.DirHandleFromPath(parentDir)
+		hasOpenDocs, err := f.stateStore.DocumentStore.HasOpenDocuments(dir)
+		if err != nil {
+			f.logger.Printf("error when checking for open documents in path (%q deleted): %s", rawPath, err)
+		}
+		if !hasOpenDocs {
+			return ids, nil
+		}
+
+		f.decodeTest(ctx, dir, true)
+	}
+
+	if changeType == protocol.Changed {
+		docHandle := document.HandleFromPath(rawPath)
+		// Check if the there are open documents for the path and the
+		// path is a test path. If so, we need to reparse the test files
+		hasOpenDocs, err := f.stateStore.DocumentStore.HasOpenDocuments(docHandle.Dir)
+		if err != nil {
+			f.logger.Printf("error when checking for open documents in path (%q changed): %s", rawPath, err)
+		}
+		if !hasOpenDocs {
+			return ids, nil
+		}
+
+		hasTestRecord := f.store.Exists(docHandle.Dir.Path())
+		if !hasTestRecord {
+			return ids, nil
+		}
+
+		f.decodeTest(ctx, docHandle.Dir, true)
+	}
+
+	if changeType == protocol.Created {
+		var dir document.DirHandle
+		if isDir {
+			dir = document.DirHandleFromPath(rawPath)
+		} else {
+			docHandle := document.HandleFromPath(rawPath)
+			dir = docHandle.Dir
+		}
+
+		// Check if the there are open documents for the path and the
+		// path is a test path. If so, we need to reparse the test files
+		hasOpenDocs, err := f.stateStore.DocumentStore.HasOpenDocuments(dir)
+		if err != nil {
+			f.logger.Printf("error when checking for open documents in path (%q changed): %s", rawPath, err)
+		}
+		if !hasOpenDocs {
+			return ids, nil
+		}
+
+		hasTestRecord := f.store.Exists(dir.Path())
+		if !hasTestRecord {
+			return ids, nil
+		}
+
+		f.decodeTest(ctx, dir, true)
+	}
+
+	return ids, nil
+}
+
+func (f *TestsFeature) removeIndexedTest(rawPath string) {
+	testHandle := document.DirHandleFromPath(rawPath)
+
+	err := f.stateStore.JobStore.DequeueJobsForDir(testHandle)
+	if err != nil {
+		f.logger.Printf("failed to dequeue jobs for test: %s", err)
+		return
+	}
+
+	err = f.store.Remove(rawPath)
+	if err != nil {
+		f.logger.Printf("failed to remove test from state: %s", err)
+		return
+	}
+}
+
+func (f *TestsFeature) decodeTest(ctx context.Context, dir document.DirHandle, ignoreState bool) (job.IDs, error) {
+	ids := make(job.IDs, 0)
+	path := dir.Path()
+
+	parseId, err := f.stateStore.JobStore.EnqueueJob(ctx, job.Job{
+		Dir: dir,
+		Func: func(ctx context.Context) error {
+			return jobs.ParseTestConfiguration(ctx, f.fs, f.store, path)
+		},
+		Type:        op.OpTypeParseTestConfiguration.String(),
+		IgnoreState: ignoreState,
+	})
+	if err != nil {
+		return ids, err
+	}
+	ids = append(ids, parseId)
+
+	refTargetsId, err := f.stateStore.JobStore.EnqueueJob(ctx, job.Job{
+		Dir: dir,
+		Func: func(ctx context.Context) error {
+			return jobs.DecodeTestReferenceTargets(ctx, f.store, f.moduleFeature, f.rootFeature, path)
+		},
+		Type:        op.OpTypeDecodeTestReferenceTargets.String(),
+		DependsOn:   job.IDs{parseId},
+		IgnoreState: ignoreState,
+	})
+	if err != nil {
+		return ids, err
+	}
+	ids = append(ids, refTargetsId)
+
+	// Origins are matched against targets of the test files
+	// to tell apart references into the module under test
+	refOriginsId, err := f.stateStore.JobStore.EnqueueJob(ctx, job.Job{
+		Dir: dir,
+		Func: func(ctx context.Context) error {
+			return jobs.DecodeTestReferenceOrigins(ctx, f.store, f.moduleFeature, f.rootFeature, path)
+		},
+		Type:        op.OpTypeDecodeTestReferenceOrigins.String(),
+		DependsOn:   job.IDs{refTargetsId},
+		IgnoreState: ignoreState,
+	})
+	if err != nil {
+		return ids, err
+	}
+	ids = append(ids, refOriginsId)
+
+	parseMockId, err := f.stateStore.JobStore.EnqueueJob(ctx, job.Job{
+		Dir: dir,
+		Func: func(ctx context.Context) error {
+			return jobs.ParseMockConfiguration(ctx, f.fs, f.store, path)
+		},
+		Type:        op.OpTypeParseMockConfiguration.String(),
+		IgnoreState: ignoreState,
+	})
+	if err != nil {
+		return ids, err
+	}
+	ids = append(ids, parseMockId)
+
+	validationOptions, _ := lsctx.ValidationOptions(ctx)
+	if validationOptions.EnableEnhancedValidation {
+		_, err = f.stateStore.JobStore.EnqueueJob(ctx, job.Job{
+			Dir: dir,
+			Func: func(ctx context.Context) error {
+				return jobs.SchemaTestValidation(ctx, f.store, f.moduleFeature, f.rootFeature, f.stateStore.ProviderSchemas, path)
+			},
+			Type:        op.OpTypeSchemaTestValidation.String(),
+			DependsOn:   job.IDs{parseId},
+			IgnoreState: ignoreState,
+		})
+		if err != nil {
+			return ids, err
+		}
+
+		_, err = f.stateStore.JobStore.EnqueueJob(ctx, job.Job{
+			Dir: dir,
+			Func: func(ctx context.Context) error {
+				return jobs.SchemaMockValidation(ctx, f.store, f.moduleFeature, f.rootFeature, f.stateStore.ProviderSchemas, path)
+			},
+			Type:        op.OpTypeSchemaMockValidation.String(),
+			DependsOn:   job.IDs{parseMockId},
+			IgnoreState: ignoreState,
+		})
+		if err != nil {
+			return ids, err
+		}
+	}
+
+	return ids, nil
+}
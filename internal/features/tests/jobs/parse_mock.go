@@ -0,0 +1,94 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package jobs
+
+import (
+	"context"
+	"path/filepath"
+
+	lsctx "github.com/opentofu/tofu-ls/internal/context"
+	"github.com/opentofu/tofu-ls/internal/document"
+	"github.com/opentofu/tofu-ls/internal/features/tests/ast"
+	"github.com/opentofu/tofu-ls/internal/features/tests/parser"
+	"github.com/opentofu/tofu-ls/internal/features/tests/state"
+	"github.com/opentofu/tofu-ls/internal/job"
+	ilsp "github.com/opentofu/tofu-ls/internal/lsp"
+	globalAst "github.com/opentofu/tofu-ls/internal/tofu/ast"
+	op "github.com/opentofu/tofu-ls/internal/tofu/module/operation"
+	"github.com/opentofu/tofu-ls/internal/uri"
+)
+
+// ParseMockConfiguration parses the mock data files,
+// i.e. turns bytes of `*.tfmock.hcl` files into AST ([*hcl.File]).
+func ParseMockConfiguration(ctx context.Context, fs ReadOnlyFS, testStore *state.TestStore, testPath string) error {
+	record, err := testStore.TestRecordByPath(testPath)
+	if err != nil {
+		return err
+	}
+
+	// Avoid parsing if it is already in progress or already known
+	if record.MockDiagnosticsState[globalAst.HCLParsingSource] != op.OpStateUnknown && !job.IgnoreState(ctx) {
+		return job.StateNotChangedErr{Dir: document.DirHandleFromPath(testPath)}
+	}
+
+	var files ast.MockFiles
+	var diags ast.MockDiags
+	rpcContext := lsctx.DocumentContext(ctx)
+	// Only parse the file that's being changed/opened, unless this is 1st-time parsing
+	if record.MockDiagnosticsState[globalAst.HCLParsingSource] == op.OpStateLoaded && rpcContext.IsDidChangeRequest() && ilsp.IsValidMockLanguage(rpcContext.LanguageID) {
+		// the file has already been parsed, so only examine this file and not the whole directory
+		err = testStore.SetMockDiagnosticsState(testPath, globalAst.HCLParsingSource, op.OpStateLoading)
+		if err != nil {
+			return err
+		}
+
+		filePath, err := uri.PathFromURI(rpcContext.URI)
+		if err != nil {
+			return err
+		}
+		fileName := filepath.Base(filePath)
+
+		f, fDiags, err := parser.ParseMockFile(fs, filePath)
+		if err != nil {
+			return err
+		}
+
+		existingFiles := record.ParsedMockFiles.Copy()
+		existingFiles[ast.MockFilename(fileName)] = f
+		files = existingFiles
+
+		existingDiags, ok := record.MockDiagnostics[globalAst.HCLParsingSource]
+		if !ok {
+			existingDiags = make(ast.MockDiags)
+		} else {
+			existingDiags = existingDiags.Copy()
+		}
+		existingDiags[ast.MockFilename(fileName)] = fDiags
+		diags = existingDiags
+	} else {
+		// this is the first time file is opened so parse the whole directory
+		err = testStore.SetMockDiagnosticsState(testPath, globalAst.HCLParsingSource, op.OpStateLoading)
+		if err != nil {
+			return err
+		}
+
+		files, diags, err = parser.ParseMockFiles(fs, testPath)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	sErr := testStore.UpdateParsedMockFiles(testPath, files, err)
+	if sErr != nil {
+		return sErr
+	}
+
+	sErr = testStore.UpdateMockDiagnostics(testPath, globalAst.HCLParsingSource, diags)
+	if sErr != nil {
+		return sErr
+	}
+
+	return err
+}
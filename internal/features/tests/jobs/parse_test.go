@@ -0,0 +1,89 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl-lang/reference"
+	tfmod "github.com/opentofu/opentofu-schema/module"
+	lsctx "github.com/opentofu/tofu-ls/internal/context"
+	"github.com/opentofu/tofu-ls/internal/features/tests/state"
+	"github.com/opentofu/tofu-ls/internal/filesystem"
+	globalState "github.com/opentofu/tofu-ls/internal/state"
+	globalAst "github.com/opentofu/tofu-ls/internal/tofu/ast"
+)
+
+type ModuleReaderMock struct{}
+
+func (r ModuleReaderMock) ModuleReferenceTargets(modPath string) (reference.Targets, error) {
+	return reference.Targets{}, nil
+}
+
+func (r ModuleReaderMock) LocalModuleMeta(modPath string) (*tfmod.Meta, error) {
+	return nil, fmt.Errorf("module not found: %s", modPath)
+}
+
+type RootReaderMock struct {
+	Version *version.Version
+}
+
+func (r RootReaderMock) TofuVersion(modPath string) *version.Version {
+	return r.Version
+}
+
+func TestParseTestConfiguration_precedence(t *testing.T) {
+	ctx := context.Background()
+	gs, err := globalState.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts, err := state.NewTestStore(gs.ChangeStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testData, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	testFs := filesystem.NewFilesystem(gs.DocumentStore)
+
+	testPath := filepath.Join(testData, "precedence")
+
+	err = ts.Add(testPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx = lsctx.WithDocumentContext(ctx, lsctx.Document{})
+	err = ParseTestConfiguration(ctx, testFs, ts, testPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	record, err := ts.TestRecordByPath(testPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fileNames := make([]string, 0)
+	for name := range record.ParsedTestFiles {
+		fileNames = append(fileNames, name.String())
+	}
+	// main.tftest.hcl is shadowed by main.tofutest.hcl
+	expectedFileNames := []string{"main.tofutest.hcl", "other.tftest.hcl"}
+	if diff := cmp.Diff(expectedFileNames, fileNames, cmpSortStrings); diff != "" {
+		t.Fatalf("unexpected parsed files: %s", diff)
+	}
+
+	if count := record.TestDiagnostics[globalAst.HCLParsingSource].Count(); count != 0 {
+		t.Fatalf("expected no parsing diagnostics, %d given", count)
+	}
+}
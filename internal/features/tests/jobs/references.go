@@ -0,0 +1,148 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package jobs
+
+import (
+	"context"
+
+	"github.com/hashicorp/hcl-lang/decoder"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/reference"
+	idecoder "github.com/opentofu/tofu-ls/internal/decoder"
+	"github.com/opentofu/tofu-ls/internal/document"
+	fdecoder "github.com/opentofu/tofu-ls/internal/features/tests/decoder"
+	"github.com/opentofu/tofu-ls/internal/features/tests/state"
+	"github.com/opentofu/tofu-ls/internal/job"
+	ilsp "github.com/opentofu/tofu-ls/internal/lsp"
+	op "github.com/opentofu/tofu-ls/internal/tofu/module/operation"
+)
+
+// DecodeTestReferenceTargets collects reference targets,
+// using previously parsed AST (via [ParseTestConfiguration]).
+//
+// For example it tells us that a run block between certain LOC
+// can be referred to as run.foobar. This is useful e.g. during completion,
+// go-to-definition or go-to-references.
+func DecodeTestReferenceTargets(ctx context.Context, testStore *state.TestStore, moduleFeature fdecoder.ModuleReader, rootFeature fdecoder.RootReader, testPath string) error {
+	record, err := testStore.TestRecordByPath(testPath)
+	if err != nil {
+		return err
+	}
+
+	// Avoid collection if it is already in progress or already done
+	if record.RefTargetsState != op.OpStateUnknown && !job.IgnoreState(ctx) {
+		return job.StateNotChangedErr{Dir: document.DirHandleFromPath(testPath)}
+	}
+
+	err = testStore.SetReferenceTargetsState(testPath, op.OpStateLoading)
+	if err != nil {
+		return err
+	}
+
+	d := decoder.NewDecoder(&fdecoder.PathReader{
+		StateReader:  testStore,
+		ModuleReader: moduleFeature,
+		RootReader:   rootFeature,
+	})
+	d.SetContext(idecoder.DecoderContext(ctx))
+
+	testDecoder, err := d.Path(lang.Path{
+		Path:       testPath,
+		LanguageID: ilsp.OpenTofuTest.String(),
+	})
+	if err != nil {
+		return err
+	}
+
+	targets, rErr := testDecoder.CollectReferenceTargets()
+
+	sErr := testStore.UpdateReferenceTargets(testPath, targets, rErr)
+	if sErr != nil {
+		return sErr
+	}
+
+	return rErr
+}
+
+// DecodeTestReferenceOrigins collects reference origins,
+// using previously parsed AST (via [ParseTestConfiguration]).
+//
+// Origins which cannot be matched with targets declared in the test
+// files themselves (as obtained via [DecodeTestReferenceTargets])
+// are assumed to target the module under test, e.g. a resource
+// referenced from an assert condition.
+func DecodeTestReferenceOrigins(ctx context.Context, testStore *state.TestStore, moduleFeature fdecoder.ModuleReader, rootFeature fdecoder.RootReader, testPath string) error {
+	record, err := testStore.TestRecordByPath(testPath)
+	if err != nil {
+		return err
+	}
+
+	// Avoid collection if it is already in progress or already done
+	if record.RefOriginsState != op.OpStateUnknown && !job.IgnoreState(ctx) {
+		return job.StateNotChangedErr{Dir: document.DirHandleFromPath(testPath)}
+	}
+
+	err = testStore.SetReferenceOriginsState(testPath, op.OpStateLoading)
+	if err != nil {
+		return err
+	}
+
+	d := decoder.NewDecoder(&fdecoder.PathReader{
+		StateReader:  testStore,
+		ModuleReader: moduleFeature,
+		RootReader:   rootFeature,
+	})
+	d.SetContext(idecoder.DecoderContext(ctx))
+
+	testDecoder, err := d.Path(lang.Path{
+		Path:       testPath,
+		LanguageID: ilsp.OpenTofuTest.String(),
+	})
+	if err != nil {
+		return err
+	}
+
+	origins, rErr := testDecoder.CollectReferenceOrigins()
+
+	modulePath := lang.Path{
+		Path:       fdecoder.ModulePathForTests(testPath),
+		LanguageID: ilsp.OpenTofu.String(),
+	}
+	for i, origin := range origins {
+		localOrigin, ok := origin.(reference.LocalOrigin)
+		if !ok {
+			continue
+		}
+		if _, ok := record.RefTargets.Match(localOrigin); ok {
+			continue
+		}
+		if isRunReference(localOrigin.Addr) {
+			// Outputs of run blocks are not known statically,
+			// so these cannot be matched, but they never
+			// point into the module under test.
+			continue
+		}
+		origins[i] = reference.PathOrigin{
+			Range:       localOrigin.Range,
+			TargetAddr:  localOrigin.Addr,
+			TargetPath:  modulePath,
+			Constraints: localOrigin.Constraints,
+		}
+	}
+
+	sErr := testStore.UpdateReferenceOrigins(testPath, origins, rErr)
+	if sErr != nil {
+		return sErr
+	}
+
+	return rErr
+}
+
+func isRunReference(addr lang.Address) bool {
+	if len(addr) == 0 {
+		return false
+	}
+	rootStep, ok := addr[0].(lang.RootStep)
+	return ok && rootStep.Name == "run"
+}
@@ -0,0 +1,100 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package jobs
+
+import (
+	"context"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/reference"
+	lsctx "github.com/opentofu/tofu-ls/internal/context"
+	"github.com/opentofu/tofu-ls/internal/features/tests/state"
+	"github.com/opentofu/tofu-ls/internal/filesystem"
+	ilsp "github.com/opentofu/tofu-ls/internal/lsp"
+	globalState "github.com/opentofu/tofu-ls/internal/state"
+)
+
+var cmpSortStrings = cmp.Transformer("Sort", func(in []string) []string {
+	out := append([]string(nil), in...)
+	sort.Strings(out)
+	return out
+})
+
+func TestDecodeTestReferenceOrigins(t *testing.T) {
+	ctx := context.Background()
+	gs, err := globalState.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts, err := state.NewTestStore(gs.ChangeStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testData, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	testFs := filesystem.NewFilesystem(gs.DocumentStore)
+
+	modPath := filepath.Join(testData, "basic")
+	testPath := filepath.Join(modPath, "tests")
+
+	err = ts.Add(testPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx = lsctx.WithDocumentContext(ctx, lsctx.Document{})
+	err = ParseTestConfiguration(ctx, testFs, ts, testPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = DecodeTestReferenceTargets(ctx, ts, ModuleReaderMock{}, RootReaderMock{}, testPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = DecodeTestReferenceOrigins(ctx, ts, ModuleReaderMock{}, RootReaderMock{}, testPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	record, err := ts.TestRecordByPath(testPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	localOrigins := make([]string, 0)
+	pathOrigins := make([]string, 0)
+	for _, origin := range record.RefOrigins {
+		switch o := origin.(type) {
+		case reference.LocalOrigin:
+			localOrigins = append(localOrigins, o.Addr.String())
+		case reference.PathOrigin:
+			if o.TargetPath != (lang.Path{Path: modPath, LanguageID: ilsp.OpenTofu.String()}) {
+				t.Fatalf("unexpected target path for %s: %#v", o.TargetAddr, o.TargetPath)
+			}
+			pathOrigins = append(pathOrigins, o.TargetAddr.String())
+		}
+	}
+
+	expectedLocalOrigins := []string{
+		"var.bucket_prefix",
+		"run.setup.bucket_prefix",
+	}
+	if diff := cmp.Diff(expectedLocalOrigins, localOrigins, cmpSortStrings); diff != "" {
+		t.Fatalf("unexpected local origins: %s", diff)
+	}
+
+	expectedPathOrigins := []string{
+		"aws_s3_bucket.example.bucket_prefix",
+	}
+	if diff := cmp.Diff(expectedPathOrigins, pathOrigins, cmpSortStrings); diff != "" {
+		t.Fatalf("unexpected path origins: %s", diff)
+	}
+}
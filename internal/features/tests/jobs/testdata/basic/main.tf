@@ -0,0 +1,7 @@
+variable "bucket_prefix" {
+  type = string
+}
+
+resource "aws_s3_bucket" "example" {
+  bucket_prefix = var.bucket_prefix
+}
@@ -0,0 +1,19 @@
+variables {
+  bucket_prefix = "test"
+}
+
+run "setup" {
+  command = plan
+
+  assert {
+    condition     = aws_s3_bucket.example.bucket_prefix == var.bucket_prefix
+    error_message = "Invalid bucket prefix"
+  }
+}
+
+run "verify" {
+  assert {
+    condition     = run.setup.bucket_prefix == "test"
+    error_message = "Unexpected output"
+  }
+}
@@ -0,0 +1,12 @@
+mock_resource "aws_instance" {
+  defaults = {
+    arn     = "arn:aws:ec2:eu-west-1:123456789012:instance/i-1234"
+    unknown = "foo"
+  }
+}
+
+mock_data "aws_ami" {
+  defaults = {
+    id = "ami-1234"
+  }
+}
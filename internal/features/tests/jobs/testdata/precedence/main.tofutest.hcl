@@ -0,0 +1,3 @@
+run "used" {
+  command = plan
+}
@@ -0,0 +1,3 @@
+run "other" {
+  command = plan
+}
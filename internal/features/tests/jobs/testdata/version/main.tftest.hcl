@@ -0,0 +1,5 @@
+mock_provider "aws" {}
+
+run "test" {
+  command = plan
+}
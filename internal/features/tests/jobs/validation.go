@@ -0,0 +1,166 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package jobs
+
+import (
+	"context"
+	"path"
+
+	"github.com/hashicorp/hcl-lang/decoder"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
+	lsctx "github.com/opentofu/tofu-ls/internal/context"
+	idecoder "github.com/opentofu/tofu-ls/internal/decoder"
+	"github.com/opentofu/tofu-ls/internal/document"
+	"github.com/opentofu/tofu-ls/internal/features/tests/ast"
+	fdecoder "github.com/opentofu/tofu-ls/internal/features/tests/decoder"
+	"github.com/opentofu/tofu-ls/internal/features/tests/state"
+	"github.com/opentofu/tofu-ls/internal/job"
+	ilsp "github.com/opentofu/tofu-ls/internal/lsp"
+	globalAst "github.com/opentofu/tofu-ls/internal/tofu/ast"
+	op "github.com/opentofu/tofu-ls/internal/tofu/module/operation"
+)
+
+// SchemaTestValidation does schema-based validation
+// of test files (*.tftest.hcl, *.tofutest.hcl) and produces
+// diagnostics associated with any "invalid" parts of code.
+//
+// It relies on previously parsed AST (via [ParseTestConfiguration]).
+func SchemaTestValidation(ctx context.Context, testStore *state.TestStore, moduleFeature fdecoder.ModuleReader, rootFeature fdecoder.RootReader, schemaReader fdecoder.SchemaReader, testPath string) error {
+	record, err := testStore.TestRecordByPath(testPath)
+	if err != nil {
+		return err
+	}
+
+	// Avoid validation if it is already in progress or already finished
+	if record.TestDiagnosticsState[globalAst.SchemaValidationSource] != op.OpStateUnknown && !job.IgnoreState(ctx) {
+		return job.StateNotChangedErr{Dir: document.DirHandleFromPath(testPath)}
+	}
+
+	err = testStore.SetTestDiagnosticsState(testPath, globalAst.SchemaValidationSource, op.OpStateLoading)
+	if err != nil {
+		return err
+	}
+
+	d := decoder.NewDecoder(&fdecoder.PathReader{
+		StateReader:  testStore,
+		ModuleReader: moduleFeature,
+		RootReader:   rootFeature,
+		SchemaReader: schemaReader,
+	})
+	d.SetContext(idecoder.DecoderContext(ctx))
+
+	testDecoder, err := d.Path(lang.Path{
+		Path:       testPath,
+		LanguageID: ilsp.OpenTofuTest.String(),
+	})
+	if err != nil {
+		return err
+	}
+
+	var rErr error
+	rpcContext := lsctx.DocumentContext(ctx)
+	if rpcContext.Method == "textDocument/didChange" && ilsp.IsValidTestLanguage(rpcContext.LanguageID) {
+		filename := path.Base(rpcContext.URI)
+		// We only revalidate a single file that changed
+		var fileDiags hcl.Diagnostics
+		fileDiags, rErr = testDecoder.ValidateFile(ctx, filename)
+
+		testDiags, ok := record.TestDiagnostics[globalAst.SchemaValidationSource]
+		if !ok {
+			testDiags = make(ast.TestDiags)
+		} else {
+			testDiags = testDiags.Copy()
+		}
+		testDiags[ast.TestFilename(filename)] = fileDiags
+
+		sErr := testStore.UpdateTestDiagnostics(testPath, globalAst.SchemaValidationSource, testDiags)
+		if sErr != nil {
+			return sErr
+		}
+	} else {
+		// We validate the whole directory, e.g. on open
+		var diags lang.DiagnosticsMap
+		diags, rErr = testDecoder.Validate(ctx)
+
+		sErr := testStore.UpdateTestDiagnostics(testPath, globalAst.SchemaValidationSource, ast.TestDiagsFromMap(diags))
+		if sErr != nil {
+			return sErr
+		}
+	}
+
+	return rErr
+}
+
+// SchemaMockValidation does schema-based validation
+// of mock data files (*.tfmock.hcl) and produces
+// diagnostics associated with any "invalid" parts of code.
+//
+// It relies on previously parsed AST (via [ParseMockConfiguration])
+// and validates defaults against the schema of the mocked provider.
+func SchemaMockValidation(ctx context.Context, testStore *state.TestStore, moduleFeature fdecoder.ModuleReader, rootFeature fdecoder.RootReader, schemaReader fdecoder.SchemaReader, testPath string) error {
+	record, err := testStore.TestRecordByPath(testPath)
+	if err != nil {
+		return err
+	}
+
+	// Avoid validation if it is already in progress or already finished
+	if record.MockDiagnosticsState[globalAst.SchemaValidationSource] != op.OpStateUnknown && !job.IgnoreState(ctx) {
+		return job.StateNotChangedErr{Dir: document.DirHandleFromPath(testPath)}
+	}
+
+	err = testStore.SetMockDiagnosticsState(testPath, globalAst.SchemaValidationSource, op.OpStateLoading)
+	if err != nil {
+		return err
+	}
+
+	d := decoder.NewDecoder(&fdecoder.PathReader{
+		StateReader:  testStore,
+		ModuleReader: moduleFeature,
+		RootReader:   rootFeature,
+		SchemaReader: schemaReader,
+	})
+	d.SetContext(idecoder.DecoderContext(ctx))
+
+	mockDecoder, err := d.Path(lang.Path{
+		Path:       testPath,
+		LanguageID: ilsp.OpenTofuMock.String(),
+	})
+	if err != nil {
+		return err
+	}
+
+	var rErr error
+	rpcContext := lsctx.DocumentContext(ctx)
+	if rpcContext.Method == "textDocument/didChange" && ilsp.IsValidMockLanguage(rpcContext.LanguageID) {
+		filename := path.Base(rpcContext.URI)
+		// We only revalidate a single file that changed
+		var fileDiags hcl.Diagnostics
+		fileDiags, rErr = mockDecoder.ValidateFile(ctx, filename)
+
+		mockDiags, ok := record.MockDiagnostics[globalAst.SchemaValidationSource]
+		if !ok {
+			mockDiags = make(ast.MockDiags)
+		} else {
+			mockDiags = mockDiags.Copy()
+		}
+		mockDiags[ast.MockFilename(filename)] = fileDiags
+
+		sErr := testStore.UpdateMockDiagnostics(testPath, globalAst.SchemaValidationSource, mockDiags)
+		if sErr != nil {
+			return sErr
+		}
+	} else {
+		// We validate the whole directory, e.g. on open
+		var diags lang.DiagnosticsMap
+		diags, rErr = mockDecoder.Validate(ctx)
+
+		sErr := testStore.UpdateMockDiagnostics(testPath, globalAst.SchemaValidationSource, ast.MockDiagsFromMap(diags))
+		if sErr != nil {
+			return sErr
+		}
+	}
+
+	return rErr
+}
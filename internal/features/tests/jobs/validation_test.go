@@ -0,0 +1,200 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package jobs
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl-lang/schema"
+	tfschema "github.com/opentofu/opentofu-schema/schema"
+	lsctx "github.com/opentofu/tofu-ls/internal/context"
+	"github.com/opentofu/tofu-ls/internal/features/tests/ast"
+	"github.com/opentofu/tofu-ls/internal/features/tests/state"
+	"github.com/opentofu/tofu-ls/internal/filesystem"
+	globalState "github.com/opentofu/tofu-ls/internal/state"
+	globalAst "github.com/opentofu/tofu-ls/internal/tofu/ast"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestSchemaMockValidation(t *testing.T) {
+	ctx := context.Background()
+	gs, err := globalState.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts, err := state.NewTestStore(gs.ChangeStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = gs.ProviderSchemas.AddPreloadedSchema(globalState.NewDefaultProvider("aws"),
+		version.Must(version.NewVersion("5.0.0")), &tfschema.ProviderSchema{
+			Resources: map[string]*schema.BodySchema{
+				"aws_instance": {
+					Attributes: map[string]*schema.AttributeSchema{
+						"arn": {
+							Constraint: schema.AnyExpression{OfType: cty.String},
+							IsComputed: true,
+						},
+					},
+				},
+			},
+			DataSources: map[string]*schema.BodySchema{
+				"aws_ami": {
+					Attributes: map[string]*schema.AttributeSchema{
+						"id": {
+							Constraint: schema.AnyExpression{OfType: cty.String},
+							IsComputed: true,
+						},
+					},
+				},
+			},
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testData, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	testFs := filesystem.NewFilesystem(gs.DocumentStore)
+
+	testPath := filepath.Join(testData, "mocks")
+
+	err = ts.Add(testPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx = lsctx.WithDocumentContext(ctx, lsctx.Document{})
+	err = ParseMockConfiguration(ctx, testFs, ts, testPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = SchemaMockValidation(ctx, ts, ModuleReaderMock{}, RootReaderMock{}, gs.ProviderSchemas, testPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	record, err := ts.TestRecordByPath(testPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diags := record.MockDiagnostics[globalAst.SchemaValidationSource][ast.MockFilename("aws.tfmock.hcl")]
+	if len(diags) != 1 {
+		t.Fatalf("expected 1 diagnostic, %d given: %#v", len(diags), diags)
+	}
+
+	expectedSummary := "Unexpected attribute"
+	if diags[0].Summary != expectedSummary {
+		t.Fatalf("expected summary %q, given: %q", expectedSummary, diags[0].Summary)
+	}
+	if diags[0].Subject.Start.Line != 4 {
+		t.Fatalf("expected diagnostic on line 4, given: %d", diags[0].Subject.Start.Line)
+	}
+}
+
+func TestSchemaTestValidation_version(t *testing.T) {
+	ctx := context.Background()
+	gs, err := globalState.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts, err := state.NewTestStore(gs.ChangeStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testData, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	testFs := filesystem.NewFilesystem(gs.DocumentStore)
+
+	testPath := filepath.Join(testData, "version")
+
+	err = ts.Add(testPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx = lsctx.WithDocumentContext(ctx, lsctx.Document{})
+	err = ParseTestConfiguration(ctx, testFs, ts, testPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// mock_provider blocks are not supported by the installed version
+	rootReader := RootReaderMock{
+		Version: version.Must(version.NewVersion("1.6.0")),
+	}
+	err = SchemaTestValidation(ctx, ts, ModuleReaderMock{}, rootReader, gs.ProviderSchemas, testPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	record, err := ts.TestRecordByPath(testPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diags := record.TestDiagnostics[globalAst.SchemaValidationSource][ast.TestFilename("main.tftest.hcl")]
+	if len(diags) != 1 {
+		t.Fatalf("expected 1 diagnostic, %d given: %#v", len(diags), diags)
+	}
+
+	expectedSummary := `Unexpected block`
+	if diags[0].Summary != expectedSummary {
+		t.Fatalf("expected summary %q, given: %q", expectedSummary, diags[0].Summary)
+	}
+	if diags[0].Subject.Start.Line != 1 {
+		t.Fatalf("expected diagnostic on line 1, given: %d", diags[0].Subject.Start.Line)
+	}
+}
+
+func TestSchemaMockValidation_unsupportedVersion(t *testing.T) {
+	ctx := context.Background()
+	gs, err := globalState.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts, err := state.NewTestStore(gs.ChangeStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testData, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	testFs := filesystem.NewFilesystem(gs.DocumentStore)
+
+	testPath := filepath.Join(testData, "mocks")
+
+	err = ts.Add(testPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx = lsctx.WithDocumentContext(ctx, lsctx.Document{})
+	err = ParseMockConfiguration(ctx, testFs, ts, testPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootReader := RootReaderMock{
+		Version: version.Must(version.NewVersion("1.6.0")),
+	}
+	err = SchemaMockValidation(ctx, ts, ModuleReaderMock{}, rootReader, gs.ProviderSchemas, testPath)
+	if !errors.As(err, &tfschema.NoCompatibleSchemaErr{}) {
+		t.Fatalf("expected no compatible schema error, given: %#v", err)
+	}
+}
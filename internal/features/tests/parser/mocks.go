@@ -0,0 +1,68 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package parser
+
+import (
+	"path/filepath"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/opentofu/tofu-ls/internal/features/tests/ast"
+	"github.com/opentofu/tofu-ls/internal/tofu/parser"
+)
+
+func ParseMockFiles(fs parser.FS, testPath string) (ast.MockFiles, ast.MockDiags, error) {
+	files := make(ast.MockFiles, 0)
+	diags := make(ast.MockDiags, 0)
+
+	dirEntries, err := fs.ReadDir(testPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, entry := range dirEntries {
+		if entry.IsDir() {
+			// We only care about files
+			continue
+		}
+
+		name := entry.Name()
+		if !ast.IsMockFilename(name) {
+			continue
+		}
+
+		fullPath := filepath.Join(testPath, name)
+
+		src, err := fs.ReadFile(fullPath)
+		if err != nil {
+			// If a file isn't accessible, continue with reading the
+			// remaining mock files
+			continue
+		}
+
+		filename := ast.MockFilename(name)
+
+		f, pDiags := parser.ParseFile(src, filename)
+
+		diags[filename] = pDiags
+		if f != nil {
+			files[filename] = f
+		}
+	}
+
+	return files, diags, nil
+}
+
+func ParseMockFile(fs parser.FS, filePath string) (*hcl.File, hcl.Diagnostics, error) {
+	src, err := fs.ReadFile(filePath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	name := filepath.Base(filePath)
+	filename := ast.MockFilename(name)
+
+	f, pDiags := parser.ParseFile(src, filename)
+
+	return f, pDiags, nil
+}
@@ -0,0 +1,80 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package parser
+
+import (
+	"path/filepath"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/opentofu/tofu-ls/internal/features/tests/ast"
+	"github.com/opentofu/tofu-ls/internal/tofu/parser"
+)
+
+func ParseTestFiles(fs parser.FS, testPath string) (ast.TestFiles, ast.TestDiags, error) {
+	files := make(ast.TestFiles, 0)
+	diags := make(ast.TestDiags, 0)
+
+	dirEntries, err := fs.ReadDir(testPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	names := make(map[string]struct{}, len(dirEntries))
+	for _, entry := range dirEntries {
+		names[entry.Name()] = struct{}{}
+	}
+
+	for _, entry := range dirEntries {
+		if entry.IsDir() {
+			// We only care about files
+			continue
+		}
+
+		name := entry.Name()
+		if !ast.IsTestFilename(name) {
+			continue
+		}
+
+		if tofuName, ok := ast.TofuTestFilenameFor(name); ok {
+			if _, exists := names[tofuName]; exists {
+				// OpenTofu only loads the .tofutest.* file in this case
+				continue
+			}
+		}
+
+		fullPath := filepath.Join(testPath, name)
+
+		src, err := fs.ReadFile(fullPath)
+		if err != nil {
+			// If a file isn't accessible, continue with reading the
+			// remaining test files
+			continue
+		}
+
+		filename := ast.TestFilename(name)
+
+		f, pDiags := parser.ParseFile(src, filename)
+
+		diags[filename] = pDiags
+		if f != nil {
+			files[filename] = f
+		}
+	}
+
+	return files, diags, nil
+}
+
+func ParseTestFile(fs parser.FS, filePath string) (*hcl.File, hcl.Diagnostics, error) {
+	src, err := fs.ReadFile(filePath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	name := filepath.Base(filePath)
+	filename := ast.TestFilename(name)
+
+	f, pDiags := parser.ParseFile(src, filename)
+
+	return f, pDiags, nil
+}
@@ -0,0 +1,127 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package state
+
+import (
+	"github.com/hashicorp/hcl-lang/reference"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/opentofu/tofu-ls/internal/features/tests/ast"
+	globalAst "github.com/opentofu/tofu-ls/internal/tofu/ast"
+	op "github.com/opentofu/tofu-ls/internal/tofu/module/operation"
+)
+
+// TestRecord contains all information about test files
+// we have for a certain path
+type TestRecord struct {
+	path string
+
+	RefTargets      reference.Targets
+	RefTargetsErr   error
+	RefTargetsState op.OpState
+
+	RefOrigins      reference.Origins
+	RefOriginsErr   error
+	RefOriginsState op.OpState
+
+	ParsedTestFiles ast.TestFiles
+	TestParsingErr  error
+
+	TestDiagnostics      ast.SourceTestDiags
+	TestDiagnosticsState globalAst.DiagnosticSourceState
+
+	ParsedMockFiles ast.MockFiles
+	MockParsingErr  error
+
+	MockDiagnostics      ast.SourceMockDiags
+	MockDiagnosticsState globalAst.DiagnosticSourceState
+}
+
+func (t *TestRecord) Copy() *TestRecord {
+	if t == nil {
+		return nil
+	}
+	newRecord := &TestRecord{
+		path: t.path,
+
+		RefTargets:      t.RefTargets.Copy(),
+		RefTargetsErr:   t.RefTargetsErr,
+		RefTargetsState: t.RefTargetsState,
+
+		RefOrigins:      t.RefOrigins.Copy(),
+		RefOriginsErr:   t.RefOriginsErr,
+		RefOriginsState: t.RefOriginsState,
+
+		TestParsingErr: t.TestParsingErr,
+
+		TestDiagnosticsState: t.TestDiagnosticsState.Copy(),
+
+		MockParsingErr: t.MockParsingErr,
+
+		MockDiagnosticsState: t.MockDiagnosticsState.Copy(),
+	}
+
+	if t.ParsedTestFiles != nil {
+		newRecord.ParsedTestFiles = make(ast.TestFiles, len(t.ParsedTestFiles))
+		for name, f := range t.ParsedTestFiles {
+			// hcl.File is practically immutable once it comes out of parser
+			newRecord.ParsedTestFiles[name] = f
+		}
+	}
+
+	if t.TestDiagnostics != nil {
+		newRecord.TestDiagnostics = make(ast.SourceTestDiags, len(t.TestDiagnostics))
+
+		for source, testDiags := range t.TestDiagnostics {
+			newRecord.TestDiagnostics[source] = make(ast.TestDiags, len(testDiags))
+
+			for name, diags := range testDiags {
+				newRecord.TestDiagnostics[source][name] = make(hcl.Diagnostics, len(diags))
+				copy(newRecord.TestDiagnostics[source][name], diags)
+			}
+		}
+	}
+
+	if t.ParsedMockFiles != nil {
+		newRecord.ParsedMockFiles = make(ast.MockFiles, len(t.ParsedMockFiles))
+		for name, f := range t.ParsedMockFiles {
+			// hcl.File is practically immutable once it comes out of parser
+			newRecord.ParsedMockFiles[name] = f
+		}
+	}
+
+	if t.MockDiagnostics != nil {
+		newRecord.MockDiagnostics = make(ast.SourceMockDiags, len(t.MockDiagnostics))
+
+		for source, mockDiags := range t.MockDiagnostics {
+			newRecord.MockDiagnostics[source] = make(ast.MockDiags, len(mockDiags))
+
+			for name, diags := range mockDiags {
+				newRecord.MockDiagnostics[source][name] = make(hcl.Diagnostics, len(diags))
+				copy(newRecord.MockDiagnostics[source][name], diags)
+			}
+		}
+	}
+
+	return newRecord
+}
+
+func (t *TestRecord) Path() string {
+	return t.path
+}
+
+func newTestRecord(path string) *TestRecord {
+	return &TestRecord{
+		path: path,
+		TestDiagnosticsState: globalAst.DiagnosticSourceState{
+			globalAst.HCLParsingSource:          op.OpStateUnknown,
+			globalAst.SchemaValidationSource:    op.OpStateUnknown,
+			globalAst.ReferenceValidationSource: op.OpStateUnknown,
+			globalAst.TofuValidateSource:        op.OpStateUnknown,
+		},
+		MockDiagnosticsState: globalAst.DiagnosticSourceState{
+			globalAst.HCLParsingSource:       op.OpStateUnknown,
+			globalAst.SchemaValidationSource: op.OpStateUnknown,
+		},
+	}
+}
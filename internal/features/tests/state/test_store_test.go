@@ -0,0 +1,182 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package state
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/opentofu/tofu-ls/internal/features/tests/ast"
+	globalState "github.com/opentofu/tofu-ls/internal/state"
+	globalAst "github.com/opentofu/tofu-ls/internal/tofu/ast"
+	"github.com/opentofu/tofu-ls/internal/tofu/module/operation"
+	"github.com/zclconf/go-cty-debug/ctydebug"
+)
+
+var cmpOpts = cmp.Options{
+	cmp.AllowUnexported(TestRecord{}),
+	cmp.AllowUnexported(hclsyntax.Body{}),
+	cmp.Comparer(func(x, y hcl.File) bool {
+		return (x.Body == y.Body &&
+			cmp.Equal(x.Bytes, y.Bytes))
+	}),
+	ctydebug.CmpOptions,
+}
+
+func TestTestStore_Add_duplicate(t *testing.T) {
+	globalStore, err := globalState.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := NewTestStore(globalStore.ChangeStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testPath := t.TempDir()
+
+	err = s.Add(testPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = s.Add(testPath)
+	if err == nil {
+		t.Fatal("expected error for duplicate entry")
+	}
+	existsError := &globalState.AlreadyExistsError{}
+	if !errors.As(err, &existsError) {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
+
+func TestTestStore_List(t *testing.T) {
+	globalStore, err := globalState.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := NewTestStore(globalStore.ChangeStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmpDir := t.TempDir()
+
+	testPaths := []string{
+		filepath.Join(tmpDir, "alpha"),
+		filepath.Join(tmpDir, "beta"),
+	}
+	for _, testPath := range testPaths {
+		err := s.Add(testPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	records, err := s.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedRecords := []*TestRecord{
+		newTestRecord(filepath.Join(tmpDir, "alpha")),
+		newTestRecord(filepath.Join(tmpDir, "beta")),
+	}
+
+	if diff := cmp.Diff(expectedRecords, records, cmpOpts); diff != "" {
+		t.Fatalf("unexpected records: %s", diff)
+	}
+}
+
+func TestTestStore_UpdateParsedTestFiles(t *testing.T) {
+	globalStore, err := globalState.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := NewTestStore(globalStore.ChangeStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmpDir := t.TempDir()
+	err = s.Add(tmpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := hclparse.NewParser()
+	testFile, diags := p.ParseHCL([]byte(`
+run "setup" {
+  command = plan
+}
+`), "main.tftest.hcl")
+	if len(diags) > 0 {
+		t.Fatal(diags)
+	}
+
+	err = s.UpdateParsedTestFiles(tmpDir, ast.TestFilesFromMap(map[string]*hcl.File{
+		"main.tftest.hcl": testFile,
+	}), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	record, err := s.TestRecordByPath(tmpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedParsedTestFiles := ast.TestFilesFromMap(map[string]*hcl.File{
+		"main.tftest.hcl": testFile,
+	})
+	if diff := cmp.Diff(expectedParsedTestFiles, record.ParsedTestFiles, cmpOpts); diff != "" {
+		t.Fatalf("unexpected parsed files: %s", diff)
+	}
+}
+
+func TestTestStore_UpdateTestDiagnostics(t *testing.T) {
+	globalStore, err := globalState.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := NewTestStore(globalStore.ChangeStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmpDir := t.TempDir()
+	err = s.Add(tmpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := hclparse.NewParser()
+	_, diags := p.ParseHCL([]byte(`
+run "setup" {
+`), "main.tftest.hcl")
+
+	err = s.UpdateTestDiagnostics(tmpDir, globalAst.HCLParsingSource, ast.TestDiagsFromMap(map[string]hcl.Diagnostics{
+		"main.tftest.hcl": diags,
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	record, err := s.TestRecordByPath(tmpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if record.TestDiagnostics[globalAst.HCLParsingSource].Count() != 1 {
+		t.Fatalf("expected 1 diagnostic, given: %#v", record.TestDiagnostics)
+	}
+	if record.TestDiagnosticsState[globalAst.HCLParsingSource] != operation.OpStateLoaded {
+		t.Fatalf("unexpected diagnostics state: %s", record.TestDiagnosticsState[globalAst.HCLParsingSource])
+	}
+}
@@ -6,6 +6,7 @@
 package ast
 
 import (
+	"sort"
 	"strings"
 
 	"github.com/hashicorp/hcl/v2"
@@ -63,6 +64,62 @@ func (vf VarsFiles) Copy() VarsFiles {
 	return m
 }
 
+// VarsValue represents a value assigned to an input variable
+// in a variable definitions file
+type VarsValue struct {
+	Filename VarsFilename
+	Expr     hcl.Expression
+	// Source is the expression as written in the file
+	Source string
+}
+
+// AutoloadedValues returns values assigned to input variables in autoloaded
+// files, in the same order of precedence as when OpenTofu loads them,
+// i.e. terraform.tfvars(.json) first, followed by *.auto.tfvars(.json)
+// in lexical order, where values from later files override earlier ones.
+func (vf VarsFiles) AutoloadedValues() map[string]VarsValue {
+	names := make([]VarsFilename, 0, len(vf))
+	for name := range vf {
+		if name.IsAutoloaded() {
+			names = append(names, name)
+		}
+	}
+	priority := func(name VarsFilename) int {
+		switch name {
+		case "terraform.tfvars":
+			return 0
+		case "terraform.tfvars.json":
+			return 1
+		}
+		return 2
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if priority(names[i]) != priority(names[j]) {
+			return priority(names[i]) < priority(names[j])
+		}
+		return names[i] < names[j]
+	})
+
+	values := make(map[string]VarsValue)
+	for _, name := range names {
+		f := vf[name]
+		attrs, _ := f.Body.JustAttributes()
+		for attrName, attr := range attrs {
+			rng := attr.Expr.Range()
+			source := ""
+			if rng.End.Byte <= len(f.Bytes) {
+				source = string(rng.SliceBytes(f.Bytes))
+			}
+			values[attrName] = VarsValue{
+				Filename: name,
+				Expr:     attr.Expr,
+				Source:   source,
+			}
+		}
+	}
+	return values
+}
+
 type VarsDiags map[VarsFilename]hcl.Diagnostics
 
 func VarsDiagsFromMap(m map[string]hcl.Diagnostics) VarsDiags {
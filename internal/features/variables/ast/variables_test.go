@@ -6,10 +6,14 @@
 package ast
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/json"
 	"github.com/zclconf/go-cty-debug/ctydebug"
 )
 
@@ -42,3 +46,43 @@ func TestVarsDiags_autoloadedOnly(t *testing.T) {
 		t.Fatalf("unexpected diagnostics: %s", diff)
 	}
 }
+
+func TestVarsFiles_autoloadedValues(t *testing.T) {
+	files := map[string]string{
+		"terraform.tfvars":         "instance_type = \"t3.micro\"\nregion = \"eu-west-1\"\nzones = 1\n",
+		"terraform.tfvars.json":    `{"zones": 2}`,
+		"b.auto.tfvars":            "region = \"us-east-1\"\n",
+		"a.auto.tfvars":            "region = \"eu-central-1\"\ntags = { env = \"dev\" }\n",
+		"production.tfvars":        "instance_type = \"m5.large\"\n",
+		"production.auto.tfvars.x": "instance_type = \"m5.xlarge\"\n",
+	}
+	vf := make(VarsFiles)
+	for name, src := range files {
+		var f *hcl.File
+		var diags hcl.Diagnostics
+		if strings.HasSuffix(name, ".json") {
+			f, diags = json.Parse([]byte(src), name)
+		} else {
+			f, diags = hclsyntax.ParseConfig([]byte(src), name, hcl.InitialPos)
+		}
+		if diags.HasErrors() {
+			t.Fatal(diags)
+		}
+		vf[VarsFilename(name)] = f
+	}
+
+	values := make(map[string]string)
+	for name, value := range vf.AutoloadedValues() {
+		values[name] = fmt.Sprintf("%s: %s", value.Filename, value.Source)
+	}
+	expectedValues := map[string]string{
+		"instance_type": `terraform.tfvars: "t3.micro"`,
+		"region":        `b.auto.tfvars: "us-east-1"`,
+		"zones":         `terraform.tfvars.json: 2`,
+		"tags":          `a.auto.tfvars: { env = "dev" }`,
+	}
+
+	if diff := cmp.Diff(expectedValues, values); diff != "" {
+		t.Fatalf("unexpected values: %s", diff)
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/hashicorp/hcl-lang/decoder"
 	"github.com/hashicorp/hcl-lang/lang"
 	"github.com/opentofu/tofu-ls/internal/eventbus"
+	"github.com/opentofu/tofu-ls/internal/features/variables/ast"
 	fdecoder "github.com/opentofu/tofu-ls/internal/features/variables/decoder"
 	"github.com/opentofu/tofu-ls/internal/features/variables/jobs"
 	"github.com/opentofu/tofu-ls/internal/features/variables/state"
@@ -122,6 +123,17 @@ func (f *VariablesFeature) Paths(ctx context.Context) []lang.Path {
 	return pathReader.Paths(ctx)
 }
 
+// AutoloadedValues returns values assigned to input variables
+// in autoloaded variable definitions files at the given path.
+func (f *VariablesFeature) AutoloadedValues(path string) (map[string]ast.VarsValue, error) {
+	record, err := f.store.VariableRecordByPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return record.ParsedVarsFiles.AutoloadedValues(), nil
+}
+
 func (f *VariablesFeature) Diagnostics(path string) diagnostics.Diagnostics {
 	diags := diagnostics.NewDiagnostics()
 
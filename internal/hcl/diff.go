@@ -160,3 +160,30 @@ func diffLines(filename string, beforeLines, afterLines source.Lines) document.C
 
 	return changes
 }
+
+// LineMapping maps lines of the before byte sequence to lines of the after
+// sequence, for lines which were either kept intact, or replaced line by line,
+// such as when a single line is being edited. Lines which were inserted
+// or deleted, or replaced by a different number of lines, are not mapped.
+//
+// Line numbers start at 1, as in [hcl.Pos].
+func LineMapping(filename string, before, after []byte) map[int]int {
+	m := difflib.NewMatcher(
+		source.StringLines(source.MakeSourceLines(filename, before)),
+		source.StringLines(source.MakeSourceLines(filename, after)))
+
+	mapping := make(map[int]int)
+	for _, c := range m.GetOpCodes() {
+		if c.Tag != OpEqual && c.Tag != OpReplace {
+			continue
+		}
+		if c.I2-c.I1 != c.J2-c.J1 {
+			continue
+		}
+		for i := 0; i < c.I2-c.I1; i++ {
+			mapping[c.I1+i+1] = c.J1 + i + 1
+		}
+	}
+
+	return mapping
+}
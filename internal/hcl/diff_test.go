@@ -258,3 +258,51 @@ ccc`,
 		})
 	}
 }
+
+func TestLineMapping(t *testing.T) {
+	testCases := []struct {
+		name                string
+		beforeCfg, afterCfg string
+		expectedMapping     map[int]int
+	}{
+		{
+			"no-op",
+			"aaa\nbbb\nccc\n",
+			"aaa\nbbb\nccc\n",
+			map[int]int{1: 1, 2: 2, 3: 3, 4: 4},
+		},
+		{
+			"line edited",
+			"aaa\nbbb\nccc\n",
+			"aaa\nxxx\nccc\n",
+			map[int]int{1: 1, 2: 2, 3: 3, 4: 4},
+		},
+		{
+			"lines inserted",
+			"aaa\nbbb\nccc\n",
+			"xxx\nyyy\naaa\nbbb\nzzz\n",
+			map[int]int{1: 3, 2: 4, 3: 5, 4: 6},
+		},
+		{
+			"line deleted",
+			"aaa\nbbb\nccc\n",
+			"aaa\nccc\n",
+			map[int]int{1: 1, 3: 2, 4: 3},
+		},
+		{
+			"line replaced by more lines",
+			"aaa\nbbb\nccc\n",
+			"aaa\nxxx\nyyy\nccc\n",
+			map[int]int{1: 1, 3: 4, 4: 5},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%d-%s", i, tc.name), func(t *testing.T) {
+			mapping := LineMapping("test.tf", []byte(tc.beforeCfg), []byte(tc.afterCfg))
+			if diff := cmp.Diff(tc.expectedMapping, mapping); diff != "" {
+				t.Fatalf("line mapping mismatch: %s", diff)
+			}
+		})
+	}
+}
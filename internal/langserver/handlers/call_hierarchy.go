@@ -0,0 +1,293 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	tfmod "github.com/opentofu/opentofu-schema/module"
+	ilsp "github.com/opentofu/tofu-ls/internal/lsp"
+	"github.com/opentofu/tofu-ls/internal/pathcmp"
+	lsp "github.com/opentofu/tofu-ls/internal/protocol"
+	"github.com/opentofu/tofu-ls/internal/uri"
+)
+
+// callHierarchyData identifies the item between the prepare request
+// and subsequent requests for incoming or outgoing calls.
+//
+// Items either represent a module (directory) or a module call,
+// i.e. a module block declared in the module at Path.
+type callHierarchyData struct {
+	Path string `json:"path"`
+	Call string `json:"call,omitempty"`
+}
+
+func (svc *service) PrepareCallHierarchy(ctx context.Context, params lsp.CallHierarchyPrepareParams) ([]lsp.CallHierarchyItem, error) {
+	items := make([]lsp.CallHierarchyItem, 0)
+
+	dh := ilsp.HandleFromDocumentURI(params.TextDocument.URI)
+	doc, err := svc.stateStore.DocumentStore.GetDocument(dh)
+	if err != nil {
+		return items, err
+	}
+	if !ilsp.IsValidConfigLanguage(doc.LanguageID) {
+		return items, nil
+	}
+
+	jobIds, err := svc.stateStore.JobStore.ListIncompleteJobsForDir(dh.Dir)
+	if err != nil {
+		return items, err
+	}
+	svc.stateStore.JobStore.WaitForJobs(ctx, jobIds...)
+
+	pos, err := ilsp.HCLPositionFromLspPosition(params.Position, doc)
+	if err != nil {
+		return items, err
+	}
+
+	modPath := doc.Dir.Path()
+	files := svc.moduleFiles(modPath)
+	if f, ok := files[doc.Filename]; ok {
+		if block, ok := labeledBlockAtPos(f, "module", pos); ok {
+			declared, err := svc.features.Modules.DeclaredModuleCalls(modPath)
+			if err != nil {
+				return items, err
+			}
+			if mc, ok := declared[block.Labels[0]]; ok {
+				return append(items, svc.moduleCallItem(modPath, mc)), nil
+			}
+		}
+	}
+
+	return append(items, svc.moduleItem(modPath)), nil
+}
+
+// CallHierarchyIncomingCalls returns the module calls which call the module
+// of the given item. For a module call, these are the calls of the module
+// in which it is declared.
+func (svc *service) CallHierarchyIncomingCalls(ctx context.Context, params lsp.CallHierarchyIncomingCallsParams) ([]lsp.CallHierarchyIncomingCall, error) {
+	calls := make([]lsp.CallHierarchyIncomingCall, 0)
+
+	data, err := parseCallHierarchyData(params.Item.Data)
+	if err != nil {
+		return calls, err
+	}
+
+	for _, path := range svc.features.Modules.Paths(ctx) {
+		declared, err := svc.features.Modules.DeclaredModuleCalls(path.Path)
+		if err != nil {
+			continue
+		}
+
+		for _, mc := range sortedModuleCalls(declared) {
+			mcPath, ok := svc.features.Modules.ModuleCallPath(path.Path, mc)
+			if !ok || !pathcmp.PathEquals(mcPath, data.Path) {
+				continue
+			}
+
+			from := svc.moduleCallItem(path.Path, mc)
+			calls = append(calls, lsp.CallHierarchyIncomingCall{
+				From:       from,
+				FromRanges: []lsp.Range{from.SelectionRange},
+			})
+		}
+	}
+
+	sort.SliceStable(calls, func(i, j int) bool {
+		return calls[i].From.URI < calls[j].From.URI
+	})
+
+	return calls, nil
+}
+
+// CallHierarchyOutgoingCalls returns the module calls declared in the module
+// of the given item. For a module call, this is the module being called,
+// which can be a local module or a module installed from elsewhere.
+func (svc *service) CallHierarchyOutgoingCalls(ctx context.Context, params lsp.CallHierarchyOutgoingCallsParams) ([]lsp.CallHierarchyOutgoingCall, error) {
+	calls := make([]lsp.CallHierarchyOutgoingCall, 0)
+
+	data, err := parseCallHierarchyData(params.Item.Data)
+	if err != nil {
+		return calls, err
+	}
+
+	modPath := data.Path
+	if data.Call != "" {
+		declared, err := svc.features.Modules.DeclaredModuleCalls(data.Path)
+		if err != nil {
+			return calls, err
+		}
+		mc, ok := declared[data.Call]
+		if !ok {
+			return calls, nil
+		}
+		modPath, ok = svc.features.Modules.ModuleCallPath(data.Path, mc)
+		if !ok {
+			// The module is not installed yet
+			return calls, nil
+		}
+	}
+
+	declared, err := svc.features.Modules.DeclaredModuleCalls(modPath)
+	if err != nil {
+		// The called module may not have been indexed (yet)
+		return calls, nil
+	}
+
+	for _, mc := range sortedModuleCalls(declared) {
+		to := svc.moduleCallItem(modPath, mc)
+		// The module block is the closest we have to a call site,
+		// since a module isn't called from any particular file.
+		calls = append(calls, lsp.CallHierarchyOutgoingCall{
+			To:         to,
+			FromRanges: []lsp.Range{to.SelectionRange},
+		})
+	}
+
+	return calls, nil
+}
+
+func parseCallHierarchyData(rawData interface{}) (callHierarchyData, error) {
+	data := callHierarchyData{}
+
+	b, err := json.Marshal(rawData)
+	if err != nil {
+		return data, err
+	}
+	err = json.Unmarshal(b, &data)
+	if err != nil {
+		return data, err
+	}
+	if data.Path == "" {
+		return data, fmt.Errorf("%w: call hierarchy item is missing module path", jrpc2.InvalidParams.Err())
+	}
+
+	return data, nil
+}
+
+// moduleItem represents the module at modPath, pointing
+// to the beginning of its main file where possible.
+func (svc *service) moduleItem(modPath string) lsp.CallHierarchyItem {
+	itemURI := uri.FromPath(modPath)
+	if filename, ok := mainModuleFile(svc.moduleFiles(modPath)); ok {
+		itemURI = uri.FromPath(filepath.Join(modPath, filename))
+	}
+
+	return lsp.CallHierarchyItem{
+		Name:   filepath.Base(modPath),
+		Kind:   lsp.Module,
+		Detail: modPath,
+		URI:    lsp.DocumentURI(itemURI),
+		Data: callHierarchyData{
+			Path: modPath,
+		},
+	}
+}
+
+// moduleCallItem represents the module block of the given call
+// declared in the module at modPath.
+func (svc *service) moduleCallItem(modPath string, mc tfmod.DeclaredModuleCall) lsp.CallHierarchyItem {
+	item := lsp.CallHierarchyItem{
+		Name:   fmt.Sprintf("module.%s", mc.LocalName),
+		Kind:   lsp.Module,
+		Detail: mc.RawSourceAddr,
+		Data: callHierarchyData{
+			Path: modPath,
+			Call: mc.LocalName,
+		},
+	}
+
+	var rng, selectionRng hcl.Range
+	if block, ok := moduleBlock(svc.moduleFiles(modPath), mc.LocalName); ok {
+		rng = block.Range()
+		selectionRng = block.LabelRanges[0]
+	} else if mc.RangePtr != nil {
+		// JSON configuration only has the range of the block body
+		rng = *mc.RangePtr
+		selectionRng = *mc.RangePtr
+	}
+
+	item.URI = lsp.DocumentURI(uri.FromPath(filepath.Join(modPath, rng.Filename)))
+	item.Range = ilsp.HCLRangeToLSP(rng)
+	item.SelectionRange = ilsp.HCLRangeToLSP(selectionRng)
+
+	return item
+}
+
+func (svc *service) moduleFiles(modPath string) map[string]*hcl.File {
+	pathCtx, err := svc.features.Modules.PathContext(lang.Path{
+		Path:       modPath,
+		LanguageID: ilsp.OpenTofu.String(),
+	})
+	if err != nil {
+		return map[string]*hcl.File{}
+	}
+	return pathCtx.Files
+}
+
+func mainModuleFile(files map[string]*hcl.File) (string, bool) {
+	if _, ok := files["main.tf"]; ok {
+		return "main.tf", true
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return "", false
+	}
+	sort.Strings(names)
+
+	return names[0], true
+}
+
+func moduleBlock(files map[string]*hcl.File, name string) (*hclsyntax.Block, bool) {
+	for _, f := range files {
+		body, ok := f.Body.(*hclsyntax.Body)
+		if !ok {
+			continue
+		}
+		for _, block := range body.Blocks {
+			if block.Type == "module" && len(block.Labels) == 1 && block.Labels[0] == name {
+				return block, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// labeledBlockAtPos returns the top-level block of the given type
+// with a single label, which contains pos
+func labeledBlockAtPos(f *hcl.File, blockType string, pos hcl.Pos) (*hclsyntax.Block, bool) {
+	body, ok := f.Body.(*hclsyntax.Body)
+	if !ok {
+		return nil, false
+	}
+	for _, block := range body.Blocks {
+		if block.Type == blockType && len(block.Labels) == 1 && block.Range().ContainsPos(pos) {
+			return block, true
+		}
+	}
+	return nil, false
+}
+
+func sortedModuleCalls(declared map[string]tfmod.DeclaredModuleCall) []tfmod.DeclaredModuleCall {
+	calls := make([]tfmod.DeclaredModuleCall, 0, len(declared))
+	for _, mc := range declared {
+		calls = append(calls, mc)
+	}
+	sort.Slice(calls, func(i, j int) bool {
+		return calls[i].LocalName < calls[j].LocalName
+	})
+	return calls
+}
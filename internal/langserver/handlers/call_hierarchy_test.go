@@ -0,0 +1,380 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package handlers
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/opentofu/tofu-ls/internal/document"
+	"github.com/opentofu/tofu-ls/internal/langserver"
+	"github.com/opentofu/tofu-ls/internal/state"
+	"github.com/opentofu/tofu-ls/internal/tofu/exec"
+	"github.com/opentofu/tofu-ls/internal/walker"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestLangServer_callHierarchy_localModules(t *testing.T) {
+	tmpDir := TempDir(t)
+	appDir := document.DirHandleFromPath(filepath.Join(tmpDir.Path(), "modules", "app"))
+	dbDir := document.DirHandleFromPath(filepath.Join(tmpDir.Path(), "modules", "db"))
+
+	rootCfg := `module "app" {
+  source = "./modules/app"
+}
+`
+	appCfg := `variable "name" {}
+
+module "db" {
+  source = "../db"
+}
+`
+	writeTestFile(t, filepath.Join(tmpDir.Path(), "main.tf"), rootCfg)
+	writeTestFile(t, filepath.Join(appDir.Path(), "main.tf"), appCfg)
+	writeTestFile(t, filepath.Join(dbDir.Path(), "main.tf"), `variable "name" {}`)
+
+	ss, err := state.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wc := walker.NewWalkerCollector()
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TofuCalls: &exec.TofuMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				tmpDir.Path(): validTfMockCalls(),
+				appDir.Path(): validTfMockCalls(),
+				dbDir.Path():  validTfMockCalls(),
+			},
+		},
+		StateStore:      ss,
+		WalkerCollector: wc,
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+	    "capabilities": {},
+	    "rootUri": %q,
+	    "processId": 12345
+	}`, tmpDir.URI)})
+	waitForWalkerPath(t, ss, wc, tmpDir)
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "opentofu",
+			"text": %q,
+			"uri": "%s/main.tf"
+		}
+	}`, rootCfg, tmpDir.URI)})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "opentofu",
+			"text": %q,
+			"uri": "%s/main.tf"
+		}
+	}`, appCfg, appDir.URI)})
+	waitForAllJobs(t, ss)
+
+	appCallItem := fmt.Sprintf(`{
+		"name": "module.app",
+		"kind": 2,
+		"detail": "./modules/app",
+		"uri": "%s/main.tf",
+		"range": {
+			"start": { "line": 0, "character": 0 },
+			"end": { "line": 2, "character": 1 }
+		},
+		"selectionRange": {
+			"start": { "line": 0, "character": 7 },
+			"end": { "line": 0, "character": 12 }
+		},
+		"data": { "path": %q, "call": "app" }
+	}`, tmpDir.URI, tmpDir.Path())
+	dbCallItem := fmt.Sprintf(`{
+		"name": "module.db",
+		"kind": 2,
+		"detail": "../db",
+		"uri": "%s/main.tf",
+		"range": {
+			"start": { "line": 2, "character": 0 },
+			"end": { "line": 4, "character": 1 }
+		},
+		"selectionRange": {
+			"start": { "line": 2, "character": 7 },
+			"end": { "line": 2, "character": 11 }
+		},
+		"data": { "path": %q, "call": "db" }
+	}`, appDir.URI, appDir.Path())
+
+	// module block in the root module
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/prepareCallHierarchy",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": { "uri": "%s/main.tf" },
+			"position": { "line": 1, "character": 4 }
+		}`, tmpDir.URI),
+	}, fmt.Sprintf(`{
+		"jsonrpc": "2.0",
+		"id": 4,
+		"result": [%s]
+	}`, appCallItem))
+
+	// down through the called module
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method:    "callHierarchy/outgoingCalls",
+		ReqParams: fmt.Sprintf(`{ "item": %s }`, appCallItem),
+	}, fmt.Sprintf(`{
+		"jsonrpc": "2.0",
+		"id": 5,
+		"result": [
+			{
+				"to": %s,
+				"fromRanges": [
+					{
+						"start": { "line": 2, "character": 7 },
+						"end": { "line": 2, "character": 11 }
+					}
+				]
+			}
+		]
+	}`, dbCallItem))
+
+	// module directory outside of any module block
+	appModuleItem := fmt.Sprintf(`{
+		"name": "app",
+		"kind": 2,
+		"detail": %q,
+		"uri": "%s/main.tf",
+		"range": {
+			"start": { "line": 0, "character": 0 },
+			"end": { "line": 0, "character": 0 }
+		},
+		"selectionRange": {
+			"start": { "line": 0, "character": 0 },
+			"end": { "line": 0, "character": 0 }
+		},
+		"data": { "path": %q }
+	}`, appDir.Path(), appDir.URI, appDir.Path())
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/prepareCallHierarchy",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": { "uri": "%s/main.tf" },
+			"position": { "line": 0, "character": 2 }
+		}`, appDir.URI),
+	}, fmt.Sprintf(`{
+		"jsonrpc": "2.0",
+		"id": 6,
+		"result": [%s]
+	}`, appModuleItem))
+
+	// up to the caller
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method:    "callHierarchy/incomingCalls",
+		ReqParams: fmt.Sprintf(`{ "item": %s }`, appModuleItem),
+	}, fmt.Sprintf(`{
+		"jsonrpc": "2.0",
+		"id": 7,
+		"result": [
+			{
+				"from": %s,
+				"fromRanges": [
+					{
+						"start": { "line": 0, "character": 7 },
+						"end": { "line": 0, "character": 12 }
+					}
+				]
+			}
+		]
+	}`, appCallItem))
+
+	// the module block in app is called whenever app is
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method:    "callHierarchy/incomingCalls",
+		ReqParams: fmt.Sprintf(`{ "item": %s }`, dbCallItem),
+	}, fmt.Sprintf(`{
+		"jsonrpc": "2.0",
+		"id": 8,
+		"result": [
+			{
+				"from": %s,
+				"fromRanges": [
+					{
+						"start": { "line": 0, "character": 7 },
+						"end": { "line": 0, "character": 12 }
+					}
+				]
+			}
+		]
+	}`, appCallItem))
+}
+
+func TestLangServer_callHierarchy_installedModules(t *testing.T) {
+	tmpDir := TempDir(t)
+	vpcPath := filepath.Join(tmpDir.Path(), ".terraform", "modules", "vpc")
+
+	rootCfg := `module "vpc" {
+  source  = "terraform-aws-modules/vpc/aws"
+  version = "5.0.0"
+}
+`
+	writeTestFile(t, filepath.Join(tmpDir.Path(), "main.tf"), rootCfg)
+	writeTestFile(t, filepath.Join(vpcPath, "main.tf"), `module "subnets" {
+  source = "./modules/subnets"
+}
+`)
+	writeTestFile(t, filepath.Join(vpcPath, "modules", "subnets", "main.tf"), `variable "cidr" {}`)
+	writeTestFile(t, filepath.Join(tmpDir.Path(), ".terraform", "modules", "modules.json"), `{"Modules":[
+	{"Key":"","Source":"","Dir":"."},
+	{"Key":"vpc","Source":"registry.opentofu.org/terraform-aws-modules/vpc/aws","Version":"5.0.0","Dir":".terraform/modules/vpc"},
+	{"Key":"vpc.subnets","Source":"./modules/subnets","Dir":".terraform/modules/vpc/modules/subnets"}
+]}`)
+
+	ss, err := state.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wc := walker.NewWalkerCollector()
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TofuCalls: &exec.TofuMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				tmpDir.Path(): validTfMockCalls(),
+			},
+		},
+		StateStore:      ss,
+		WalkerCollector: wc,
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+	    "capabilities": {},
+	    "rootUri": %q,
+	    "processId": 12345
+	}`, tmpDir.URI)})
+	waitForWalkerPath(t, ss, wc, tmpDir)
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "opentofu",
+			"text": %q,
+			"uri": "%s/main.tf"
+		}
+	}`, rootCfg, tmpDir.URI)})
+	waitForAllJobs(t, ss)
+
+	vpcCallItem := fmt.Sprintf(`{
+		"name": "module.vpc",
+		"kind": 2,
+		"detail": "terraform-aws-modules/vpc/aws",
+		"uri": "%s/main.tf",
+		"range": {
+			"start": { "line": 0, "character": 0 },
+			"end": { "line": 3, "character": 1 }
+		},
+		"selectionRange": {
+			"start": { "line": 0, "character": 7 },
+			"end": { "line": 0, "character": 12 }
+		},
+		"data": { "path": %q, "call": "vpc" }
+	}`, tmpDir.URI, tmpDir.Path())
+
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method:    "callHierarchy/outgoingCalls",
+		ReqParams: fmt.Sprintf(`{ "item": %s }`, vpcCallItem),
+	}, fmt.Sprintf(`{
+		"jsonrpc": "2.0",
+		"id": 3,
+		"result": [
+			{
+				"to": {
+					"name": "module.subnets",
+					"kind": 2,
+					"detail": "./modules/subnets",
+					"uri": "%s/.terraform/modules/vpc/main.tf",
+					"range": {
+						"start": { "line": 0, "character": 0 },
+						"end": { "line": 2, "character": 1 }
+					},
+					"selectionRange": {
+						"start": { "line": 0, "character": 7 },
+						"end": { "line": 0, "character": 16 }
+					},
+					"data": { "path": %q, "call": "subnets" }
+				},
+				"fromRanges": [
+					{
+						"start": { "line": 0, "character": 7 },
+						"end": { "line": 0, "character": 16 }
+					}
+				]
+			}
+		]
+	}`, tmpDir.URI, vpcPath))
+
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "callHierarchy/incomingCalls",
+		ReqParams: fmt.Sprintf(`{ "item": {
+			"name": "vpc",
+			"kind": 2,
+			"uri": "%s/.terraform/modules/vpc/main.tf",
+			"range": {
+				"start": { "line": 0, "character": 0 },
+				"end": { "line": 0, "character": 0 }
+			},
+			"selectionRange": {
+				"start": { "line": 0, "character": 0 },
+				"end": { "line": 0, "character": 0 }
+			},
+			"data": { "path": %q }
+		} }`, tmpDir.URI, vpcPath),
+	}, fmt.Sprintf(`{
+		"jsonrpc": "2.0",
+		"id": 4,
+		"result": [
+			{
+				"from": %s,
+				"fromRanges": [
+					{
+						"start": { "line": 0, "character": 7 },
+						"end": { "line": 0, "character": 12 }
+					}
+				]
+			}
+		]
+	}`, vpcCallItem))
+}
+
+func writeTestFile(t *testing.T, path, content string) {
+	err := os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(path, []byte(content), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -8,14 +8,23 @@ package handlers
 import (
 	"context"
 	"fmt"
+	"path/filepath"
+	"slices"
 
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
+	lsctx "github.com/opentofu/tofu-ls/internal/context"
+	idecoder "github.com/opentofu/tofu-ls/internal/decoder"
+	"github.com/opentofu/tofu-ls/internal/document"
+	"github.com/opentofu/tofu-ls/internal/features/modules/decoder/validations"
 	"github.com/opentofu/tofu-ls/internal/langserver/errors"
 	ilsp "github.com/opentofu/tofu-ls/internal/lsp"
 	lsp "github.com/opentofu/tofu-ls/internal/protocol"
 	"github.com/opentofu/tofu-ls/internal/tofu/module"
+	op "github.com/opentofu/tofu-ls/internal/tofu/module/operation"
 )
 
-func (svc *service) TextDocumentCodeAction(ctx context.Context, params lsp.CodeActionParams) []lsp.CodeAction {
+func (svc *service) TextDocumentCodeAction(ctx context.Context, params lsp.CodeActionParams) []ilsp.CodeAction {
 	ca, err := svc.textDocumentCodeAction(ctx, params)
 	if err != nil {
 		svc.logger.Printf("code action failed: %s", err)
@@ -24,22 +33,35 @@ func (svc *service) TextDocumentCodeAction(ctx context.Context, params lsp.CodeA
 	return ca
 }
 
-func (svc *service) textDocumentCodeAction(ctx context.Context, params lsp.CodeActionParams) ([]lsp.CodeAction, error) {
-	var ca []lsp.CodeAction
+// codeActionJobTypes are the types of jobs which quick fixes
+// and refactorings depend on
+var codeActionJobTypes = []string{
+	op.OpTypeParseModuleConfiguration.String(),
+	op.OpTypeLoadModuleMetadata.String(),
+	op.OpTypePreloadEmbeddedSchema.String(),
+	op.OpTypeDecodeReferenceTargets.String(),
+	op.OpTypeDecodeReferenceOrigins.String(),
+	op.OpTypeSchemaModuleValidation.String(),
+	op.OpTypeReferenceValidation.String(),
+}
+
+func (svc *service) textDocumentCodeAction(ctx context.Context, params lsp.CodeActionParams) ([]ilsp.CodeAction, error) {
+	var ca []ilsp.CodeAction
 
 	// For action definitions, refer to https://code.visualstudio.com/api/references/vscode-api#CodeActionKind
-	// We only support format type code actions at the moment, and do not want to format without the client asking for
-	// them, so exit early here if nothing is requested.
-	if len(params.Context.Only) == 0 {
-		svc.logger.Printf("No code action requested, exiting")
-		return ca, nil
+	// We do not want to format without the client asking for it, so when no particular
+	// kind is requested (e.g. for the lightbulb menu) we only offer quick fixes
+	// for diagnostics in the given context and refactorings of the selection.
+	only := params.Context.Only
+	if len(only) == 0 {
+		only = []lsp.CodeActionKind{lsp.QuickFix, lsp.RefactorExtract}
 	}
 
-	for _, o := range params.Context.Only {
+	for _, o := range only {
 		svc.logger.Printf("Code actions requested: %q", o)
 	}
 
-	wantedCodeActions := ilsp.SupportedCodeActions.Only(params.Context.Only)
+	wantedCodeActions := ilsp.SupportedCodeActions.Only(only)
 	if len(wantedCodeActions) == 0 {
 		return nil, fmt.Errorf("could not find a supported code action to execute for %s, wanted %v",
 			params.TextDocument.URI, params.Context.Only)
@@ -54,6 +76,17 @@ func (svc *service) textDocumentCodeAction(ctx context.Context, params lsp.CodeA
 		return ca, err
 	}
 
+	if wantedCodeActions[lsp.QuickFix] || wantedCodeActions[lsp.RefactorExtract] {
+		// Fixes are attached to diagnostics, renames are detected when decoding
+		// and extracting needs expressions of the current version of the document,
+		// so we wait for any jobs which may still be running for the current version
+		jobIds, err := svc.stateStore.JobStore.ListIncompleteJobsForDirOfTypes(dh.Dir, codeActionJobTypes...)
+		if err != nil {
+			return ca, err
+		}
+		svc.stateStore.JobStore.WaitForJobs(ctx, jobIds...)
+	}
+
 	for action := range wantedCodeActions {
 		switch action {
 		case ilsp.SourceFormatAllTofu:
@@ -67,17 +100,212 @@ func (svc *service) textDocumentCodeAction(ctx context.Context, params lsp.CodeA
 				return ca, err
 			}
 
-			ca = append(ca, lsp.CodeAction{
-				Title: "Format Document",
-				Kind:  action,
-				Edit: lsp.WorkspaceEdit{
-					Changes: map[lsp.DocumentURI][]lsp.TextEdit{
-						lsp.DocumentURI(dh.FullURI()): edits,
+			ca = append(ca, ilsp.CodeAction{
+				CodeAction: lsp.CodeAction{
+					Title: "Format Document",
+					Kind:  action,
+					Edit: lsp.WorkspaceEdit{
+						Changes: map[lsp.DocumentURI][]lsp.TextEdit{
+							lsp.DocumentURI(dh.FullURI()): edits,
+						},
 					},
 				},
 			})
+		case lsp.QuickFix:
+			ca = append(ca, ilsp.CodeActionsFromLSP(svc.quickFixes(ctx, dh, doc.Filename, params.Context.Diagnostics))...)
+
+			movedCa, err := svc.movedBlockFixes(ctx, doc, params.Range)
+			if err != nil {
+				svc.logger.Printf("failed to suggest moved blocks: %s", err)
+			}
+			ca = append(ca, ilsp.CodeActionsFromLSP(movedCa)...)
+		case lsp.RefactorExtract:
+			extractCa, err := svc.extractActions(ctx, doc, params.Range)
+			if err != nil {
+				svc.logger.Printf("failed to extract selection: %s", err)
+			}
+			ca = append(ca, extractCa...)
+		}
+	}
+
+	return ca, nil
+}
+
+// extractActions returns code actions extracting the selected expression
+// into a local value, or the selected blocks into a new module.
+func (svc *service) extractActions(ctx context.Context, doc *document.Document, rng lsp.Range) ([]ilsp.CodeAction, error) {
+	ca := make([]ilsp.CodeAction, 0)
+	if !ilsp.IsValidConfigLanguage(doc.LanguageID) || rng.Start == rng.End {
+		return ca, nil
+	}
+
+	hclRng, err := documentRange(doc, rng)
+	if err != nil {
+		return ca, err
+	}
+
+	path := lang.Path{
+		Path:       doc.Dir.Path(),
+		LanguageID: ilsp.OpenTofu.String(),
+	}
+	extractedLocal, err := idecoder.ExtractToLocal(svc.pathReader, path, doc.Filename, hclRng)
+	if err != nil {
+		return ca, err
+	}
+	if extractedLocal != nil {
+		ca = append(ca, ilsp.CodeAction{
+			CodeAction: lsp.CodeAction{
+				Title: "Extract to local value",
+				Kind:  lsp.RefactorExtract,
+				Edit:  *ilsp.WorkspaceEditFromTextEdits(extractedLocal.Edits),
+			},
+		})
+	}
+
+	// New files can only be created via resource operations
+	cc, err := ilsp.ClientCapabilities(ctx)
+	if err != nil {
+		return ca, err
+	}
+	if cc.Workspace.WorkspaceEdit == nil || !cc.Workspace.WorkspaceEdit.DocumentChanges ||
+		!slices.Contains(cc.Workspace.WorkspaceEdit.ResourceOperations, lsp.Create) {
+		return ca, nil
+	}
+
+	extractedMod, err := idecoder.ExtractToModule(svc.pathReader, svc.fs, path, doc.Filename, hclRng)
+	if err != nil {
+		return ca, err
+	}
+	if extractedMod != nil {
+		newFiles := make(map[string]string, len(extractedMod.Files))
+		for name, content := range extractedMod.Files {
+			newFiles[filepath.Join(extractedMod.Dir, name)] = content
+		}
+		ca = append(ca, ilsp.CodeAction{
+			CodeAction: lsp.CodeAction{
+				Title: fmt.Sprintf("Extract to module %q", extractedMod.Name),
+				Kind:  lsp.RefactorExtract,
+			},
+			DocumentChanges: ilsp.DocumentChangesWithNewFiles(newFiles, extractedMod.Edits),
+		})
+	}
+
+	return ca, nil
+}
+
+// movedBlockFixes returns code actions declaring moved blocks
+// for resources renamed within the given range, since renaming
+// a resource would otherwise cause it to be replaced.
+func (svc *service) movedBlockFixes(ctx context.Context, doc *document.Document, rng lsp.Range) ([]lsp.CodeAction, error) {
+	ca := make([]lsp.CodeAction, 0)
+	if !ilsp.IsValidConfigLanguage(doc.LanguageID) {
+		return ca, nil
+	}
+
+	renames, err := svc.features.Modules.ResourceRenames(doc.Dir.Path())
+	if err != nil {
+		return ca, err
+	}
+	if len(renames) == 0 {
+		return ca, nil
+	}
+
+	hclRng, err := documentRange(doc, rng)
+	if err != nil {
+		return ca, err
+	}
+	path := lang.Path{
+		Path:       doc.Dir.Path(),
+		LanguageID: ilsp.OpenTofu.String(),
+	}
+
+	for _, rename := range renames {
+		edits, err := idecoder.MovedBlock(svc.pathReader, path, doc.Filename, hclRng, rename.From, rename.To)
+		if err != nil {
+			return ca, err
+		}
+		if edits == nil {
+			continue
 		}
+		ca = append(ca, lsp.CodeAction{
+			Title: fmt.Sprintf("Add moved block from %q", rename.From),
+			Kind:  lsp.QuickFix,
+			Edit:  *ilsp.WorkspaceEditFromTextEdits(edits),
+		})
 	}
 
 	return ca, nil
 }
+
+// documentRange converts the LSP range within the document to hcl.Range
+func documentRange(doc *document.Document, rng lsp.Range) (hcl.Range, error) {
+	start, err := ilsp.HCLPositionFromLspPosition(rng.Start, doc)
+	if err != nil {
+		return hcl.Range{}, err
+	}
+	end, err := ilsp.HCLPositionFromLspPosition(rng.End, doc)
+	if err != nil {
+		return hcl.Range{}, err
+	}
+	return hcl.Range{
+		Filename: doc.Filename,
+		Start:    start,
+		End:      end,
+	}, nil
+}
+
+// quickFixes returns code actions fixing any of the given diagnostics,
+// based on fixes attached to diagnostics when the module was validated.
+func (svc *service) quickFixes(ctx context.Context, dh document.Handle, filename string, diags []lsp.Diagnostic) []lsp.CodeAction {
+	ca := make([]lsp.CodeAction, 0)
+	if len(diags) == 0 {
+		return ca
+	}
+
+	modDiags := svc.features.Modules.Diagnostics(dh.Dir.Path())
+	for source, hclDiags := range modDiags[filename] {
+		for _, hclDiag := range hclDiags {
+			fixes := validations.DiagnosticQuickFixes(hclDiag)
+			if len(fixes) == 0 {
+				continue
+			}
+
+			lspDiag := ilsp.HCLDiagsToLSP(hcl.Diagnostics{hclDiag}, source.String())[0]
+			for _, diag := range diags {
+				if diag.Source != lspDiag.Source || diag.Message != lspDiag.Message || diag.Range != lspDiag.Range {
+					continue
+				}
+
+				for _, fix := range fixes {
+					fileEdits := make(map[string][]lang.TextEdit)
+					for _, edit := range fix.Edits {
+						path := filepath.Join(dh.Dir.Path(), edit.Range.Filename)
+						fileEdits[path] = append(fileEdits[path], edit)
+					}
+
+					action := lsp.CodeAction{
+						Title:       fix.Title,
+						Kind:        lsp.QuickFix,
+						Diagnostics: []lsp.Diagnostic{diag},
+						Edit:        *ilsp.WorkspaceEditFromTextEdits(fileEdits),
+					}
+					if fix.Command != nil {
+						command, err := ilsp.Command(*fix.Command)
+						if err != nil {
+							svc.logger.Printf("skipping quick fix %q: %s", fix.Title, err)
+							continue
+						}
+						if commandPrefix, _ := lsctx.CommandPrefix(ctx); commandPrefix != "" {
+							command.Command = fmt.Sprintf("%s.%s", commandPrefix, command.Command)
+						}
+						action.Command = &command
+					}
+
+					ca = append(ca, action)
+				}
+			}
+		}
+	}
+
+	return ca
+}
@@ -7,6 +7,7 @@ package handlers
 
 import (
 	"fmt"
+	"path/filepath"
 	"testing"
 
 	"github.com/hashicorp/go-version"
@@ -190,6 +191,24 @@ func TestLangServer_codeAction_no_code_action_requested(t *testing.T) {
 				"result": null
 			}`,
 		},
+		{
+			name: "no code action kind requested",
+			request: &langserver.CallRequest{
+				Method: "textDocument/codeAction",
+				ReqParams: fmt.Sprintf(`{
+						"textDocument": { "uri": "%s/main.tf" },
+						"range": {
+							"start": { "line": 0, "character": 0 },
+							"end": { "line": 0, "character": 0 }
+						},
+						"context": { "diagnostics": [] }
+					}`, tmpDir.URI)},
+			want: `{
+				"jsonrpc": "2.0",
+				"id": 3,
+				"result": null
+			}`,
+		},
 		{
 			name: "source.formatAll.opentofu code action requested",
 			request: &langserver.CallRequest{
@@ -364,3 +383,402 @@ func TestLangServer_codeAction_no_code_action_requested(t *testing.T) {
 		})
 	}
 }
+
+func TestLangServer_codeAction_quickFix(t *testing.T) {
+	tmpDir := TempDir(t)
+
+	ss, err := state.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wc := walker.NewWalkerCollector()
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TofuCalls: &exec.TofuMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				tmpDir.Path(): {
+					{
+						Method:        "Version",
+						Repeatability: 1,
+						Arguments: []interface{}{
+							mock.AnythingOfType(""),
+						},
+						ReturnArguments: []interface{}{
+							version.Must(version.NewVersion("0.12.0")),
+							nil,
+							nil,
+						},
+					},
+					{
+						Method:        "GetExecPath",
+						Repeatability: 1,
+						ReturnArguments: []interface{}{
+							"",
+						},
+					},
+				},
+			},
+		},
+		StateStore:      ss,
+		WalkerCollector: wc,
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+	    "capabilities": {},
+	    "rootUri": %q,
+	    "processId": 12345
+	}`, tmpDir.URI)})
+	waitForWalkerPath(t, ss, wc, tmpDir)
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "opentofu",
+			"text": "output \"foo\" {\n  value = var.bar\n}\nlocals {\n  unused = 1\n}\n",
+			"uri": "%s/main.tf"
+		}
+	}`, tmpDir.URI)})
+	waitForAllJobs(t, ss)
+
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/codeAction",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": { "uri": "%s/main.tf" },
+			"range": {
+				"start": { "line": 1, "character": 10 },
+				"end": { "line": 1, "character": 10 }
+			},
+			"context": {
+				"diagnostics": [
+					{
+						"range": {
+							"start": { "line": 1, "character": 10 },
+							"end": { "line": 1, "character": 17 }
+						},
+						"severity": 1,
+						"source": "OpenTofu",
+						"message": "No declaration found for \"var.bar\""
+					}
+				]
+			}
+		}`, tmpDir.URI)}, fmt.Sprintf(`{
+			"jsonrpc": "2.0",
+			"id": 3,
+			"result": [
+				{
+					"title": "Declare variable \"bar\"",
+					"kind": "quickfix",
+					"diagnostics": [
+						{
+							"range": {
+								"start": { "line": 1, "character": 10 },
+								"end": { "line": 1, "character": 17 }
+							},
+							"severity": 1,
+							"source": "OpenTofu",
+							"message": "No declaration found for \"var.bar\""
+						}
+					],
+					"edit": {
+						"changes": {
+							"%s/main.tf": [
+								{
+									"range": {
+										"start": { "line": 6, "character": 0 },
+										"end": { "line": 6, "character": 0 }
+									},
+									"newText": "\nvariable \"bar\" {\n}\n"
+								}
+							]
+						}
+					}
+				}
+			]
+		}`, tmpDir.URI))
+
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/codeAction",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": { "uri": "%s/main.tf" },
+			"range": {
+				"start": { "line": 4, "character": 2 },
+				"end": { "line": 4, "character": 2 }
+			},
+			"context": {
+				"diagnostics": [
+					{
+						"range": {
+							"start": { "line": 4, "character": 2 },
+							"end": { "line": 4, "character": 8 }
+						},
+						"severity": 2,
+						"source": "OpenTofu",
+						"message": "\"local.unused\" is declared but not used"
+					}
+				],
+				"only": ["quickfix"]
+			}
+		}`, tmpDir.URI)}, fmt.Sprintf(`{
+			"jsonrpc": "2.0",
+			"id": 4,
+			"result": [
+				{
+					"title": "Remove unused declaration of \"local.unused\"",
+					"kind": "quickfix",
+					"diagnostics": [
+						{
+							"range": {
+								"start": { "line": 4, "character": 2 },
+								"end": { "line": 4, "character": 8 }
+							},
+							"severity": 2,
+							"source": "OpenTofu",
+							"message": "\"local.unused\" is declared but not used"
+						}
+					],
+					"edit": {
+						"changes": {
+							"%s/main.tf": [
+								{
+									"range": {
+										"start": { "line": 4, "character": 0 },
+										"end": { "line": 5, "character": 0 }
+									},
+									"newText": ""
+								}
+							]
+						}
+					}
+				}
+			]
+		}`, tmpDir.URI))
+}
+
+func TestLangServer_codeAction_movedBlock(t *testing.T) {
+	tmpDir := TempDir(t)
+
+	ss, err := state.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wc := walker.NewWalkerCollector()
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TofuCalls: &exec.TofuMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				tmpDir.Path(): {
+					{
+						Method:        "Version",
+						Repeatability: 1,
+						Arguments: []interface{}{
+							mock.AnythingOfType(""),
+						},
+						ReturnArguments: []interface{}{
+							version.Must(version.NewVersion("0.12.0")),
+							nil,
+							nil,
+						},
+					},
+					{
+						Method:        "GetExecPath",
+						Repeatability: 1,
+						ReturnArguments: []interface{}{
+							"",
+						},
+					},
+				},
+			},
+		},
+		StateStore:      ss,
+		WalkerCollector: wc,
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+	    "capabilities": {},
+	    "rootUri": %q,
+	    "processId": 12345
+	}`, tmpDir.URI)})
+	waitForWalkerPath(t, ss, wc, tmpDir)
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "opentofu",
+			"text": "resource \"aws_instance\" \"web\" {\n}\n",
+			"uri": "%s/main.tf"
+		}
+	}`, tmpDir.URI)})
+	waitForAllJobs(t, ss)
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didChange",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 1,
+			"uri": "%s/main.tf"
+		},
+		"contentChanges": [
+			{
+				"text": "resource \"aws_instance\" \"server\" {\n}\n"
+			}
+		]
+	}`, tmpDir.URI)})
+	waitForAllJobs(t, ss)
+
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/codeAction",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": { "uri": "%s/main.tf" },
+			"range": {
+				"start": { "line": 0, "character": 27 },
+				"end": { "line": 0, "character": 27 }
+			},
+			"context": {
+				"diagnostics": [],
+				"only": ["quickfix"]
+			}
+		}`, tmpDir.URI)}, fmt.Sprintf(`{
+			"jsonrpc": "2.0",
+			"id": 4,
+			"result": [
+				{
+					"title": "Add moved block from \"aws_instance.web\"",
+					"kind": "quickfix",
+					"edit": {
+						"changes": {
+							"%s/main.tf": [
+								{
+									"range": {
+										"start": { "line": 2, "character": 0 },
+										"end": { "line": 2, "character": 0 }
+									},
+									"newText": "\nmoved {\n  from = aws_instance.web\n  to   = aws_instance.server\n}\n"
+								}
+							]
+						}
+					}
+				}
+			]
+		}`, tmpDir.URI))
+}
+
+func TestLangServer_codeAction_providerLockQuickFix(t *testing.T) {
+	tmpDir := TempDir(t)
+	cfg := `terraform {
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = "~> 5.0"
+    }
+  }
+}
+`
+	writeTestFile(t, filepath.Join(tmpDir.Path(), "main.tf"), cfg)
+	writeTestFile(t, filepath.Join(tmpDir.Path(), ".terraform.lock.hcl"), `provider "registry.opentofu.org/hashicorp/aws" {
+  version     = "4.67.0"
+  constraints = "~> 4.0"
+}
+`)
+
+	ss, err := state.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wc := walker.NewWalkerCollector()
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TofuCalls: &exec.TofuMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				tmpDir.Path(): validTfMockCalls(),
+			},
+		},
+		StateStore:      ss,
+		WalkerCollector: wc,
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+	    "capabilities": {},
+	    "initializationOptions": {
+	        "commandPrefix": "test"
+	    },
+	    "rootUri": %q,
+	    "processId": 12345
+	}`, tmpDir.URI)})
+	waitForWalkerPath(t, ss, wc, tmpDir)
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "opentofu",
+			"text": %q,
+			"uri": "%s/main.tf"
+		}
+	}`, cfg, tmpDir.URI)})
+	waitForAllJobs(t, ss)
+
+	diag := `{
+		"range": {
+			"start": { "line": 2, "character": 4 },
+			"end": { "line": 5, "character": 5 }
+		},
+		"severity": 1,
+		"source": "OpenTofu",
+		"message": "Locked version of provider hashicorp/aws doesn't match required_providers: Version 4.67.0 is locked, which doesn't satisfy the constraints \"~\u003e 5.0\"."
+	}`
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/codeAction",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": { "uri": "%s/main.tf" },
+			"range": {
+				"start": { "line": 2, "character": 4 },
+				"end": { "line": 2, "character": 4 }
+			},
+			"context": {
+				"diagnostics": [%s],
+				"only": ["quickfix"]
+			}
+		}`, tmpDir.URI, diag)}, fmt.Sprintf(`{
+			"jsonrpc": "2.0",
+			"id": 3,
+			"result": [
+				{
+					"title": "Run tofu init -upgrade",
+					"kind": "quickfix",
+					"diagnostics": [%s],
+					"edit": {},
+					"command": {
+						"title": "Run tofu init -upgrade",
+						"command": "test.tofu-ls.tofu.init",
+						"arguments": ["uri=%s", "upgrade=true"]
+					}
+				}
+			]
+		}`, diag, tmpDir.URI))
+}
@@ -396,3 +396,147 @@ variable "instances" {
 			]
 	}`)
 }
+
+func TestCodeLens_initStatus(t *testing.T) {
+	tmpDir := TempDir(t)
+	appDir := document.DirHandleFromPath(filepath.Join(tmpDir.Path(), "modules", "app"))
+
+	cfg := `terraform {
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = "~> 5.0"
+    }
+  }
+}
+
+module "app" {
+  source = "./modules/app"
+}
+`
+	writeTestFile(t, filepath.Join(tmpDir.Path(), "main.tf"), cfg)
+	writeTestFile(t, filepath.Join(tmpDir.Path(), ".terraform.lock.hcl"), `provider "registry.opentofu.org/hashicorp/aws" {
+  version     = "4.67.0"
+  constraints = "~> 4.0"
+}
+`)
+	writeTestFile(t, filepath.Join(tmpDir.Path(), ".terraform", "modules", "modules.json"), `{
+  "Modules": [
+    {"Key": "", "Source": "", "Dir": "."},
+    {"Key": "app", "Source": "./modules/app", "Dir": "modules/app"}
+  ]
+}`)
+	writeTestFile(t, filepath.Join(appDir.Path(), "main.tf"), `variable "name" {}
+`)
+
+	ss, err := state.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wc := walker.NewWalkerCollector()
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TofuCalls: &exec.TofuMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				tmpDir.Path(): validTfMockCalls(),
+				appDir.Path(): validTfMockCalls(),
+			},
+		},
+		StateStore:      ss,
+		WalkerCollector: wc,
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+		"capabilities": {},
+		"initializationOptions": {
+			"experimentalFeatures": {
+				"initStatusCodeLens": true
+			}
+		},
+		"rootUri": %q,
+		"processId": 12345
+	}`, tmpDir.URI)})
+	waitForWalkerPath(t, ss, wc, tmpDir)
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "opentofu",
+			"text": %q,
+			"uri": "%s/main.tf"
+		}
+	}`, cfg, tmpDir.URI)})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "opentofu",
+			"text": "variable \"name\" {}\n",
+			"uri": "%s/main.tf"
+		}
+	}`, appDir.URI)})
+	waitForAllJobs(t, ss)
+
+	topOfFile := `{
+		"start": { "line": 0, "character": 0 },
+		"end": { "line": 0, "character": 0 }
+	}`
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/codeLens",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/main.tf"
+			}
+		}`, tmpDir.URI),
+	}, fmt.Sprintf(`{
+		"jsonrpc": "2.0",
+		"id": 4,
+		"result": [
+			{
+				"range": %[1]s,
+				"command": {
+					"title": "Initialized",
+					"command": ""
+				}
+			},
+			{
+				"range": %[1]s,
+				"command": {
+					"title": "1 provider not matching required_providers: hashicorp/aws (4.67.0 installed, ~\u003e 5.0 required)",
+					"command": ""
+				}
+			},
+			{
+				"range": %[1]s,
+				"command": {
+					"title": "Run tofu init",
+					"command": "tofu-ls.tofu.init",
+					"arguments": ["uri=%[2]s"]
+				}
+			}
+		]
+	}`, topOfFile, tmpDir.URI))
+
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/codeLens",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/main.tf"
+			}
+		}`, appDir.URI),
+	}, `{
+		"jsonrpc": "2.0",
+		"id": 5,
+		"result": []
+	}`)
+}
@@ -10,6 +10,7 @@ import (
 	"fmt"
 
 	"github.com/creachadair/jrpc2"
+	"github.com/opentofu/tofu-exec/tfexec"
 	"github.com/opentofu/tofu-ls/internal/document"
 	"github.com/opentofu/tofu-ls/internal/langserver/cmd"
 	"github.com/opentofu/tofu-ls/internal/langserver/errors"
@@ -39,8 +40,13 @@ func (h *CmdHandler) TofuInitHandler(ctx context.Context, args cmd.CommandArgs)
 		progress.End(ctx, "Finished")
 	}()
 
+	opts := make([]tfexec.InitOption, 0)
+	if upgrade, ok := args.GetBool("upgrade"); ok && upgrade {
+		opts = append(opts, tfexec.Upgrade(true))
+	}
+
 	progress.Report(ctx, "Running tofu init ...")
-	err = tfExec.Init(ctx)
+	err = tfExec.Init(ctx, opts...)
 	if err != nil {
 		return nil, err
 	}
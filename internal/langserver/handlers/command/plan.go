@@ -0,0 +1,52 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/opentofu/tofu-ls/internal/document"
+	"github.com/opentofu/tofu-ls/internal/features/modules/jobs"
+	"github.com/opentofu/tofu-ls/internal/job"
+	"github.com/opentofu/tofu-ls/internal/langserver/cmd"
+	"github.com/opentofu/tofu-ls/internal/langserver/progress"
+	op "github.com/opentofu/tofu-ls/internal/tofu/module/operation"
+	"github.com/opentofu/tofu-ls/internal/uri"
+)
+
+func (h *CmdHandler) TofuPlanHandler(ctx context.Context, args cmd.CommandArgs) (interface{}, error) {
+	dirUri, ok := args.GetString("uri")
+	if !ok || dirUri == "" {
+		return nil, fmt.Errorf("%w: expected module uri argument to be set", jrpc2.InvalidParams.Err())
+	}
+
+	if !uri.IsURIValid(dirUri) {
+		return nil, fmt.Errorf("URI %q is not valid", dirUri)
+	}
+
+	dirHandle := document.DirHandleFromURI(dirUri)
+
+	progress.Begin(ctx, "Planning")
+	defer func() {
+		progress.End(ctx, "Finished")
+	}()
+
+	progress.Report(ctx, "Running tofu plan ...")
+	id, err := h.StateStore.JobStore.EnqueueJob(ctx, job.Job{
+		Dir: dirHandle,
+		Func: func(ctx context.Context) error {
+			return jobs.TofuPlan(ctx, h.ModulesFeature.Store, dirHandle.Path())
+		},
+		Type:        op.OpTypeTofuPlan.String(),
+		Priority:    job.HighPriority,
+		IgnoreState: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, h.StateStore.JobStore.WaitForJobs(ctx, id)
+}
@@ -0,0 +1,267 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package handlers
+
+import (
+	"context"
+	"path/filepath"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/opentofu/tofu-ls/internal/document"
+	"github.com/opentofu/tofu-ls/internal/eventbus"
+	"github.com/opentofu/tofu-ls/internal/job"
+	"github.com/opentofu/tofu-ls/internal/langserver/diagnostics"
+	ilsp "github.com/opentofu/tofu-ls/internal/lsp"
+	lsp "github.com/opentofu/tofu-ls/internal/protocol"
+	"github.com/opentofu/tofu-ls/internal/tofu/ast"
+	"github.com/opentofu/tofu-ls/internal/tofu/datadir"
+	op "github.com/opentofu/tofu-ls/internal/tofu/module/operation"
+	"github.com/opentofu/tofu-ls/internal/uri"
+)
+
+// TextDocumentDiagnostic returns diagnostics of a document, which
+// doesn't need to be open, as long as its directory was indexed.
+func (svc *service) TextDocumentDiagnostic(ctx context.Context, params lsp.DocumentDiagnosticParams) (interface{}, error) {
+	dh := ilsp.HandleFromDocumentURI(params.TextDocument.URI)
+
+	languageID, ok := svc.indexedDirs(ctx)[dh.Dir.Path()]
+	if !ok {
+		hasOpenDocs, err := svc.stateStore.DocumentStore.HasOpenDocuments(dh.Dir)
+		if err != nil {
+			return nil, err
+		}
+		if !hasOpenDocs {
+			// Nothing is known about the document
+			return fullDiagnosticReport(make([]lsp.Diagnostic, 0)), nil
+		}
+	}
+
+	err := svc.validateDirs(ctx, map[string]string{dh.Dir.Path(): languageID})
+	if err != nil {
+		return nil, err
+	}
+
+	diags := dirDiagnostics(svc.features, dh.Dir.Path())
+	items := fileDiagnosticsToLSP(diags[dh.Filename])
+	resultId := ilsp.DiagnosticsResultID(items)
+
+	if params.PreviousResultID == resultId {
+		return lsp.RelatedUnchangedDocumentDiagnosticReport{
+			UnchangedDocumentDiagnosticReport: lsp.UnchangedDocumentDiagnosticReport{
+				Kind:     string(lsp.DiagnosticUnchanged),
+				ResultID: resultId,
+			},
+		}, nil
+	}
+
+	return fullDiagnosticReport(items), nil
+}
+
+// WorkspaceDiagnostic returns diagnostics of all files in directories
+// indexed by the walker, except for installed modules. Files with the
+// same diagnostics as the client already knows are reported as unchanged.
+func (svc *service) WorkspaceDiagnostic(ctx context.Context, params lsp.WorkspaceDiagnosticParams) (ilsp.WorkspaceDiagnosticReport, error) {
+	items := make([]interface{}, 0)
+
+	dirs := svc.indexedDirs(ctx)
+	err := svc.validateDirs(ctx, dirs)
+	if err != nil {
+		return ilsp.WorkspaceDiagnosticReport{}, err
+	}
+
+	previousResultIds := make(map[lsp.DocumentURI]string, len(params.PreviousResultIds))
+	for _, previous := range params.PreviousResultIds {
+		previousResultIds[previous.URI] = previous.Value
+	}
+
+	paths := make([]string, 0, len(dirs))
+	for path := range dirs {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		diags := dirDiagnostics(svc.features, path)
+
+		filenames := make([]string, 0, len(diags))
+		for filename := range diags {
+			if filename != "" {
+				filenames = append(filenames, filename)
+			}
+		}
+		sort.Strings(filenames)
+
+		for _, filename := range filenames {
+			fileURI := lsp.DocumentURI(uri.FromPath(filepath.Join(path, filename)))
+			version := svc.openDocumentVersion(document.HandleFromPath(filepath.Join(path, filename)))
+
+			fileItems := fileDiagnosticsToLSP(diags[filename])
+			resultId := ilsp.DiagnosticsResultID(fileItems)
+
+			if previousResultIds[fileURI] == resultId {
+				items = append(items, ilsp.WorkspaceUnchangedDiagnosticReport{
+					URI:     fileURI,
+					Version: version,
+					UnchangedDocumentDiagnosticReport: lsp.UnchangedDocumentDiagnosticReport{
+						Kind:     string(lsp.DiagnosticUnchanged),
+						ResultID: resultId,
+					},
+				})
+				continue
+			}
+
+			items = append(items, ilsp.WorkspaceFullDiagnosticReport{
+				URI:                          fileURI,
+				Version:                      version,
+				FullDocumentDiagnosticReport: fullDiagnosticReport(fileItems).FullDocumentDiagnosticReport,
+			})
+		}
+	}
+
+	return ilsp.WorkspaceDiagnosticReport{
+		Items: items,
+	}, nil
+}
+
+// indexedDirs returns paths of directories indexed by any feature,
+// except for those within a data directory, with the language ID
+// under which they were indexed.
+func (svc *service) indexedDirs(ctx context.Context) map[string]string {
+	dirs := make(map[string]string, 0)
+
+	paths := svc.features.Tests.Paths(ctx)
+	paths = append(paths, svc.features.Variables.Paths(ctx)...)
+	// Module paths come last to take precedence
+	paths = append(paths, svc.features.Modules.Paths(ctx)...)
+	for _, path := range paths {
+		if datadir.IsInstalledModulePath(path.Path) {
+			continue
+		}
+		dirs[path.Path] = path.LanguageID
+	}
+
+	return dirs
+}
+
+// diagnosticJobTypes are the types of jobs which parse, decode
+// and validate modules, variable definitions and tests
+var diagnosticJobTypes = []string{
+	op.OpTypeParseModuleConfiguration.String(),
+	op.OpTypeLoadModuleMetadata.String(),
+	op.OpTypePreloadEmbeddedSchema.String(),
+	op.OpTypeDecodeReferenceTargets.String(),
+	op.OpTypeDecodeReferenceOrigins.String(),
+	op.OpTypeSchemaModuleValidation.String(),
+	op.OpTypeReferenceValidation.String(),
+	op.OpTypeProviderLockValidation.String(),
+	op.OpTypeParseVariables.String(),
+	op.OpTypeDecodeVarsReferences.String(),
+	op.OpTypeSchemaVarsValidation.String(),
+	op.OpTypeParseTestConfiguration.String(),
+	op.OpTypeDecodeTestReferenceTargets.String(),
+	op.OpTypeDecodeTestReferenceOrigins.String(),
+	op.OpTypeSchemaTestValidation.String(),
+	op.OpTypeParseMockConfiguration.String(),
+	op.OpTypeSchemaMockValidation.String(),
+}
+
+// validateDirs validates the given directories in the same way as
+// if a document in each of them was opened, and waits for the jobs
+// parsing, decoding and validating them, including validation jobs
+// which get scheduled once a module is decoded. Jobs which execute
+// tofu or make requests to a registry are not waited for.
+func (svc *service) validateDirs(ctx context.Context, dirs map[string]string) error {
+	paths := make([]string, 0, len(dirs))
+	for path := range dirs {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	handles := make([]document.DirHandle, 0, len(paths))
+	for _, path := range paths {
+		dir := document.DirHandleFromPath(path)
+		handles = append(handles, dir)
+
+		hasOpenDocs, err := svc.stateStore.DocumentStore.HasOpenDocuments(dir)
+		if err != nil {
+			return err
+		}
+		if hasOpenDocs || dirs[path] == "" {
+			// Open documents are validated already
+			continue
+		}
+
+		svc.eventBus.DidOpen(eventbus.DidOpenEvent{
+			Context:    ctx,
+			Dir:        dir,
+			LanguageID: dirs[path],
+		})
+	}
+
+	for {
+		jobIds := make(job.IDs, 0)
+		for _, dir := range handles {
+			dirIds, err := svc.stateStore.JobStore.ListIncompleteJobsForDirOfTypes(dir, diagnosticJobTypes...)
+			if err != nil {
+				return err
+			}
+			jobIds = append(jobIds, dirIds...)
+		}
+		if len(jobIds) == 0 {
+			return nil
+		}
+
+		err := svc.stateStore.JobStore.WaitForJobs(ctx, jobIds...)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (svc *service) openDocumentVersion(dh document.Handle) *int32 {
+	doc, err := svc.stateStore.DocumentStore.GetDocument(dh)
+	if err != nil {
+		return nil
+	}
+	version := int32(doc.Version)
+	return &version
+}
+
+// dirDiagnostics returns diagnostics of all features for the directory
+func dirDiagnostics(features *Features, path string) diagnostics.Diagnostics {
+	diags := diagnostics.NewDiagnostics()
+	diags.Extend(features.Modules.Diagnostics(path))
+	diags.Extend(features.Variables.Diagnostics(path))
+	diags.Extend(features.Tests.Diagnostics(path))
+	return diags
+}
+
+// fileDiagnosticsToLSP converts diagnostics of a single file, ordered
+// by source, so that the same diagnostics result in the same result ID
+func fileDiagnosticsToLSP(fileDiags map[ast.DiagnosticSource]hcl.Diagnostics) []lsp.Diagnostic {
+	sources := make([]ast.DiagnosticSource, 0, len(fileDiags))
+	for source := range fileDiags {
+		sources = append(sources, source)
+	}
+	sort.Slice(sources, func(i, j int) bool {
+		return sources[i] < sources[j]
+	})
+
+	items := make([]lsp.Diagnostic, 0)
+	for _, source := range sources {
+		items = append(items, ilsp.HCLDiagsToLSP(fileDiags[source], source.String())...)
+	}
+	return items
+}
+
+func fullDiagnosticReport(items []lsp.Diagnostic) lsp.RelatedFullDocumentDiagnosticReport {
+	return lsp.RelatedFullDocumentDiagnosticReport{
+		FullDocumentDiagnosticReport: lsp.FullDocumentDiagnosticReport{
+			Kind:     string(lsp.DiagnosticFull),
+			ResultID: ilsp.DiagnosticsResultID(items),
+			Items:    items,
+		},
+	}
+}
@@ -0,0 +1,228 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package handlers
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/opentofu/tofu-ls/internal/document"
+	"github.com/opentofu/tofu-ls/internal/langserver"
+	ilsp "github.com/opentofu/tofu-ls/internal/lsp"
+	lsp "github.com/opentofu/tofu-ls/internal/protocol"
+	"github.com/opentofu/tofu-ls/internal/state"
+	"github.com/opentofu/tofu-ls/internal/tofu/exec"
+	"github.com/opentofu/tofu-ls/internal/walker"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestLangServer_workspaceDiagnostic(t *testing.T) {
+	tmpDir := TempDir(t)
+	appDir := document.DirHandleFromPath(filepath.Join(tmpDir.Path(), "modules", "app"))
+	dbDir := document.DirHandleFromPath(filepath.Join(tmpDir.Path(), "modules", "db"))
+
+	writeTestFile(t, filepath.Join(tmpDir.Path(), "main.tf"), `module "app" {
+  source = "./modules/app"
+  name   = "app"
+}
+`)
+	writeTestFile(t, filepath.Join(appDir.Path(), "main.tf"), `variable "name" {}
+
+output "name" {
+  value = var.name
+}
+
+output "id" {
+  value = var.id
+}
+`)
+	writeTestFile(t, filepath.Join(dbDir.Path(), "main.tf"), `locals {
+`)
+
+	ss, err := state.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wc := walker.NewWalkerCollector()
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TofuCalls: &exec.TofuMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				tmpDir.Path(): validTfMockCalls(),
+			},
+		},
+		StateStore:      ss,
+		WalkerCollector: wc,
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+	    "capabilities": {
+	        "textDocument": {
+	            "diagnostic": {}
+	        }
+	    },
+	    "rootUri": %q,
+	    "processId": 12345
+	}`, tmpDir.URI)})
+	waitForWalkerPath(t, ss, wc, tmpDir)
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+
+	noDiagsId := ilsp.DiagnosticsResultID([]lsp.Diagnostic{})
+	dbDiags := `[{
+		"range": {
+			"start": { "line": 0, "character": 7 },
+			"end": { "line": 0, "character": 8 }
+		},
+		"severity": 1,
+		"source": "OpenTofu",
+		"message": "Unclosed configuration block: There is no closing brace for this block before the end of the file. This may be caused by incorrect brace nesting elsewhere in this file."
+	}]`
+	dbDiagsId := ilsp.DiagnosticsResultID([]lsp.Diagnostic{
+		{
+			Range: lsp.Range{
+				Start: lsp.Position{Line: 0, Character: 7},
+				End:   lsp.Position{Line: 0, Character: 8},
+			},
+			Severity: lsp.SeverityError,
+			Source:   "OpenTofu",
+			Message:  "Unclosed configuration block: There is no closing brace for this block before the end of the file. This may be caused by incorrect brace nesting elsewhere in this file.",
+		},
+	})
+
+	// Modules without open documents are validated too
+	appDiags := `[{
+		"range": {
+			"start": { "line": 7, "character": 10 },
+			"end": { "line": 7, "character": 16 }
+		},
+		"severity": 1,
+		"source": "OpenTofu",
+		"message": "No declaration found for \"var.id\""
+	}]`
+	appDiagsId := ilsp.DiagnosticsResultID([]lsp.Diagnostic{
+		{
+			Range: lsp.Range{
+				Start: lsp.Position{Line: 7, Character: 10},
+				End:   lsp.Position{Line: 7, Character: 16},
+			},
+			Severity: lsp.SeverityError,
+			Source:   "OpenTofu",
+			Message:  "No declaration found for \"var.id\"",
+		},
+	})
+
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "workspace/diagnostic",
+		ReqParams: `{
+			"previousResultIds": []
+		}`,
+	}, fmt.Sprintf(`{
+		"jsonrpc": "2.0",
+		"id": 2,
+		"result": {
+			"items": [
+				{
+					"uri": "%[1]s/main.tf",
+					"version": null,
+					"kind": "full",
+					"resultId": %[4]q,
+					"items": []
+				},
+				{
+					"uri": "%[2]s/main.tf",
+					"version": null,
+					"kind": "full",
+					"resultId": %[7]q,
+					"items": %[8]s
+				},
+				{
+					"uri": "%[3]s/main.tf",
+					"version": null,
+					"kind": "full",
+					"resultId": %[5]q,
+					"items": %[6]s
+				}
+			]
+		}
+	}`, tmpDir.URI, appDir.URI, dbDir.URI, noDiagsId, dbDiagsId, dbDiags, appDiagsId, appDiags))
+
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "workspace/diagnostic",
+		ReqParams: fmt.Sprintf(`{
+			"previousResultIds": [
+				{ "uri": "%[1]s/main.tf", "value": %[4]q },
+				{ "uri": "%[2]s/main.tf", "value": "stale" },
+				{ "uri": "%[3]s/main.tf", "value": %[5]q }
+			]
+		}`, tmpDir.URI, appDir.URI, dbDir.URI, noDiagsId, dbDiagsId),
+	}, fmt.Sprintf(`{
+		"jsonrpc": "2.0",
+		"id": 3,
+		"result": {
+			"items": [
+				{
+					"uri": "%[1]s/main.tf",
+					"version": null,
+					"kind": "unchanged",
+					"resultId": %[4]q
+				},
+				{
+					"uri": "%[2]s/main.tf",
+					"version": null,
+					"kind": "full",
+					"resultId": %[6]q,
+					"items": %[7]s
+				},
+				{
+					"uri": "%[3]s/main.tf",
+					"version": null,
+					"kind": "unchanged",
+					"resultId": %[5]q
+				}
+			]
+		}
+	}`, tmpDir.URI, appDir.URI, dbDir.URI, noDiagsId, dbDiagsId, appDiagsId, appDiags))
+
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/diagnostic",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/main.tf"
+			}
+		}`, dbDir.URI),
+	}, fmt.Sprintf(`{
+		"jsonrpc": "2.0",
+		"id": 4,
+		"result": {
+			"kind": "full",
+			"resultId": %q,
+			"items": %s
+		}
+	}`, dbDiagsId, dbDiags))
+
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/diagnostic",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/main.tf"
+			},
+			"previousResultId": %q
+		}`, dbDir.URI, dbDiagsId),
+	}, fmt.Sprintf(`{
+		"jsonrpc": "2.0",
+		"id": 5,
+		"result": {
+			"kind": "unchanged",
+			"resultId": %q
+		}
+	}`, dbDiagsId))
+}
@@ -0,0 +1,59 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package handlers
+
+import (
+	"context"
+
+	"github.com/hashicorp/hcl-lang/lang"
+	idecoder "github.com/opentofu/tofu-ls/internal/decoder"
+	ilsp "github.com/opentofu/tofu-ls/internal/lsp"
+	lsp "github.com/opentofu/tofu-ls/internal/protocol"
+)
+
+func (svc *service) DocumentHighlight(ctx context.Context, params lsp.DocumentHighlightParams) ([]lsp.DocumentHighlight, error) {
+	highlights := make([]lsp.DocumentHighlight, 0)
+
+	dh := ilsp.HandleFromDocumentURI(params.TextDocument.URI)
+	doc, err := svc.stateStore.DocumentStore.GetDocument(dh)
+	if err != nil {
+		return highlights, err
+	}
+	if !ilsp.IsValidConfigLanguage(doc.LanguageID) {
+		return highlights, nil
+	}
+
+	jobIds, err := svc.stateStore.JobStore.ListIncompleteJobsForDir(dh.Dir)
+	if err != nil {
+		return highlights, err
+	}
+	svc.stateStore.JobStore.WaitForJobs(ctx, jobIds...)
+
+	pos, err := ilsp.HCLPositionFromLspPosition(params.Position, doc)
+	if err != nil {
+		return highlights, err
+	}
+
+	path := lang.Path{
+		Path:       doc.Dir.Path(),
+		LanguageID: ilsp.OpenTofu.String(),
+	}
+	found, err := idecoder.DocumentHighlights(svc.pathReader, path, doc.Filename, pos)
+	if err != nil {
+		return highlights, err
+	}
+
+	for _, highlight := range found {
+		kind := lsp.Read
+		if highlight.IsDeclaration {
+			kind = lsp.Write
+		}
+		highlights = append(highlights, lsp.DocumentHighlight{
+			Range: ilsp.HCLRangeToLSP(highlight.Range),
+			Kind:  kind,
+		})
+	}
+
+	return highlights, nil
+}
@@ -0,0 +1,268 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package handlers
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/opentofu/tofu-ls/internal/langserver"
+	"github.com/opentofu/tofu-ls/internal/state"
+	"github.com/opentofu/tofu-ls/internal/tofu/exec"
+	"github.com/opentofu/tofu-ls/internal/walker"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestLangServer_documentHighlight(t *testing.T) {
+	tmpDir := TempDir(t)
+
+	ss, err := state.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wc := walker.NewWalkerCollector()
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TofuCalls: &exec.TofuMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				tmpDir.Path(): validTfMockCalls(),
+			},
+		},
+		StateStore:      ss,
+		WalkerCollector: wc,
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	cfg := `variable "region" {
+}
+
+locals {
+  name = "vpc-${var.region}"
+}
+
+output "region" {
+  value = var.region
+}
+
+resource "aws_vpc" "main" {
+}
+
+resource "aws_subnet" "a" {
+  depends_on = [aws_vpc.main]
+}
+`
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+	    "capabilities": {},
+	    "rootUri": %q,
+	    "processId": 12345
+	}`, tmpDir.URI)})
+	waitForWalkerPath(t, ss, wc, tmpDir)
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "opentofu",
+			"text": %q,
+			"uri": "%s/main.tf"
+		}
+	}`, cfg, tmpDir.URI)})
+	waitForAllJobs(t, ss)
+
+	// Variable reference
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/documentHighlight",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": { "uri": "%s/main.tf" },
+			"position": { "line": 8, "character": 12 }
+		}`, tmpDir.URI)}, `{
+			"jsonrpc": "2.0",
+			"id": 3,
+			"result": [
+				{
+					"range": {
+						"start": { "line": 0, "character": 10 },
+						"end": { "line": 0, "character": 16 }
+					},
+					"kind": 3
+				},
+				{
+					"range": {
+						"start": { "line": 4, "character": 16 },
+						"end": { "line": 4, "character": 26 }
+					},
+					"kind": 2
+				},
+				{
+					"range": {
+						"start": { "line": 8, "character": 10 },
+						"end": { "line": 8, "character": 20 }
+					},
+					"kind": 2
+				}
+			]
+		}`)
+
+	// Resource declaration
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/documentHighlight",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": { "uri": "%s/main.tf" },
+			"position": { "line": 11, "character": 22 }
+		}`, tmpDir.URI)}, `{
+			"jsonrpc": "2.0",
+			"id": 4,
+			"result": [
+				{
+					"range": {
+						"start": { "line": 11, "character": 20 },
+						"end": { "line": 11, "character": 24 }
+					},
+					"kind": 3
+				},
+				{
+					"range": {
+						"start": { "line": 15, "character": 16 },
+						"end": { "line": 15, "character": 28 }
+					},
+					"kind": 2
+				}
+			]
+		}`)
+
+	// Nothing to highlight
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/documentHighlight",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": { "uri": "%s/main.tf" },
+			"position": { "line": 2, "character": 0 }
+		}`, tmpDir.URI)}, `{
+			"jsonrpc": "2.0",
+			"id": 5,
+			"result": []
+		}`)
+}
+
+func TestLangServer_documentHighlight_moduleOutputs(t *testing.T) {
+	tmpDir := TempDir(t)
+
+	ss, err := state.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wc := walker.NewWalkerCollector()
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TofuCalls: &exec.TofuMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				tmpDir.Path(): validTfMockCalls(),
+			},
+		},
+		StateStore:      ss,
+		WalkerCollector: wc,
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	cfg := `module "vpc" {
+  source = "./modules/vpc"
+}
+
+output "vpc_id" {
+  value = module.vpc.id
+}
+
+output "vpc_arn" {
+  value = module.vpc.arn
+}
+
+output "vpc" {
+  value = "${module.vpc.id}/${module.vpc.arn}"
+}
+`
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+	    "capabilities": {},
+	    "rootUri": %q,
+	    "processId": 12345
+	}`, tmpDir.URI)})
+	waitForWalkerPath(t, ss, wc, tmpDir)
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "opentofu",
+			"text": %q,
+			"uri": "%s/main.tf"
+		}
+	}`, cfg, tmpDir.URI)})
+	waitForAllJobs(t, ss)
+
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/documentHighlight",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": { "uri": "%s/main.tf" },
+			"position": { "line": 5, "character": 22 }
+		}`, tmpDir.URI)}, `{
+			"jsonrpc": "2.0",
+			"id": 3,
+			"result": [
+				{
+					"range": {
+						"start": { "line": 5, "character": 10 },
+						"end": { "line": 5, "character": 23 }
+					},
+					"kind": 2
+				},
+				{
+					"range": {
+						"start": { "line": 13, "character": 13 },
+						"end": { "line": 13, "character": 26 }
+					},
+					"kind": 2
+				}
+			]
+		}`)
+
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/documentHighlight",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": { "uri": "%s/main.tf" },
+			"position": { "line": 9, "character": 22 }
+		}`, tmpDir.URI)}, `{
+			"jsonrpc": "2.0",
+			"id": 4,
+			"result": [
+				{
+					"range": {
+						"start": { "line": 9, "character": 10 },
+						"end": { "line": 9, "character": 24 }
+					},
+					"kind": 2
+				},
+				{
+					"range": {
+						"start": { "line": 13, "character": 30 },
+						"end": { "line": 13, "character": 44 }
+					},
+					"kind": 2
+				}
+			]
+		}`)
+}
@@ -30,6 +30,7 @@ func cmdHandlers(svc *service) cmd.Handlers {
 		cmd.Name("module.callers"):   cmdHandler.ModuleCallersHandler,
 		cmd.Name("tofu.init"):        cmdHandler.TofuInitHandler,
 		cmd.Name("tofu.validate"):    cmdHandler.TofuValidateHandler,
+		cmd.Name("tofu.plan"):        cmdHandler.TofuPlanHandler,
 		cmd.Name("module.calls"):     cmdHandler.ModuleCallsHandler,
 		cmd.Name("module.providers"): cmdHandler.ModuleProvidersHandler,
 		cmd.Name("module.opentofu"):  cmdHandler.TofuVersionRequestHandler,
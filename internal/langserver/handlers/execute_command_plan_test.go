@@ -0,0 +1,261 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package handlers
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/creachadair/jrpc2"
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/opentofu/tofu-ls/internal/document"
+	"github.com/opentofu/tofu-ls/internal/langserver"
+	"github.com/opentofu/tofu-ls/internal/langserver/cmd"
+	ilsp "github.com/opentofu/tofu-ls/internal/lsp"
+	lsp "github.com/opentofu/tofu-ls/internal/protocol"
+	"github.com/opentofu/tofu-ls/internal/state"
+	"github.com/opentofu/tofu-ls/internal/tofu/exec"
+	"github.com/opentofu/tofu-ls/internal/walker"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestLangServer_workspaceExecuteCommand_plan_argumentError(t *testing.T) {
+	tmpDir := TempDir(t)
+	testFileURI := fmt.Sprintf("%s/main.tf", tmpDir.URI)
+
+	ss, err := state.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wc := walker.NewWalkerCollector()
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TofuCalls: &exec.TofuMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				tmpDir.Path(): validTfMockCalls(),
+			},
+		},
+		StateStore:      ss,
+		WalkerCollector: wc,
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+	    "capabilities": {},
+	    "rootUri": %q,
+		"processId": 12345
+	}`, tmpDir.URI)})
+	waitForWalkerPath(t, ss, wc, tmpDir)
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "opentofu",
+			"text": "provider \"github\" {}",
+			"uri": %q
+		}
+	}`, testFileURI)})
+	waitForAllJobs(t, ss)
+
+	ls.CallAndExpectError(t, &langserver.CallRequest{
+		Method: "workspace/executeCommand",
+		ReqParams: fmt.Sprintf(`{
+		"command": %q
+	}`, cmd.Name("tofu.plan"))}, jrpc2.InvalidParams.Err())
+}
+
+func TestLangServer_workspaceExecuteCommand_plan_diagnostics(t *testing.T) {
+	tmpDir := TempDir(t)
+	appDir := document.DirHandleFromPath(filepath.Join(tmpDir.Path(), "modules", "app"))
+
+	mainCfg := `module "app" {
+  source = "./modules/app"
+  name   = "app"
+}
+`
+	writeTestFile(t, filepath.Join(tmpDir.Path(), "main.tf"), mainCfg)
+	writeTestFile(t, filepath.Join(appDir.Path(), "main.tf"), `variable "name" {}
+
+resource "null_resource" "app" {
+  triggers = {
+    name = var.name
+  }
+}
+`)
+
+	tfMockCalls := append(validTfMockCalls(), &mock.Call{
+		Method:        "Plan",
+		Repeatability: 1,
+		Arguments: []interface{}{
+			mock.AnythingOfType(""),
+		},
+		ReturnArguments: []interface{}{
+			[]tfjson.Diagnostic{
+				{
+					Severity: tfjson.DiagnosticSeverityError,
+					Summary:  "Invalid value for variable",
+					Range: &tfjson.Range{
+						Filename: "main.tf",
+						Start:    tfjson.Pos{Line: 3, Column: 12, Byte: 54},
+						End:      tfjson.Pos{Line: 3, Column: 17, Byte: 59},
+					},
+				},
+				{
+					Severity: tfjson.DiagnosticSeverityWarning,
+					Summary:  "Deprecated resource",
+					Range: &tfjson.Range{
+						Filename: filepath.Join("modules", "app", "main.tf"),
+						Start:    tfjson.Pos{Line: 3, Column: 1, Byte: 20},
+						End:      tfjson.Pos{Line: 3, Column: 31, Byte: 50},
+					},
+				},
+			},
+			false,
+			fmt.Errorf("exit status 1"),
+		},
+	})
+
+	ss, err := state.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wc := walker.NewWalkerCollector()
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TofuCalls: &exec.TofuMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				tmpDir.Path(): tfMockCalls,
+				appDir.Path(): validTfMockCalls(),
+			},
+		},
+		StateStore:      ss,
+		WalkerCollector: wc,
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+	    "capabilities": {
+	        "textDocument": {
+	            "diagnostic": {}
+	        }
+	    },
+	    "rootUri": %q,
+	    "processId": 12345
+	}`, tmpDir.URI)})
+	waitForWalkerPath(t, ss, wc, tmpDir)
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "opentofu",
+			"text": %q,
+			"uri": "%s/main.tf"
+		}
+	}`, mainCfg, tmpDir.URI)})
+	waitForAllJobs(t, ss)
+
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "workspace/executeCommand",
+		ReqParams: fmt.Sprintf(`{
+		"command": %q,
+		"arguments": ["uri=%s"]
+	}`, cmd.Name("tofu.plan"), tmpDir.URI)}, `{
+		"jsonrpc": "2.0",
+		"id": 3,
+		"result": null
+	}`)
+
+	rootDiags := []lsp.Diagnostic{
+		{
+			Range: lsp.Range{
+				Start: lsp.Position{Line: 2, Character: 11},
+				End:   lsp.Position{Line: 2, Character: 16},
+			},
+			Severity: lsp.SeverityError,
+			Source:   "OpenTofu plan",
+			Message:  "Invalid value for variable",
+		},
+	}
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/diagnostic",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/main.tf"
+			}
+		}`, tmpDir.URI),
+	}, fmt.Sprintf(`{
+		"jsonrpc": "2.0",
+		"id": 4,
+		"result": {
+			"kind": "full",
+			"resultId": %q,
+			"items": [
+				{
+					"range": {
+						"start": { "line": 2, "character": 11 },
+						"end": { "line": 2, "character": 16 }
+					},
+					"severity": 1,
+					"source": "OpenTofu plan",
+					"message": "Invalid value for variable"
+				}
+			]
+		}
+	}`, ilsp.DiagnosticsResultID(rootDiags)))
+
+	appDiags := []lsp.Diagnostic{
+		{
+			Range: lsp.Range{
+				Start: lsp.Position{Line: 2, Character: 0},
+				End:   lsp.Position{Line: 2, Character: 30},
+			},
+			Severity: lsp.SeverityWarning,
+			Source:   "OpenTofu plan",
+			Message:  "Deprecated resource",
+		},
+	}
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/diagnostic",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/main.tf"
+			}
+		}`, appDir.URI),
+	}, fmt.Sprintf(`{
+		"jsonrpc": "2.0",
+		"id": 5,
+		"result": {
+			"kind": "full",
+			"resultId": %q,
+			"items": [
+				{
+					"range": {
+						"start": { "line": 2, "character": 0 },
+						"end": { "line": 2, "character": 30 }
+					},
+					"severity": 2,
+					"source": "OpenTofu plan",
+					"message": "Deprecated resource"
+				}
+			]
+		}
+	}`, ilsp.DiagnosticsResultID(appDiags)))
+}
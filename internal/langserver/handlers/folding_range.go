@@ -0,0 +1,54 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package handlers
+
+import (
+	"context"
+
+	"github.com/hashicorp/hcl-lang/lang"
+	idecoder "github.com/opentofu/tofu-ls/internal/decoder"
+	ilsp "github.com/opentofu/tofu-ls/internal/lsp"
+	lsp "github.com/opentofu/tofu-ls/internal/protocol"
+)
+
+func (svc *service) FoldingRange(ctx context.Context, params lsp.FoldingRangeParams) ([]lsp.FoldingRange, error) {
+	ranges := make([]lsp.FoldingRange, 0)
+
+	dh := ilsp.HandleFromDocumentURI(params.TextDocument.URI)
+	doc, err := svc.stateStore.DocumentStore.GetDocument(dh)
+	if err != nil {
+		return ranges, err
+	}
+
+	jobIds, err := svc.stateStore.JobStore.ListIncompleteJobsForDir(dh.Dir)
+	if err != nil {
+		return ranges, err
+	}
+	svc.stateStore.JobStore.WaitForJobs(ctx, jobIds...)
+
+	pathCtx, err := svc.pathReader.PathContext(lang.Path{
+		Path:       doc.Dir.Path(),
+		LanguageID: ilsp.ParseLanguageID(doc.LanguageID).String(),
+	})
+	if err != nil {
+		return ranges, err
+	}
+	f, ok := pathCtx.Files[doc.Filename]
+	if !ok {
+		return ranges, nil
+	}
+
+	for _, rng := range idecoder.FoldingRanges(f) {
+		foldingRange := lsp.FoldingRange{
+			StartLine: uint32(rng.StartLine - 1),
+			EndLine:   uint32(rng.EndLine - 1),
+		}
+		if rng.IsComment {
+			foldingRange.Kind = string(lsp.Comment)
+		}
+		ranges = append(ranges, foldingRange)
+	}
+
+	return ranges, nil
+}
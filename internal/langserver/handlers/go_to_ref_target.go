@@ -7,9 +7,13 @@ package handlers
 
 import (
 	"context"
+	"errors"
 
 	"github.com/hashicorp/hcl-lang/decoder"
 	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/reference"
+	"github.com/hashicorp/hcl/v2"
+	idecoder "github.com/opentofu/tofu-ls/internal/decoder"
 	ilsp "github.com/opentofu/tofu-ls/internal/lsp"
 	lsp "github.com/opentofu/tofu-ls/internal/protocol"
 )
@@ -65,5 +69,96 @@ func (svc *service) goToReferenceTarget(ctx context.Context, params lsp.TextDocu
 		LanguageID: string(ilsp.ParseLanguageID(doc.LanguageID)),
 	}
 
-	return svc.decoder.ReferenceTargetsForOriginAtPos(path, doc.Filename, pos)
+	targets, err := svc.decoder.ReferenceTargetsForOriginAtPos(path, doc.Filename, pos)
+	var noOriginErr *reference.NoOriginFound
+	if err != nil && !errors.As(err, &noOriginErr) {
+		return nil, err
+	}
+	if ilsp.ParseLanguageID(doc.LanguageID) != ilsp.OpenTofu || targetsOutsidePath(targets, path) {
+		return targets, err
+	}
+
+	// The schema only knows about outputs and variables of called modules
+	// if they were indexed before the calling module, which often isn't
+	// the case for modules installed from elsewhere.
+	modTargets, ok := svc.moduleReferenceTargets(ctx, doc.Dir.Path(), doc.Filename, pos)
+	if !ok {
+		return targets, err
+	}
+
+	return append(targets, modTargets...), nil
+}
+
+// moduleReferenceTargets returns the output or variable declaration in
+// a called module referenced at pos, indexing the module if needed.
+func (svc *service) moduleReferenceTargets(ctx context.Context, modPath, filename string, pos hcl.Pos) (decoder.ReferenceTargets, bool) {
+	f, ok := svc.moduleFiles(modPath)[filename]
+	if !ok {
+		return nil, false
+	}
+	ref, ok := idecoder.ModuleReferenceAtPos(f, pos)
+	if !ok {
+		return nil, false
+	}
+
+	declared, err := svc.features.Modules.DeclaredModuleCalls(modPath)
+	if err != nil {
+		return nil, false
+	}
+	mc, ok := declared[ref.ModuleName]
+	if !ok {
+		return nil, false
+	}
+	mcPath, ok := svc.features.Modules.ModuleCallPath(modPath, mc)
+	if !ok {
+		return nil, false
+	}
+
+	jobIds, err := svc.features.Modules.DecodeModules(ctx, []string{mcPath})
+	if err != nil {
+		svc.logger.Printf("failed to decode module %q: %s", mcPath, err)
+		return nil, false
+	}
+	err = svc.stateStore.JobStore.WaitForJobs(ctx, jobIds...)
+	if err != nil {
+		return nil, false
+	}
+
+	mcLangPath := lang.Path{
+		Path:       mcPath,
+		LanguageID: ilsp.OpenTofu.String(),
+	}
+	pathCtx, err := svc.features.Modules.PathContext(mcLangPath)
+	if err != nil {
+		return nil, false
+	}
+
+	addr := ref.Address()
+	targets := make(decoder.ReferenceTargets, 0)
+	// Variables are targetable both with and without their type,
+	// so the same declaration can be listed more than once
+	seen := make(map[hcl.Range]bool)
+	for _, target := range pathCtx.ReferenceTargets {
+		if !target.Addr.Equals(addr) || target.RangePtr == nil || seen[*target.RangePtr] {
+			continue
+		}
+		seen[*target.RangePtr] = true
+		targets = append(targets, &decoder.ReferenceTarget{
+			OriginRange: ref.Range,
+			Path:        mcLangPath,
+			Range:       *target.RangePtr,
+			DefRangePtr: target.DefRangePtr,
+		})
+	}
+
+	return targets, len(targets) > 0
+}
+
+func targetsOutsidePath(targets decoder.ReferenceTargets, path lang.Path) bool {
+	for _, target := range targets {
+		if target.Path.Path != path.Path {
+			return true
+		}
+	}
+	return false
 }
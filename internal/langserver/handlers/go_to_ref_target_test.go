@@ -779,3 +779,145 @@ output "foo" {
 			]
 		}`, tmpDir.URI))
 }
+
+func TestDefinition_registryModuleInNestedModule(t *testing.T) {
+	tmpDir := TempDir(t)
+	appHandle := document.DirHandleFromPath(filepath.Join(tmpDir.Path(), "modules", "app"))
+	vpcPath := filepath.Join(tmpDir.Path(), ".terraform", "modules", "app.vpc")
+	vpcHandle := document.DirHandleFromPath(vpcPath)
+
+	appCfg := `module "vpc" {
+  source  = "terraform-aws-modules/vpc/aws"
+  version = "5.0.0"
+  name    = "main"
+}
+
+output "vpc_id" {
+  value = module.vpc.vpc_id
+}
+`
+	writeTestFile(t, filepath.Join(tmpDir.Path(), "main.tf"), `module "app" {
+  source = "./modules/app"
+}
+`)
+	writeTestFile(t, filepath.Join(appHandle.Path(), "main.tf"), appCfg)
+	writeTestFile(t, filepath.Join(vpcPath, "variables.tf"), `variable "name" {
+  type = string
+}
+`)
+	writeTestFile(t, filepath.Join(vpcPath, "outputs.tf"), `output "vpc_id" {
+  value = "vpc-${var.name}"
+}
+`)
+	writeTestFile(t, filepath.Join(tmpDir.Path(), ".terraform", "modules", "modules.json"), `{"Modules":[
+	{"Key":"","Source":"","Dir":"."},
+	{"Key":"app","Source":"./modules/app","Dir":"modules/app"},
+	{"Key":"app.vpc","Source":"registry.opentofu.org/terraform-aws-modules/vpc/aws","Version":"5.0.0","Dir":".terraform/modules/app.vpc"}
+]}`)
+
+	ss, err := state.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wc := walker.NewWalkerCollector()
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TofuCalls: &exec.TofuMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				tmpDir.Path():    validTfMockCalls(),
+				appHandle.Path(): validTfMockCalls(),
+			},
+		},
+		StateStore:      ss,
+		WalkerCollector: wc,
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+			"capabilities": {},
+			"rootUri": %q,
+			"processId": 12345
+	}`, tmpDir.URI)})
+	waitForWalkerPath(t, ss, wc, tmpDir)
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "opentofu",
+			"text": %q,
+			"uri": "%s/main.tf"
+		}
+	}`, appCfg, appHandle.URI)})
+	waitForAllJobs(t, ss)
+
+	// The module is only known from the manifest of the root module,
+	// so it is indexed on demand
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/definition",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/main.tf"
+			},
+			"position": {
+				"line": 7,
+				"character": 23
+			}
+		}`, appHandle.URI)}, fmt.Sprintf(`{
+			"jsonrpc": "2.0",
+			"id": 3,
+			"result": [
+				{
+					"uri": "%s/outputs.tf",
+					"range": {
+						"start": {
+							"line": 0,
+							"character": 0
+						},
+						"end": {
+							"line": 2,
+							"character": 1
+						}
+					}
+				}
+			]
+		}`, vpcHandle.URI))
+
+	// variable of the installed module
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/definition",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/main.tf"
+			},
+			"position": {
+				"line": 3,
+				"character": 4
+			}
+		}`, appHandle.URI)}, fmt.Sprintf(`{
+			"jsonrpc": "2.0",
+			"id": 4,
+			"result": [
+				{
+					"uri": "%s/variables.tf",
+					"range": {
+						"start": {
+							"line": 0,
+							"character": 0
+						},
+						"end": {
+							"line": 2,
+							"character": 1
+						}
+					}
+				}
+			]
+		}`, vpcHandle.URI))
+}
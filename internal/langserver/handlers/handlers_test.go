@@ -51,24 +51,29 @@ func initializeResponse(t *testing.T, commandPrefix string) string {
 				"declarationProvider": true,
 				"definitionProvider": true,
 				"referencesProvider": true,
+				"documentHighlightProvider": true,
 				"documentSymbolProvider": true,
 				"codeActionProvider": {
-					"codeActionKinds": ["source.formatAll.opentofu"]
+					"codeActionKinds": ["quickfix", "refactor.extract", "source.formatAll.opentofu"]
 				},
 				"codeLensProvider": {},
 				"documentLinkProvider": {},
 				"workspaceSymbolProvider": true,
 				"documentFormattingProvider": true,
+				"renameProvider": true,
+				"foldingRangeProvider": true,
 				"executeCommandProvider": {
 					"commands": %s,
 					"workDoneProgress":true
 				},
+				"callHierarchyProvider": true,
 				"semanticTokensProvider": {
 					"legend": {
 						"tokenTypes": [],
 						"tokenModifiers": []
 					}
 				},
+				"inlayHintProvider": true,
 				"workspace": {
 					"workspaceFolders": {
 						"supported": true,
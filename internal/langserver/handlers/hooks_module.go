@@ -9,6 +9,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/opentofu/tofu-ls/internal/document"
 	"github.com/opentofu/tofu-ls/internal/langserver/diagnostics"
 	"github.com/opentofu/tofu-ls/internal/langserver/notifier"
 	"github.com/opentofu/tofu-ls/internal/langserver/session"
@@ -25,9 +26,7 @@ func updateDiagnostics(features *Features, dNotifier *diagnostics.Notifier) noti
 
 			diags := diagnostics.NewDiagnostics()
 			diags.EmptyRootDiagnostic()
-
-			diags.Extend(features.Modules.Diagnostics(path))
-			diags.Extend(features.Variables.Diagnostics(path))
+			diags.Extend(dirDiagnostics(features, path))
 
 			dNotifier.PublishHCLDiags(ctx, path, diags)
 		}
@@ -35,6 +34,20 @@ func updateDiagnostics(features *Features, dNotifier *diagnostics.Notifier) noti
 	}
 }
 
+// refreshDiagnostics asks clients pulling diagnostics to pull them again,
+// since diagnostics aren't published to such clients.
+func refreshDiagnostics(clientRequester session.ClientCaller) notifier.Hook {
+	return func(ctx context.Context, changes state.Changes) error {
+		if changes.Diagnostics {
+			_, err := clientRequester.Callback(ctx, "workspace/diagnostic/refresh", nil)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
 func callRefreshClientCommand(clientRequester session.ClientCaller, commandId string) notifier.Hook {
 	return func(ctx context.Context, changes state.Changes) error {
 		// TODO: avoid triggering if module calls/providers did not change
@@ -72,28 +85,65 @@ func refreshCodeLens(clientRequester session.ClientCaller) notifier.Hook {
 	}
 }
 
+func refreshInitStatusCodeLens(clientRequester session.ClientCaller) notifier.Hook {
+	return func(ctx context.Context, changes state.Changes) error {
+		if changes.InstalledProviders || changes.ProviderRequirements {
+			_, err := clientRequester.Callback(ctx, "workspace/codeLens/refresh", nil)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
 func refreshSemanticTokens(clientRequester session.ClientCaller) notifier.Hook {
 	return func(ctx context.Context, changes state.Changes) error {
-		isOpen, err := notifier.RecordIsOpen(ctx)
+		changed, err := semanticTokensChanged(ctx, changes)
+		if err != nil || !changed {
+			return err
+		}
+
+		path, err := notifier.RecordPathFromContext(ctx)
 		if err != nil {
 			return err
 		}
 
-		localChanges := isOpen && (changes.TofuVersion || changes.CoreRequirements ||
-			changes.InstalledProviders || changes.ProviderRequirements)
+		_, err = clientRequester.Callback(ctx, "workspace/semanticTokens/refresh", nil)
+		if err != nil {
+			return fmt.Errorf("error refreshing %s: %s", path, err)
+		}
 
-		if localChanges || changes.ReferenceOrigins || changes.ReferenceTargets {
-			path, err := notifier.RecordPathFromContext(ctx)
-			if err != nil {
-				return err
-			}
+		return nil
+	}
+}
 
-			_, err = clientRequester.Callback(ctx, "workspace/semanticTokens/refresh", nil)
-			if err != nil {
-				return fmt.Errorf("error refreshing %s: %s", path, err)
-			}
+// expireSemanticTokens marks tokens of documents in the module as outdated,
+// when they may change without the documents being changed
+func expireSemanticTokens(docStore *state.DocumentStore) notifier.Hook {
+	return func(ctx context.Context, changes state.Changes) error {
+		changed, err := semanticTokensChanged(ctx, changes)
+		if err != nil || !changed {
+			return err
 		}
 
-		return nil
+		path, err := notifier.RecordPathFromContext(ctx)
+		if err != nil {
+			return err
+		}
+
+		return docStore.ExpireSemanticTokens(document.DirHandleFromPath(path))
+	}
+}
+
+func semanticTokensChanged(ctx context.Context, changes state.Changes) (bool, error) {
+	isOpen, err := notifier.RecordIsOpen(ctx)
+	if err != nil {
+		return false, err
 	}
+
+	localChanges := isOpen && (changes.TofuVersion || changes.CoreRequirements ||
+		changes.InstalledProviders || changes.ProviderRequirements)
+
+	return localChanges || changes.ReferenceOrigins || changes.ReferenceTargets, nil
 }
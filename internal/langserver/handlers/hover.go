@@ -43,6 +43,9 @@ func (svc *service) TextDocumentHover(ctx context.Context, params lsp.TextDocume
 	svc.logger.Printf("Looking for hover data at %q -> %#v", doc.Filename, pos)
 	hoverData, err := d.HoverAtPos(ctx, doc.Filename, pos)
 	svc.logger.Printf("received hover data: %#v", hoverData)
+	if planStateData, ok := svc.planStateHover(doc, pos); ok {
+		return ilsp.HoverData(appendHover(hoverData, planStateData), cc.TextDocument), nil
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,258 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/opentofu/tofu-ls/internal/document"
+)
+
+// resourceInstance is an instance of a managed resource
+// from the output of tofu show
+type resourceInstance struct {
+	Address string
+	// Values are the current attribute values, if any
+	Values          map[string]interface{}
+	SensitiveValues map[string]interface{}
+	// Actions are the planned actions, if a plan was shown
+	Actions tfjson.Actions
+}
+
+// planStateHover returns hover data for the header of a resource block at pos,
+// describing the current values and planned actions of all its instances,
+// as obtained from the root module via tofu show.
+func (svc *service) planStateHover(doc *document.Document, pos hcl.Pos) (*lang.HoverData, bool) {
+	f, ok := svc.moduleFiles(doc.Dir.Path())[doc.Filename]
+	if !ok {
+		return nil, false
+	}
+	block, ok := resourceHeaderAtPos(f, pos)
+	if !ok {
+		return nil, false
+	}
+
+	tfState, plan, err := svc.features.RootModules.PlanState(doc.Dir.Path())
+	if err != nil || (tfState == nil && plan == nil) {
+		return nil, false
+	}
+
+	instances := resourceInstances(tfState, plan, block.Labels[0], block.Labels[1])
+	content := resourceInstancesMarkdown(instances, plan != nil)
+
+	return &lang.HoverData{
+		Content: lang.Markdown(content),
+		Range:   hcl.RangeBetween(block.TypeRange, block.LabelRanges[1]),
+	}, true
+}
+
+// appendHover appends the content of hover data describing the same
+// position to other data, which may be nil
+func appendHover(data, other *lang.HoverData) *lang.HoverData {
+	if data == nil || data.Content.Value == "" {
+		return other
+	}
+	return &lang.HoverData{
+		Content: lang.Markdown(data.Content.Value + "\n\n---\n\n" + other.Content.Value),
+		Range:   data.Range,
+	}
+}
+
+func resourceHeaderAtPos(f *hcl.File, pos hcl.Pos) (*hclsyntax.Block, bool) {
+	body, ok := f.Body.(*hclsyntax.Body)
+	if !ok {
+		return nil, false
+	}
+	for _, block := range body.Blocks {
+		if block.Type != "resource" || len(block.Labels) != 2 {
+			continue
+		}
+		if hcl.RangeBetween(block.TypeRange, block.LabelRanges[1]).ContainsPos(pos) {
+			return block, true
+		}
+	}
+	return nil, false
+}
+
+// resourceInstances returns instances of the managed resource declared in
+// the root module, either from the state, or from the plan and its prior state
+func resourceInstances(tfState *tfjson.State, plan *tfjson.Plan, resourceType, name string) []resourceInstance {
+	instances := make([]resourceInstance, 0)
+
+	if plan != nil {
+		priorValues := make(map[string]*tfjson.StateResource, 0)
+		for _, resource := range stateResources(plan.PriorState, resourceType, name) {
+			priorValues[resource.Address] = resource
+		}
+
+		for _, rc := range plan.ResourceChanges {
+			if rc.ModuleAddress != "" || rc.Mode != tfjson.ManagedResourceMode ||
+				rc.Type != resourceType || rc.Name != name || rc.Change == nil {
+				continue
+			}
+			instance := resourceInstance{
+				Address: rc.Address,
+				Actions: rc.Change.Actions,
+			}
+			if prior, ok := priorValues[rc.Address]; ok {
+				instance.Values = prior.AttributeValues
+				instance.SensitiveValues = sensitiveValues(prior.SensitiveValues)
+			}
+			instances = append(instances, instance)
+		}
+	} else {
+		for _, resource := range stateResources(tfState, resourceType, name) {
+			instances = append(instances, resourceInstance{
+				Address:         resource.Address,
+				Values:          resource.AttributeValues,
+				SensitiveValues: sensitiveValues(resource.SensitiveValues),
+			})
+		}
+	}
+
+	sort.SliceStable(instances, func(i, j int) bool {
+		return instances[i].Address < instances[j].Address
+	})
+
+	return instances
+}
+
+func stateResources(tfState *tfjson.State, resourceType, name string) []*tfjson.StateResource {
+	resources := make([]*tfjson.StateResource, 0)
+	if tfState == nil || tfState.Values == nil || tfState.Values.RootModule == nil {
+		return resources
+	}
+
+	for _, resource := range tfState.Values.RootModule.Resources {
+		if resource.Mode == tfjson.ManagedResourceMode && resource.Type == resourceType && resource.Name == name {
+			resources = append(resources, resource)
+		}
+	}
+	return resources
+}
+
+func sensitiveValues(raw json.RawMessage) map[string]interface{} {
+	values := make(map[string]interface{}, 0)
+	if len(raw) == 0 {
+		return values
+	}
+	// Sensitive values which can't be parsed are hidden altogether
+	err := json.Unmarshal(raw, &values)
+	if err != nil {
+		return nil
+	}
+	return values
+}
+
+func resourceInstancesMarkdown(instances []resourceInstance, isPlan bool) string {
+	if len(instances) == 0 {
+		if isPlan {
+			return "Not part of the plan"
+		}
+		return "Not in the state"
+	}
+
+	var b strings.Builder
+	for i, instance := range instances {
+		if i > 0 {
+			b.WriteString("\n\n")
+		}
+
+		fmt.Fprintf(&b, "`%s`", instance.Address)
+		if isPlan {
+			fmt.Fprintf(&b, " %s", plannedActionsDescription(instance.Actions))
+		}
+
+		if len(instance.Values) == 0 {
+			continue
+		}
+		b.WriteString("\n```\n")
+		b.WriteString(attributeValues(instance.Values, instance.SensitiveValues))
+		b.WriteString("```")
+	}
+
+	return b.String()
+}
+
+func plannedActionsDescription(actions tfjson.Actions) string {
+	switch {
+	case actions.NoOp():
+		return "has no changes planned"
+	case actions.Create():
+		return "will be created"
+	case actions.Read():
+		return "will be read during apply"
+	case actions.Update():
+		return "will be updated in-place"
+	case actions.Replace():
+		return "must be replaced"
+	case actions.Delete():
+		return "will be destroyed"
+	}
+	return "will change"
+}
+
+// attributeValues formats non-null attribute values, one per line,
+// aligned like tofu fmt would align them
+func attributeValues(values, sensitive map[string]interface{}) string {
+	names := make([]string, 0, len(values))
+	width := 0
+	for name, value := range values {
+		if value == nil {
+			continue
+		}
+		names = append(names, name)
+		if len(name) > width {
+			width = len(name)
+		}
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		value := "(sensitive value)"
+		if sensitive != nil && isInsensitive(sensitive[name]) {
+			v, err := json.Marshal(values[name])
+			if err != nil {
+				continue
+			}
+			value = string(v)
+		}
+		fmt.Fprintf(&b, "%-*s = %s\n", width, name, value)
+	}
+	return b.String()
+}
+
+// isInsensitive returns true if the value from sensitive_values
+// of tofu show doesn't mark any part of an attribute as sensitive
+func isInsensitive(sensitive interface{}) bool {
+	switch v := sensitive.(type) {
+	case nil:
+		return true
+	case bool:
+		return !v
+	case []interface{}:
+		for _, elem := range v {
+			if !isInsensitive(elem) {
+				return false
+			}
+		}
+		return true
+	case map[string]interface{}:
+		for _, elem := range v {
+			if !isInsensitive(elem) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
@@ -0,0 +1,307 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/opentofu/tofu-ls/internal/langserver"
+	"github.com/opentofu/tofu-ls/internal/state"
+	"github.com/opentofu/tofu-ls/internal/tofu/exec"
+	"github.com/opentofu/tofu-ls/internal/walker"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHover_resourceState(t *testing.T) {
+	tmpDir := TempDir(t)
+
+	cfg := `resource "aws_instance" "web" {
+  count = 2
+  ami   = "ami-123"
+}
+`
+	writeTestFile(t, filepath.Join(tmpDir.Path(), "main.tf"), cfg)
+	writeTestFile(t, filepath.Join(tmpDir.Path(), ".terraform.lock.hcl"), "")
+
+	tfState := &tfjson.State{
+		FormatVersion: "1.0",
+		Values: &tfjson.StateValues{
+			RootModule: &tfjson.StateModule{
+				Resources: []*tfjson.StateResource{
+					{
+						Address: "aws_instance.web[1]",
+						Mode:    tfjson.ManagedResourceMode,
+						Type:    "aws_instance",
+						Name:    "web",
+						Index:   1,
+						AttributeValues: map[string]interface{}{
+							"ami":      "ami-123",
+							"password": "secret",
+						},
+						SensitiveValues: json.RawMessage(`{"password":true}`),
+					},
+					{
+						Address: "aws_instance.web[0]",
+						Mode:    tfjson.ManagedResourceMode,
+						Type:    "aws_instance",
+						Name:    "web",
+						Index:   0,
+						AttributeValues: map[string]interface{}{
+							"ami":           "ami-123",
+							"instance_type": "t2.micro",
+							"tags":          nil,
+						},
+						SensitiveValues: json.RawMessage(`{}`),
+					},
+					{
+						Address: "data.aws_instance.web",
+						Mode:    tfjson.DataResourceMode,
+						Type:    "aws_instance",
+						Name:    "web",
+					},
+				},
+			},
+		},
+	}
+
+	ss, err := state.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wc := walker.NewWalkerCollector()
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TofuCalls: &exec.TofuMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				tmpDir.Path(): append(validTfMockCalls(), &mock.Call{
+					Method:        "Show",
+					Repeatability: 1,
+					Arguments: []interface{}{
+						mock.AnythingOfType(""),
+					},
+					ReturnArguments: []interface{}{
+						tfState,
+						nil,
+					},
+				}, &mock.Call{
+					Method:        "Show",
+					Repeatability: 1,
+					Arguments: []interface{}{
+						mock.AnythingOfType(""),
+					},
+					ReturnArguments: []interface{}{
+						&tfjson.State{
+							FormatVersion: "1.0",
+							Values: &tfjson.StateValues{
+								RootModule: &tfjson.StateModule{
+									Resources: tfState.Values.RootModule.Resources[1:],
+								},
+							},
+						},
+						nil,
+					},
+				}),
+			},
+		},
+		StateStore:      ss,
+		WalkerCollector: wc,
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+		"capabilities": {
+			"textDocument": {
+				"hover": {
+					"contentFormat": ["markdown"]
+				}
+			}
+		},
+		"initializationOptions": {
+			"experimentalFeatures": {
+				"planStateHover": {
+					"enable": true
+				}
+			}
+		},
+		"rootUri": %q,
+		"processId": 12345
+	}`, tmpDir.URI)})
+	waitForWalkerPath(t, ss, wc, tmpDir)
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "opentofu",
+			"text": %q,
+			"uri": "%s/main.tf"
+		}
+	}`, cfg, tmpDir.URI)})
+	waitForAllJobs(t, ss)
+
+	content := "\"aws_instance\" (type)\n\nResource Type\n\n---\n\n" +
+		"`aws_instance.web[0]`\n```\nami           = \"ami-123\"\ninstance_type = \"t2.micro\"\n```\n\n" +
+		"`aws_instance.web[1]`\n```\nami      = \"ami-123\"\npassword = (sensitive value)\n```"
+
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/hover",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/main.tf"
+			},
+			"position": {
+				"character": 14,
+				"line": 0
+			}
+		}`, tmpDir.URI),
+	}, fmt.Sprintf(`{
+		"jsonrpc": "2.0",
+		"id": 3,
+		"result": {
+			"contents": {
+				"kind": "markdown",
+				"value": %q
+			},
+			"range": {
+				"start": { "line": 0, "character": 9 },
+				"end": { "line": 0, "character": 23 }
+			}
+		}
+	}`, content))
+
+	// The state is obtained again once it changes
+	writeTestFile(t, filepath.Join(tmpDir.Path(), "terraform.tfstate"), "{}")
+	ls.Call(t, &langserver.CallRequest{
+		Method: "workspace/didChangeWatchedFiles",
+		ReqParams: fmt.Sprintf(`{
+		"changes": [
+			{
+				"uri": "%s/terraform.tfstate",
+				"type": 1
+			}
+		]
+	}`, tmpDir.URI)})
+	waitForAllJobs(t, ss)
+
+	content = "\"aws_instance\" (type)\n\nResource Type\n\n---\n\n" +
+		"`aws_instance.web[0]`\n```\nami           = \"ami-123\"\ninstance_type = \"t2.micro\"\n```"
+
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/hover",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/main.tf"
+			},
+			"position": {
+				"character": 14,
+				"line": 0
+			}
+		}`, tmpDir.URI),
+	}, fmt.Sprintf(`{
+		"jsonrpc": "2.0",
+		"id": 5,
+		"result": {
+			"contents": {
+				"kind": "markdown",
+				"value": %q
+			},
+			"range": {
+				"start": { "line": 0, "character": 9 },
+				"end": { "line": 0, "character": 23 }
+			}
+		}
+	}`, content))
+}
+
+func TestResourceInstances_plan(t *testing.T) {
+	plan := &tfjson.Plan{
+		PriorState: &tfjson.State{
+			Values: &tfjson.StateValues{
+				RootModule: &tfjson.StateModule{
+					Resources: []*tfjson.StateResource{
+						{
+							Address: "aws_instance.web",
+							Mode:    tfjson.ManagedResourceMode,
+							Type:    "aws_instance",
+							Name:    "web",
+							AttributeValues: map[string]interface{}{
+								"ami": "ami-123",
+							},
+						},
+					},
+				},
+			},
+		},
+		ResourceChanges: []*tfjson.ResourceChange{
+			{
+				Address: "aws_instance.web",
+				Mode:    tfjson.ManagedResourceMode,
+				Type:    "aws_instance",
+				Name:    "web",
+				Change: &tfjson.Change{
+					Actions: tfjson.Actions{tfjson.ActionUpdate},
+				},
+			},
+			{
+				Address:       "module.app.aws_instance.web",
+				ModuleAddress: "module.app",
+				Mode:          tfjson.ManagedResourceMode,
+				Type:          "aws_instance",
+				Name:          "web",
+				Change: &tfjson.Change{
+					Actions: tfjson.Actions{tfjson.ActionCreate},
+				},
+			},
+			{
+				Address: "aws_instance.db",
+				Mode:    tfjson.ManagedResourceMode,
+				Type:    "aws_instance",
+				Name:    "db",
+				Change: &tfjson.Change{
+					Actions: tfjson.Actions{tfjson.ActionDelete, tfjson.ActionCreate},
+				},
+			},
+		},
+	}
+
+	testCases := []struct {
+		name            string
+		expectedContent string
+	}{
+		{
+			"web",
+			"`aws_instance.web` will be updated in-place\n```\nami = \"ami-123\"\n```",
+		},
+		{
+			"db",
+			"`aws_instance.db` must be replaced",
+		},
+		{
+			"cache",
+			"Not part of the plan",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			instances := resourceInstances(nil, plan, "aws_instance", tc.name)
+			content := resourceInstancesMarkdown(instances, true)
+			if diff := cmp.Diff(tc.expectedContent, content); diff != "" {
+				t.Fatalf("unexpected content: %s", diff)
+			}
+		})
+	}
+}
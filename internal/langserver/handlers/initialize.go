@@ -77,16 +77,36 @@ func (svc *service) Initialize(ctx context.Context, params lsp.InitializeParams)
 	caps := ilsp.SemanticTokensClientCapabilities{
 		SemanticTokensClientCapabilities: clientCaps.TextDocument.SemanticTokens,
 	}
-	semanticTokensOpts := lsp.SemanticTokensOptions{
+	semanticTokensOpts := ilsp.SemanticTokensOptions{
 		Legend: lsp.SemanticTokensLegend{
 			TokenTypes:     ilsp.TokenTypesLegend(stCaps.TokenTypes).AsStrings(),
 			TokenModifiers: ilsp.TokenModifiersLegend(stCaps.TokenModifiers).AsStrings(),
 		},
-		Full: caps.FullRequest(),
+	}
+	if caps.FullDeltaRequest() {
+		semanticTokensOpts.Full = lsp.PFullESemanticTokensOptions{Delta: true}
+	} else if caps.FullRequest() {
+		semanticTokensOpts.Full = true
+	}
+	if caps.RangeRequest() {
+		semanticTokensOpts.Range = true
 	}
 
 	serverCaps.Capabilities.SemanticTokensProvider = semanticTokensOpts
 
+	if clientCaps.TextDocument.Diagnostic != nil {
+		serverCaps.Capabilities.DiagnosticProvider = lsp.DiagnosticOptions{
+			InterFileDependencies: true,
+			WorkspaceDiagnostics:  true,
+		}
+	}
+
+	if clientCaps.TextDocument.Rename.PrepareSupport {
+		serverCaps.Capabilities.RenameProvider = lsp.RenameOptions{
+			PrepareProvider: true,
+		}
+	}
+
 	// set commandPrefix for session
 	lsctx.SetCommandPrefix(ctx, out.Options.CommandPrefix)
 	// apply prefix to executeCommand handler names
@@ -187,9 +207,14 @@ func initializeResult(ctx context.Context) lsp.InitializeResult {
 			DefinitionProvider:         true,
 			CodeLensProvider:           &lsp.CodeLensOptions{},
 			ReferencesProvider:         true,
+			DocumentHighlightProvider:  true,
+			RenameProvider:             true,
 			HoverProvider:              true,
 			DocumentFormattingProvider: true,
+			FoldingRangeProvider:       true,
+			CallHierarchyProvider:      true,
 			DocumentSymbolProvider:     true,
+			InlayHintProvider:          true,
 			WorkspaceSymbolProvider:    true,
 			Workspace: lsp.Workspace6Gn{
 				WorkspaceFolders: lsp.WorkspaceFolders5Gn{
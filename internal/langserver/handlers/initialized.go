@@ -10,6 +10,8 @@ import (
 
 	"github.com/creachadair/jrpc2"
 	"github.com/hashicorp/go-uuid"
+	lsctx "github.com/opentofu/tofu-ls/internal/context"
+	rmAst "github.com/opentofu/tofu-ls/internal/features/rootmodules/ast"
 	ilsp "github.com/opentofu/tofu-ls/internal/lsp"
 	lsp "github.com/opentofu/tofu-ls/internal/protocol"
 	"github.com/opentofu/tofu-ls/internal/tofu/datadir"
@@ -45,6 +47,17 @@ func (svc *service) setupWatchedFiles(ctx context.Context, caps lsp.DidChangeWat
 		}
 	}
 
+	expFeatures, err := lsctx.ExperimentalFeatures(ctx)
+	if err == nil && expFeatures.PlanStateHover.Enable {
+		// Plan and state shown on hover are obtained again when they change
+		for _, pattern := range rmAst.PlanStateGlobPatterns(expFeatures.PlanStateHover.PlanFile) {
+			watchers = append(watchers, lsp.FileSystemWatcher{
+				GlobPattern: pattern,
+				Kind:        kindFromEventType(datadir.AnyEventType),
+			})
+		}
+	}
+
 	srv := jrpc2.ServerFromContext(ctx)
 	_, err = srv.Callback(ctx, "client/registerCapability", lsp.RegistrationParams{
 		Registrations: []lsp.Registration{
@@ -0,0 +1,165 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package handlers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	idecoder "github.com/opentofu/tofu-ls/internal/decoder"
+	"github.com/opentofu/tofu-ls/internal/document"
+	ilsp "github.com/opentofu/tofu-ls/internal/lsp"
+	"github.com/opentofu/tofu-ls/internal/pathcmp"
+	lsp "github.com/opentofu/tofu-ls/internal/protocol"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func (svc *service) InlayHint(ctx context.Context, params lsp.InlayHintParams) ([]ilsp.InlayHint, error) {
+	hints := make([]ilsp.InlayHint, 0)
+
+	dh := ilsp.HandleFromDocumentURI(params.TextDocument.URI)
+	doc, err := svc.stateStore.DocumentStore.GetDocument(dh)
+	if err != nil {
+		return hints, err
+	}
+	if !ilsp.IsValidConfigLanguage(doc.LanguageID) {
+		return hints, nil
+	}
+
+	jobIds, err := svc.stateStore.JobStore.ListIncompleteJobsForDir(dh.Dir)
+	if err != nil {
+		return hints, err
+	}
+	svc.stateStore.JobStore.WaitForJobs(ctx, jobIds...)
+
+	rng, err := documentRange(doc, params.Range)
+	if err != nil {
+		return hints, err
+	}
+
+	pathCtx, err := svc.pathReader.PathContext(lang.Path{
+		Path:       doc.Dir.Path(),
+		LanguageID: ilsp.OpenTofu.String(),
+	})
+	if err != nil {
+		return hints, err
+	}
+
+	for _, hint := range idecoder.InlayHints(pathCtx, doc.Filename, rng, svc.variableValues(ctx, doc.Dir), svc.moduleVersions(doc.Dir)) {
+		pos := ilsp.HCLPosToLSP(hint.Pos)
+		hints = append(hints, ilsp.InlayHint{
+			InlayHint: lsp.InlayHint{
+				Position: &pos,
+				Label: []lsp.InlayHintLabelPart{
+					{Value: hint.Label},
+				},
+				PaddingLeft: true,
+			},
+			Tooltip: hint.Tooltip,
+		})
+	}
+
+	return hints, nil
+}
+
+// variableValues returns values which input variables of the module
+// resolve to, i.e. values from autoloaded variable definitions files,
+// or default values from variable declarations.
+// Values of sensitive variables are left out.
+//
+// Variables of modules called by other modules are set by the callers,
+// possibly to different values for each call, so no values are returned.
+// The same applies until it is known whether the module is called.
+func (svc *service) variableValues(ctx context.Context, dir document.DirHandle) map[string]idecoder.VariableValue {
+	values := make(map[string]idecoder.VariableValue)
+	called, ok := svc.isCalledModule(ctx, dir.Path())
+	if called || !ok {
+		return values
+	}
+
+	inputs, err := svc.features.Modules.ModuleInputs(dir.Path())
+	if err != nil {
+		return values
+	}
+	for name, input := range inputs {
+		if input.IsSensitive || input.DefaultValue == cty.NilVal || !input.DefaultValue.IsWhollyKnown() {
+			continue
+		}
+		values[name] = idecoder.VariableValue{
+			Value:       string(hclwrite.TokensForValue(input.DefaultValue).Bytes()),
+			Description: "Default value",
+		}
+	}
+
+	varsValues, err := svc.features.Variables.AutoloadedValues(dir.Path())
+	if err != nil {
+		// There may be no variable definitions files
+		return values
+	}
+	for name, value := range varsValues {
+		if input, ok := inputs[name]; !ok || input.IsSensitive {
+			continue
+		}
+		values[name] = idecoder.VariableValue{
+			Value:       value.Source,
+			Description: fmt.Sprintf("Value from %s", value.Filename),
+		}
+	}
+
+	return values
+}
+
+// isCalledModule returns true if the module at modPath is called by any
+// other module, based on modules installed by root modules and module
+// calls declared in indexed modules. The second return value is false
+// if metadata of any indexed module isn't loaded yet.
+func (svc *service) isCalledModule(ctx context.Context, modPath string) (bool, bool) {
+	callers, err := svc.features.RootModules.CallersOfModule(modPath)
+	if err != nil {
+		return false, false
+	}
+	if len(callers) > 0 {
+		return true, true
+	}
+
+	for _, path := range svc.features.Modules.Paths(ctx) {
+		_, ready, err := svc.features.Modules.MetadataReady(document.DirHandleFromPath(path.Path))
+		if err != nil || !ready {
+			return false, false
+		}
+
+		declared, err := svc.features.Modules.DeclaredModuleCalls(path.Path)
+		if err != nil {
+			return false, false
+		}
+		for _, mc := range declared {
+			mcPath, ok := svc.features.Modules.ModuleCallPath(path.Path, mc)
+			if ok && pathcmp.PathEquals(mcPath, modPath) {
+				return true, true
+			}
+		}
+	}
+
+	return false, true
+}
+
+// moduleVersions returns versions of installed modules called
+// from the module, if it is a root module
+func (svc *service) moduleVersions(dir document.DirHandle) map[string]string {
+	versions := make(map[string]string)
+
+	calls, err := svc.features.RootModules.InstalledModuleCalls(dir.Path())
+	if err != nil {
+		return versions
+	}
+	for name, call := range calls {
+		if call.Version != nil {
+			versions[name] = call.Version.String()
+		}
+	}
+
+	return versions
+}
@@ -0,0 +1,235 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package handlers
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/opentofu/tofu-ls/internal/document"
+	"github.com/opentofu/tofu-ls/internal/langserver"
+	"github.com/opentofu/tofu-ls/internal/state"
+	"github.com/opentofu/tofu-ls/internal/tofu/exec"
+	"github.com/opentofu/tofu-ls/internal/walker"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestLangServer_inlayHint(t *testing.T) {
+	tmpDir := TempDir(t)
+
+	cfg := `variable "instance_type" {
+  default = "t3.micro"
+}
+
+variable "region" {
+  default = "eu-west-1"
+}
+
+variable "password" {
+  sensitive = true
+  default   = "secret"
+}
+
+module "vpc" {
+  source  = "terraform-aws-modules/vpc/aws"
+  version = "~> 5.0"
+}
+
+output "test" {
+  value = [var.instance_type, var.region, var.password]
+}
+`
+	err := os.WriteFile(filepath.Join(tmpDir.Path(), "main.tf"), []byte(cfg), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(filepath.Join(tmpDir.Path(), "terraform.tfvars"), []byte("region = \"us-east-1\"\n"), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	modulesDir := filepath.Join(tmpDir.Path(), ".terraform", "modules")
+	err = os.MkdirAll(filepath.Join(modulesDir, "vpc"), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	manifest := `{
+    "Modules": [
+        {
+            "Key": "",
+            "Source": "",
+            "Dir": "."
+        },
+        {
+            "Key": "vpc",
+            "Source": "registry.opentofu.org/terraform-aws-modules/vpc/aws",
+            "Version": "5.1.2",
+            "Dir": ".terraform/modules/vpc"
+        }
+    ]
+}`
+	err = os.WriteFile(filepath.Join(modulesDir, "modules.json"), []byte(manifest), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ss, err := state.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wc := walker.NewWalkerCollector()
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TofuCalls: &exec.TofuMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				tmpDir.Path(): validTfMockCalls(),
+			},
+		},
+		StateStore:      ss,
+		WalkerCollector: wc,
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+	    "capabilities": {},
+	    "rootUri": %q,
+	    "processId": 12345
+	}`, tmpDir.URI)})
+	waitForWalkerPath(t, ss, wc, tmpDir)
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "opentofu",
+			"text": %q,
+			"uri": "%s/main.tf"
+		}
+	}`, cfg, tmpDir.URI)})
+	waitForAllJobs(t, ss)
+
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/inlayHint",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": { "uri": "%s/main.tf" },
+			"range": {
+				"start": { "line": 0, "character": 0 },
+				"end": { "line": 21, "character": 0 }
+			}
+		}`, tmpDir.URI)}, `{
+			"jsonrpc": "2.0",
+			"id": 3,
+			"result": [
+				{
+					"position": { "line": 14, "character": 43 },
+					"label": [{ "value": "v5.1.2" }],
+					"paddingLeft": true,
+					"tooltip": "Installed version"
+				},
+				{
+					"position": { "line": 19, "character": 28 },
+					"label": [{ "value": "= \"t3.micro\"" }],
+					"paddingLeft": true,
+					"tooltip": "Default value"
+				},
+				{
+					"position": { "line": 19, "character": 40 },
+					"label": [{ "value": "= \"us-east-1\"" }],
+					"paddingLeft": true,
+					"tooltip": "Value from terraform.tfvars"
+				}
+			]
+		}`)
+}
+
+func TestLangServer_inlayHint_childModule(t *testing.T) {
+	tmpDir := TempDir(t)
+	appDir := document.DirHandleFromPath(filepath.Join(tmpDir.Path(), "modules", "app"))
+
+	writeTestFile(t, filepath.Join(tmpDir.Path(), "main.tf"), `module "app" {
+  source = "./modules/app"
+
+  instance_type = "t3.large"
+}
+`)
+	appCfg := `variable "instance_type" {
+  default = "t3.micro"
+}
+
+output "instance_type" {
+  value = var.instance_type
+}
+`
+	writeTestFile(t, filepath.Join(appDir.Path(), "main.tf"), appCfg)
+	writeTestFile(t, filepath.Join(tmpDir.Path(), ".terraform", "modules", "modules.json"), `{"Modules":[
+	{"Key":"","Source":"","Dir":"."},
+	{"Key":"app","Source":"./modules/app","Dir":"modules/app"}
+]}`)
+
+	ss, err := state.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wc := walker.NewWalkerCollector()
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TofuCalls: &exec.TofuMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				tmpDir.Path(): validTfMockCalls(),
+				appDir.Path(): validTfMockCalls(),
+			},
+		},
+		StateStore:      ss,
+		WalkerCollector: wc,
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+	    "capabilities": {},
+	    "rootUri": %q,
+	    "processId": 12345
+	}`, tmpDir.URI)})
+	waitForWalkerPath(t, ss, wc, tmpDir)
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "opentofu",
+			"text": %q,
+			"uri": "%s/main.tf"
+		}
+	}`, appCfg, appDir.URI)})
+	waitForAllJobs(t, ss)
+
+	// The default value would be misleading, since the caller sets the variable
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/inlayHint",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": { "uri": "%s/main.tf" },
+			"range": {
+				"start": { "line": 0, "character": 0 },
+				"end": { "line": 7, "character": 0 }
+			}
+		}`, appDir.URI)}, `{
+			"jsonrpc": "2.0",
+			"id": 3,
+			"result": []
+		}`)
+}
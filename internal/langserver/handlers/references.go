@@ -7,10 +7,13 @@ package handlers
 
 import (
 	"context"
+	"sort"
 
 	"github.com/hashicorp/hcl-lang/lang"
 	ilsp "github.com/opentofu/tofu-ls/internal/lsp"
+	"github.com/opentofu/tofu-ls/internal/pathcmp"
 	lsp "github.com/opentofu/tofu-ls/internal/protocol"
+	"github.com/opentofu/tofu-ls/internal/tofu/datadir"
 )
 
 func (svc *service) References(ctx context.Context, params lsp.ReferenceParams) ([]lsp.Location, error) {
@@ -37,8 +40,86 @@ func (svc *service) References(ctx context.Context, params lsp.ReferenceParams)
 		Path:       doc.Dir.Path(),
 		LanguageID: ilsp.ParseLanguageID(doc.LanguageID).String(),
 	}
-	// TODO? maybe kick off indexing of the whole workspace here
+	if path.LanguageID != ilsp.OpenTofu.String() {
+		// TODO? maybe kick off indexing of the whole workspace here
+		origins := svc.decoder.ReferenceOriginsTargetingPos(path, doc.Filename, pos)
+		return ilsp.RefOriginsToLocations(origins), nil
+	}
+
+	if f, ok := svc.moduleFiles(path.Path)[doc.Filename]; ok {
+		if _, ok := labeledBlockAtPos(f, "output", pos); ok {
+			// Outputs are referenced from other modules, which
+			// may not have been decoded unless they're open
+			err = svc.decodeModuleCallers(ctx, path.Path)
+			if err != nil {
+				svc.logger.Printf("failed to decode callers of %q: %s", path.Path, err)
+			}
+		}
+	}
+
 	origins := svc.decoder.ReferenceOriginsTargetingPos(path, doc.Filename, pos)
 
+	// Origins in other modules, such as module.<name>.<output>, target
+	// the module under the language ID used by the schema, so they
+	// only match a path with that language ID.
+	origins = append(origins, svc.decoder.ReferenceOriginsTargetingPos(lang.Path{
+		Path:       path.Path,
+		LanguageID: ilsp.Terraform.String(),
+	}, doc.Filename, pos)...)
+	sort.SliceStable(origins, func(i, j int) bool {
+		if origins[i].Path.Path != origins[j].Path.Path {
+			return origins[i].Path.Path < origins[j].Path.Path
+		}
+		if origins[i].Range.Filename != origins[j].Range.Filename {
+			return origins[i].Range.Filename < origins[j].Range.Filename
+		}
+		return origins[i].Range.Start.Byte < origins[j].Range.Start.Byte
+	})
+
 	return ilsp.RefOriginsToLocations(origins), nil
 }
+
+// decodeModuleCallers decodes all modules calling the module at modPath,
+// directly or through other local modules. Callers are found through
+// the module manifests of root modules the module is installed in.
+func (svc *service) decodeModuleCallers(ctx context.Context, modPath string) error {
+	manifestIds, err := svc.features.RootModules.ParseModuleManifests(ctx)
+	if err != nil {
+		return err
+	}
+	err = svc.stateStore.JobStore.WaitForJobs(ctx, manifestIds...)
+	if err != nil {
+		return err
+	}
+
+	rootPaths, err := svc.features.RootModules.CallersOfModule(modPath)
+	if err != nil {
+		return err
+	}
+
+	paths := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, rootPath := range rootPaths {
+		paths = append(paths, rootPath)
+
+		installed, err := svc.features.RootModules.InstalledModuleCalls(rootPath)
+		if err != nil {
+			continue
+		}
+		for _, mc := range installed {
+			// Modules installed into the data directory
+			// cannot call modules in the workspace
+			if datadir.IsInstalledModulePath(mc.Path) || pathcmp.PathEquals(mc.Path, modPath) || seen[mc.Path] {
+				continue
+			}
+			seen[mc.Path] = true
+			paths = append(paths, mc.Path)
+		}
+	}
+
+	jobIds, err := svc.features.Modules.DecodeModules(ctx, paths)
+	if err != nil {
+		return err
+	}
+	return svc.stateStore.JobStore.WaitForJobs(ctx, jobIds...)
+}
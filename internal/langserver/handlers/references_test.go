@@ -240,3 +240,128 @@ variable "instances" {
 			]
 		}`, rootHandle.URI))
 }
+
+func TestReferences_outputInCallers(t *testing.T) {
+	tmpDir := TempDir(t)
+	appDir := document.DirHandleFromPath(filepath.Join(tmpDir.Path(), "modules", "app"))
+	sharedDir := document.DirHandleFromPath(filepath.Join(tmpDir.Path(), "modules", "shared"))
+
+	writeTestFile(t, filepath.Join(tmpDir.Path(), "main.tf"), `module "app" {
+  source = "./modules/app"
+}
+
+module "shared" {
+  source = "./modules/shared"
+}
+
+output "shared_name" {
+  value = module.shared.name
+}
+`)
+	writeTestFile(t, filepath.Join(appDir.Path(), "main.tf"), `module "shared" {
+  source = "../shared"
+}
+
+locals {
+  name = module.shared.name
+}
+`)
+	sharedCfg := `output "name" {
+  value = "shared"
+}
+`
+	writeTestFile(t, filepath.Join(sharedDir.Path(), "main.tf"), sharedCfg)
+	writeTestFile(t, filepath.Join(tmpDir.Path(), ".terraform", "modules", "modules.json"), `{"Modules":[
+	{"Key":"","Source":"","Dir":"."},
+	{"Key":"app","Source":"./modules/app","Dir":"modules/app"},
+	{"Key":"app.shared","Source":"../shared","Dir":"modules/shared"},
+	{"Key":"shared","Source":"./modules/shared","Dir":"modules/shared"}
+]}`)
+
+	ss, err := state.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wc := walker.NewWalkerCollector()
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TofuCalls: &exec.TofuMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				tmpDir.Path():    validTfMockCalls(),
+				appDir.Path():    validTfMockCalls(),
+				sharedDir.Path(): validTfMockCalls(),
+			},
+		},
+		StateStore:      ss,
+		WalkerCollector: wc,
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+			"capabilities": {},
+			"rootUri": %q,
+			"processId": 12345
+	}`, tmpDir.URI)})
+	waitForWalkerPath(t, ss, wc, tmpDir)
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "opentofu",
+			"text": %q,
+			"uri": "%s/main.tf"
+		}
+	}`, sharedCfg, sharedDir.URI)})
+	waitForAllJobs(t, ss)
+
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/references",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/main.tf"
+			},
+			"position": {
+				"line": 0,
+				"character": 9
+			}
+		}`, sharedDir.URI)}, fmt.Sprintf(`{
+			"jsonrpc": "2.0",
+			"id": 3,
+			"result": [
+				{
+					"uri": "%s/main.tf",
+					"range": {
+						"start": {
+							"line": 9,
+							"character": 10
+						},
+						"end": {
+							"line": 9,
+							"character": 28
+						}
+					}
+				},
+				{
+					"uri": "%s/main.tf",
+					"range": {
+						"start": {
+							"line": 5,
+							"character": 9
+						},
+						"end": {
+							"line": 5,
+							"character": 27
+						}
+					}
+				}
+			]
+		}`, tmpDir.URI, appDir.URI))
+}
@@ -0,0 +1,111 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package handlers
+
+import (
+	"context"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/hashicorp/hcl-lang/lang"
+	idecoder "github.com/opentofu/tofu-ls/internal/decoder"
+	ilsp "github.com/opentofu/tofu-ls/internal/lsp"
+	"github.com/opentofu/tofu-ls/internal/pathcmp"
+	lsp "github.com/opentofu/tofu-ls/internal/protocol"
+)
+
+func (svc *service) PrepareRename(ctx context.Context, params lsp.PrepareRenameParams) (*lsp.PrepareRenameResult, error) {
+	rt, err := svc.renameTarget(ctx, params.TextDocumentPositionParams)
+	if err != nil {
+		return nil, err
+	}
+	if rt == nil {
+		// Nothing we know how to rename
+		return nil, nil
+	}
+
+	return &lsp.PrepareRenameResult{
+		Range:       ilsp.HCLRangeToLSP(rt.NameRange),
+		Placeholder: rt.Name(),
+	}, nil
+}
+
+func (svc *service) Rename(ctx context.Context, params lsp.RenameParams) (*lsp.WorkspaceEdit, error) {
+	rt, err := svc.renameTarget(ctx, lsp.TextDocumentPositionParams{
+		TextDocument: params.TextDocument,
+		Position:     params.Position,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if rt == nil {
+		return nil, jrpc2.Errorf(jrpc2.InvalidRequest, "The element can't be renamed")
+	}
+	if rt.IsOutput() {
+		rt.ModuleCalls = svc.moduleCallsOf(ctx, rt.Path.Path)
+	}
+
+	edits, err := idecoder.Rename(ctx, svc.pathReader, rt, params.NewName)
+	if err != nil {
+		return nil, jrpc2.Errorf(jrpc2.InvalidParams, "%s", err)
+	}
+
+	return ilsp.WorkspaceEditFromTextEdits(edits), nil
+}
+
+func (svc *service) renameTarget(ctx context.Context, params lsp.TextDocumentPositionParams) (*idecoder.RenameTarget, error) {
+	dh := ilsp.HandleFromDocumentURI(params.TextDocument.URI)
+	doc, err := svc.stateStore.DocumentStore.GetDocument(dh)
+	if err != nil {
+		return nil, err
+	}
+
+	jobIds, err := svc.stateStore.JobStore.ListIncompleteJobsForDir(dh.Dir)
+	if err != nil {
+		return nil, err
+	}
+	svc.stateStore.JobStore.WaitForJobs(ctx, jobIds...)
+
+	pos, err := ilsp.HCLPositionFromLspPosition(params.Position, doc)
+	if err != nil {
+		return nil, err
+	}
+
+	path := lang.Path{
+		Path:       doc.Dir.Path(),
+		LanguageID: ilsp.ParseLanguageID(doc.LanguageID).String(),
+	}
+
+	return idecoder.PrepareRename(svc.pathReader, path, doc.Filename, pos)
+}
+
+// moduleCallsOf returns the module blocks calling the module at modPath.
+// Calling modules are decoded first, since they may not be open.
+func (svc *service) moduleCallsOf(ctx context.Context, modPath string) []idecoder.ModuleCall {
+	calls := make([]idecoder.ModuleCall, 0)
+
+	err := svc.decodeModuleCallers(ctx, modPath)
+	if err != nil {
+		svc.logger.Printf("failed to decode callers of %q: %s", modPath, err)
+	}
+
+	for _, path := range svc.features.Modules.Paths(ctx) {
+		declared, err := svc.features.Modules.DeclaredModuleCalls(path.Path)
+		if err != nil {
+			continue
+		}
+
+		for _, mc := range declared {
+			mcPath, ok := svc.features.Modules.ModuleCallPath(path.Path, mc)
+			if !ok || !pathcmp.PathEquals(mcPath, modPath) {
+				continue
+			}
+			calls = append(calls, idecoder.ModuleCall{
+				Path: path,
+				Name: mc.LocalName,
+			})
+		}
+	}
+
+	return calls
+}
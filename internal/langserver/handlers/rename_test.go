@@ -0,0 +1,332 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package handlers
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/go-version"
+	"github.com/opentofu/tofu-ls/internal/document"
+	"github.com/opentofu/tofu-ls/internal/langserver"
+	"github.com/opentofu/tofu-ls/internal/state"
+	"github.com/opentofu/tofu-ls/internal/tofu/exec"
+	"github.com/opentofu/tofu-ls/internal/walker"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRename_variable(t *testing.T) {
+	tmpDir := TempDir(t)
+
+	ss, err := state.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wc := walker.NewWalkerCollector()
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TofuCalls: &exec.TofuMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				tmpDir.Path(): {
+					{
+						Method:        "Version",
+						Repeatability: 1,
+						Arguments: []interface{}{
+							mock.AnythingOfType(""),
+						},
+						ReturnArguments: []interface{}{
+							version.Must(version.NewVersion("0.12.0")),
+							nil,
+							nil,
+						},
+					},
+					{
+						Method:        "GetExecPath",
+						Repeatability: 1,
+						ReturnArguments: []interface{}{
+							"",
+						},
+					},
+				},
+			},
+		},
+		StateStore:      ss,
+		WalkerCollector: wc,
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+	    "capabilities": {
+	    	"textDocument": {
+	    		"rename": {
+	    			"prepareSupport": true
+	    		}
+	    	}
+	    },
+	    "rootUri": %q,
+	    "processId": 12345
+	}`, tmpDir.URI)})
+	waitForWalkerPath(t, ss, wc, tmpDir)
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "opentofu",
+			"text": `+fmt.Sprintf("%q",
+			`variable "test" {
+}
+
+output "foo" {
+  value = "${var.test}-${var.test}"
+}`)+`,
+			"uri": "%s/main.tf"
+		}
+	}`, tmpDir.URI)})
+	waitForAllJobs(t, ss)
+
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/prepareRename",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/main.tf"
+			},
+			"position": {
+				"line": 4,
+				"character": 18
+			}
+		}`, tmpDir.URI)}, `{
+			"jsonrpc": "2.0",
+			"id": 3,
+			"result": {
+				"range": {
+					"start": {
+						"line": 4,
+						"character": 17
+					},
+					"end": {
+						"line": 4,
+						"character": 21
+					}
+				},
+				"placeholder": "test"
+			}
+		}`)
+
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/rename",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/main.tf"
+			},
+			"position": {
+				"line": 0,
+				"character": 11
+			},
+			"newName": "renamed"
+		}`, tmpDir.URI)}, fmt.Sprintf(`{
+			"jsonrpc": "2.0",
+			"id": 4,
+			"result": {
+				"changes": {
+					"%s/main.tf": [
+						{
+							"range": {
+								"start": {
+									"line": 0,
+									"character": 10
+								},
+								"end": {
+									"line": 0,
+									"character": 14
+								}
+							},
+							"newText": "renamed"
+						},
+						{
+							"range": {
+								"start": {
+									"line": 4,
+									"character": 17
+								},
+								"end": {
+									"line": 4,
+									"character": 21
+								}
+							},
+							"newText": "renamed"
+						},
+						{
+							"range": {
+								"start": {
+									"line": 4,
+									"character": 29
+								},
+								"end": {
+									"line": 4,
+									"character": 33
+								}
+							},
+							"newText": "renamed"
+						}
+					]
+				}
+			}
+		}`, tmpDir.URI))
+}
+
+func TestRename_outputInCallers(t *testing.T) {
+	tmpDir := TempDir(t)
+	appDir := document.DirHandleFromPath(filepath.Join(tmpDir.Path(), "modules", "app"))
+	sharedDir := document.DirHandleFromPath(filepath.Join(tmpDir.Path(), "modules", "shared"))
+
+	writeTestFile(t, filepath.Join(tmpDir.Path(), "main.tf"), `module "app" {
+  source = "./modules/app"
+}
+
+module "shared" {
+  source = "./modules/shared"
+}
+
+output "shared_name" {
+  value = module.shared.name
+}
+`)
+	writeTestFile(t, filepath.Join(appDir.Path(), "main.tf"), `module "shared" {
+  source = "../shared"
+}
+
+locals {
+  name = upper(module.shared.name.first)
+}
+`)
+	sharedCfg := `output "name" {
+  value = { first = "shared" }
+}
+`
+	writeTestFile(t, filepath.Join(sharedDir.Path(), "main.tf"), sharedCfg)
+	writeTestFile(t, filepath.Join(tmpDir.Path(), ".terraform", "modules", "modules.json"), `{"Modules":[
+	{"Key":"","Source":"","Dir":"."},
+	{"Key":"app","Source":"./modules/app","Dir":"modules/app"},
+	{"Key":"app.shared","Source":"../shared","Dir":"modules/shared"},
+	{"Key":"shared","Source":"./modules/shared","Dir":"modules/shared"}
+]}`)
+
+	ss, err := state.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wc := walker.NewWalkerCollector()
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TofuCalls: &exec.TofuMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				tmpDir.Path():    validTfMockCalls(),
+				appDir.Path():    validTfMockCalls(),
+				sharedDir.Path(): validTfMockCalls(),
+			},
+		},
+		StateStore:      ss,
+		WalkerCollector: wc,
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+			"capabilities": {},
+			"rootUri": %q,
+			"processId": 12345
+	}`, tmpDir.URI)})
+	waitForWalkerPath(t, ss, wc, tmpDir)
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "opentofu",
+			"text": %q,
+			"uri": "%s/main.tf"
+		}
+	}`, sharedCfg, sharedDir.URI)})
+	waitForAllJobs(t, ss)
+
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/rename",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/main.tf"
+			},
+			"position": {
+				"line": 0,
+				"character": 9
+			},
+			"newName": "renamed"
+		}`, sharedDir.URI)}, fmt.Sprintf(`{
+			"jsonrpc": "2.0",
+			"id": 3,
+			"result": {
+				"changes": {
+					"%s/main.tf": [
+						{
+							"range": {
+								"start": {
+									"line": 9,
+									"character": 24
+								},
+								"end": {
+									"line": 9,
+									"character": 28
+								}
+							},
+							"newText": "renamed"
+						}
+					],
+					"%s/main.tf": [
+						{
+							"range": {
+								"start": {
+									"line": 5,
+									"character": 29
+								},
+								"end": {
+									"line": 5,
+									"character": 33
+								}
+							},
+							"newText": "renamed"
+						}
+					],
+					"%s/main.tf": [
+						{
+							"range": {
+								"start": {
+									"line": 0,
+									"character": 8
+								},
+								"end": {
+									"line": 0,
+									"character": 12
+								}
+							},
+							"newText": "renamed"
+						}
+					]
+				}
+			}
+		}`, tmpDir.URI, appDir.URI, sharedDir.URI))
+}
@@ -9,6 +9,10 @@ import (
 	"context"
 
 	"github.com/creachadair/jrpc2"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
+	idecoder "github.com/opentofu/tofu-ls/internal/decoder"
+	"github.com/opentofu/tofu-ls/internal/document"
 	ilsp "github.com/opentofu/tofu-ls/internal/lsp"
 	lsp "github.com/opentofu/tofu-ls/internal/protocol"
 )
@@ -38,28 +42,177 @@ func (svc *service) TextDocumentSemanticTokensFull(ctx context.Context, params l
 		return tks, err
 	}
 
-	jobIds, err := svc.stateStore.JobStore.ListIncompleteJobsForDir(dh.Dir)
+	if cached, ok := cachedSemanticTokens(doc); ok {
+		tks.Data = cached.Data
+		if caps.FullDeltaRequest() {
+			tks.ResultID = cached.ResultID
+		}
+		return tks, nil
+	}
+
+	tks.Data, err = svc.encodeSemanticTokens(ctx, doc, cc.TextDocument.SemanticTokens, nil)
 	if err != nil {
 		return tks, err
 	}
-	svc.stateStore.JobStore.WaitForJobs(ctx, jobIds...)
 
-	d, err := svc.decoderForDocument(ctx, doc)
+	if caps.FullDeltaRequest() {
+		// The result is only needed for computing a delta later
+		tks.ResultID, err = svc.stateStore.DocumentStore.SetSemanticTokens(dh, doc.Version, tks.Data)
+		if err != nil {
+			return tks, err
+		}
+	}
+
+	return tks, nil
+}
+
+// TextDocumentSemanticTokensFullDelta returns edits to the tokens
+// previously returned for the document, or all tokens if the previous
+// result is no longer known.
+//
+// The whole document is still tokenized unless the tokens of the current
+// version are already known, so a delta mostly reduces the size
+// of the response, not the work done by the server.
+func (svc *service) TextDocumentSemanticTokensFullDelta(ctx context.Context, params lsp.SemanticTokensDeltaParams) (interface{}, error) {
+	cc, err := ilsp.ClientCapabilities(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	caps := ilsp.SemanticTokensClientCapabilities{
+		SemanticTokensClientCapabilities: cc.TextDocument.SemanticTokens,
+	}
+	if !caps.FullDeltaRequest() {
+		svc.logger.Printf("semantic tokens full/delta request support not announced by client")
+		return nil, jrpc2.MethodNotFound.Err()
+	}
+
+	dh := ilsp.HandleFromDocumentURI(params.TextDocument.URI)
+	doc, err := svc.stateStore.DocumentStore.GetDocument(dh)
+	if err != nil {
+		return nil, err
+	}
+
+	if cached, ok := cachedSemanticTokens(doc); ok {
+		if cached.ResultID == params.PreviousResultID {
+			return lsp.SemanticTokensDelta{
+				ResultID: cached.ResultID,
+				Edits:    []lsp.SemanticTokensEdit{},
+			}, nil
+		}
+		return lsp.SemanticTokens{
+			ResultID: cached.ResultID,
+			Data:     cached.Data,
+		}, nil
+	}
+
+	data, err := svc.encodeSemanticTokens(ctx, doc, cc.TextDocument.SemanticTokens, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resultId, err := svc.stateStore.DocumentStore.SetSemanticTokens(dh, doc.Version, data)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := doc.SemanticTokens
+	if previous == nil || previous.ResultID != params.PreviousResultID {
+		return lsp.SemanticTokens{
+			ResultID: resultId,
+			Data:     data,
+		}, nil
+	}
+
+	return lsp.SemanticTokensDelta{
+		ResultID: resultId,
+		Edits:    ilsp.SemanticTokensEdits(previous.Data, data),
+	}, nil
+}
+
+// TextDocumentSemanticTokensRange returns tokens of the blocks and attributes
+// overlapping with the given range, which may include tokens outside
+// of the range.
+func (svc *service) TextDocumentSemanticTokensRange(ctx context.Context, params lsp.SemanticTokensRangeParams) (lsp.SemanticTokens, error) {
+	tks := lsp.SemanticTokens{}
+
+	cc, err := ilsp.ClientCapabilities(ctx)
 	if err != nil {
 		return tks, err
 	}
 
-	tokens, err := d.SemanticTokensInFile(ctx, doc.Filename)
+	caps := ilsp.SemanticTokensClientCapabilities{
+		SemanticTokensClientCapabilities: cc.TextDocument.SemanticTokens,
+	}
+	if !caps.RangeRequest() {
+		svc.logger.Printf("semantic tokens range request support not announced by client")
+		return tks, jrpc2.MethodNotFound.Err()
+	}
+
+	dh := ilsp.HandleFromDocumentURI(params.TextDocument.URI)
+	doc, err := svc.stateStore.DocumentStore.GetDocument(dh)
+	if err != nil {
+		return tks, err
+	}
+
+	rng, err := documentRange(doc, params.Range)
 	if err != nil {
 		return tks, err
 	}
 
+	tks.Data, err = svc.encodeSemanticTokens(ctx, doc, cc.TextDocument.SemanticTokens, &rng)
+	if err != nil {
+		return tks, err
+	}
+
+	return tks, nil
+}
+
+// encodeSemanticTokens returns encoded tokens of the document,
+// optionally only those of blocks overlapping with the given range
+func (svc *service) encodeSemanticTokens(ctx context.Context, doc *document.Document, clientCaps lsp.SemanticTokensClientCapabilities, rng *hcl.Range) ([]uint32, error) {
+	jobIds, err := svc.stateStore.JobStore.ListIncompleteJobsForDir(doc.Dir)
+	if err != nil {
+		return nil, err
+	}
+	svc.stateStore.JobStore.WaitForJobs(ctx, jobIds...)
+
+	var tokens []lang.SemanticToken
+	if rng != nil {
+		path := lang.Path{
+			Path:       doc.Dir.Path(),
+			LanguageID: string(ilsp.ParseLanguageID(doc.LanguageID)),
+		}
+		tokens, err = idecoder.SemanticTokensInRange(ctx, svc.pathReader, path, doc.Filename, *rng)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		d, err := svc.decoderForDocument(ctx, doc)
+		if err != nil {
+			return nil, err
+		}
+
+		tokens, err = d.SemanticTokensInFile(ctx, doc.Filename)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	te := &ilsp.TokenEncoder{
 		Lines:      doc.Lines,
 		Tokens:     tokens,
-		ClientCaps: cc.TextDocument.SemanticTokens,
+		ClientCaps: clientCaps,
 	}
-	tks.Data = te.Encode()
+	return te.Encode(), nil
+}
 
-	return tks, nil
+// cachedSemanticTokens returns tokens previously computed
+// for the current version of the document, if any
+func cachedSemanticTokens(doc *document.Document) (*document.SemanticTokens, bool) {
+	if doc.SemanticTokens == nil || doc.SemanticTokens.Version != doc.Version ||
+		doc.SemanticTokens.IsOutdated {
+		return nil, false
+	}
+	return doc.SemanticTokens, true
 }
@@ -251,12 +251,217 @@ func TestSemanticTokensFull_clientSupportsDelta(t *testing.T) {
 			"jsonrpc": "2.0",
 			"id": 3,
 			"result": {
+				"resultId": "0-1",
 				"data": [
 					0,0,8,3,0,
 					0,9,6,0,1
 				]
 			}
 		}`)
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didChange",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 1,
+			"uri": "%s/main.tf"
+		},
+		"contentChanges": [
+			{
+				"text": "provider \"test\" {\n\n}\n\nprovider \"test\" {\n}\n"
+			}
+		]
+	}`, tmpDir.URI)})
+	waitForAllJobs(t, ss)
+
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/semanticTokens/full/delta",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/main.tf"
+			},
+			"previousResultId": "0-1"
+		}`, tmpDir.URI)}, `{
+			"jsonrpc": "2.0",
+			"id": 5,
+			"result": {
+				"resultId": "1-2",
+				"edits": [
+					{
+						"start": 10,
+						"deleteCount": 0,
+						"data": [
+							4,0,8,3,0,
+							0,9,6,0,1
+						]
+					}
+				]
+			}
+		}`)
+
+	// Unknown previous result, where tokens of the current
+	// version of the document are reused
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/semanticTokens/full/delta",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/main.tf"
+			},
+			"previousResultId": "0-1"
+		}`, tmpDir.URI)}, `{
+			"jsonrpc": "2.0",
+			"id": 6,
+			"result": {
+				"resultId": "1-2",
+				"data": [
+					0,0,8,3,0,
+					0,9,6,0,1,
+					4,0,8,3,0,
+					0,9,6,0,1
+				]
+			}
+		}`)
+
+	// The document has not changed since the previous result
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/semanticTokens/full/delta",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/main.tf"
+			},
+			"previousResultId": "1-2"
+		}`, tmpDir.URI)}, `{
+			"jsonrpc": "2.0",
+			"id": 7,
+			"result": {
+				"resultId": "1-2",
+				"edits": []
+			}
+		}`)
+}
+
+func TestSemanticTokensRange(t *testing.T) {
+	tmpDir := TempDir(t)
+	InitPluginCache(t, tmpDir.Path())
+
+	var testSchema tfjson.ProviderSchemas
+	err := json.Unmarshal([]byte(testModuleSchemaOutput), &testSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ss, err := state.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wc := walker.NewWalkerCollector()
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TofuCalls: &exec.TofuMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				tmpDir.Path(): {
+					{
+						Method:        "Version",
+						Repeatability: 1,
+						Arguments: []interface{}{
+							mock.AnythingOfType(""),
+						},
+						ReturnArguments: []interface{}{
+							version.Must(version.NewVersion("0.12.0")),
+							nil,
+							nil,
+						},
+					},
+					{
+						Method:        "GetExecPath",
+						Repeatability: 1,
+						ReturnArguments: []interface{}{
+							"",
+						},
+					},
+					{
+						Method:        "ProviderSchemas",
+						Repeatability: 1,
+						Arguments: []interface{}{
+							mock.AnythingOfType(""),
+						},
+						ReturnArguments: []interface{}{
+							&testSchema,
+							nil,
+						},
+					},
+				},
+			},
+		},
+		StateStore:      ss,
+		WalkerCollector: wc,
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+		"capabilities": {
+			"textDocument": {
+				"semanticTokens": {
+					"tokenTypes": [
+						"enumMember",
+						"property",
+						"string",
+						"type"
+					],
+					"tokenModifiers": [
+						"defaultLibrary",
+						"deprecated"
+					],
+					"requests": {
+						"full": true,
+						"range": true
+					}
+				}
+			}
+		},
+		"rootUri": %q,
+		"processId": 12345
+	}`, tmpDir.URI)})
+	waitForWalkerPath(t, ss, wc, tmpDir)
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "opentofu",
+			"text": "provider \"test\" {\n}\n\nprovider \"test\" {\n}\n",
+			"uri": "%s/main.tf"
+		}
+	}`, tmpDir.URI)})
+	waitForAllJobs(t, ss)
+
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/semanticTokens/range",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/main.tf"
+			},
+			"range": {
+				"start": { "line": 2, "character": 0 },
+				"end": { "line": 4, "character": 1 }
+			}
+		}`, tmpDir.URI)}, `{
+			"jsonrpc": "2.0",
+			"id": 3,
+			"result": {
+				"data": [
+					3,0,8,3,0,
+					0,9,6,0,1
+				]
+			}
+		}`)
 }
 
 func TestVarsSemanticTokensFull(t *testing.T) {
@@ -18,15 +18,19 @@ import (
 	rpch "github.com/creachadair/jrpc2/handler"
 	"github.com/hashicorp/hcl-lang/decoder"
 	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/opentofu/tofu-ls/internal/cache"
+	"github.com/opentofu/tofu-ls/internal/codelens"
 	lsctx "github.com/opentofu/tofu-ls/internal/context"
 	idecoder "github.com/opentofu/tofu-ls/internal/decoder"
 	"github.com/opentofu/tofu-ls/internal/document"
 	"github.com/opentofu/tofu-ls/internal/eventbus"
 	fmodules "github.com/opentofu/tofu-ls/internal/features/modules"
 	frootmodules "github.com/opentofu/tofu-ls/internal/features/rootmodules"
+	ftests "github.com/opentofu/tofu-ls/internal/features/tests"
 	fvariables "github.com/opentofu/tofu-ls/internal/features/variables"
 	"github.com/opentofu/tofu-ls/internal/filesystem"
 	"github.com/opentofu/tofu-ls/internal/job"
+	"github.com/opentofu/tofu-ls/internal/langserver/cmd"
 	"github.com/opentofu/tofu-ls/internal/langserver/diagnostics"
 	"github.com/opentofu/tofu-ls/internal/langserver/notifier"
 	"github.com/opentofu/tofu-ls/internal/langserver/session"
@@ -50,6 +54,7 @@ type Features struct {
 	Modules     *fmodules.ModulesFeature
 	RootModules *frootmodules.RootModulesFeature
 	Variables   *fvariables.VariablesFeature
+	Tests       *ftests.TestsFeature
 }
 
 type service struct {
@@ -72,7 +77,9 @@ type service struct {
 	tfExecFactory  exec.ExecutorFactory
 	tfExecOpts     *exec.ExecutorOpts
 	decoder        *decoder.Decoder
+	pathReader     decoder.PathReader
 	stateStore     *state.StateStore
+	cache          *cache.Cache
 	server         session.Server
 	diagsNotifier  *diagnostics.Notifier
 	notifier       *notifier.Notifier
@@ -156,6 +163,7 @@ func (svc *service) Assigner() (jrpc2.Assigner, error) {
 			}
 
 			ctx = ilsp.WithClientCapabilities(ctx, cc)
+			ctx = lsctx.WithExperimentalFeatures(ctx, &expFeatures)
 
 			return handle(ctx, req, svc.Initialized)
 		},
@@ -174,6 +182,7 @@ func (svc *service) Assigner() (jrpc2.Assigner, error) {
 				return nil, err
 			}
 			ctx = lsctx.WithValidationOptions(ctx, &validationOptions)
+			ctx = lsctx.WithExperimentalFeatures(ctx, &expFeatures)
 
 			return handle(ctx, req, svc.TextDocumentDidOpen)
 		},
@@ -265,6 +274,7 @@ func (svc *service) Assigner() (jrpc2.Assigner, error) {
 			}
 
 			ctx = ilsp.WithClientCapabilities(ctx, cc)
+			ctx = lsctx.WithCommandPrefix(ctx, &commandPrefix)
 			ctx = exec.WithExecutorOpts(ctx, svc.tfExecOpts)
 			ctx = exec.WithExecutorFactory(ctx, svc.tfExecFactory)
 
@@ -311,6 +321,26 @@ func (svc *service) Assigner() (jrpc2.Assigner, error) {
 
 			return handle(ctx, req, svc.TextDocumentSemanticTokensFull)
 		},
+		"textDocument/semanticTokens/full/delta": func(ctx context.Context, req *jrpc2.Request) (interface{}, error) {
+			err := session.CheckInitializationIsConfirmed()
+			if err != nil {
+				return nil, err
+			}
+
+			ctx = ilsp.WithClientCapabilities(ctx, cc)
+
+			return handle(ctx, req, svc.TextDocumentSemanticTokensFullDelta)
+		},
+		"textDocument/semanticTokens/range": func(ctx context.Context, req *jrpc2.Request) (interface{}, error) {
+			err := session.CheckInitializationIsConfirmed()
+			if err != nil {
+				return nil, err
+			}
+
+			ctx = ilsp.WithClientCapabilities(ctx, cc)
+
+			return handle(ctx, req, svc.TextDocumentSemanticTokensRange)
+		},
 		"textDocument/didSave": func(ctx context.Context, req *jrpc2.Request) (interface{}, error) {
 			err := session.CheckInitializationIsConfirmed()
 			if err != nil {
@@ -338,6 +368,7 @@ func (svc *service) Assigner() (jrpc2.Assigner, error) {
 				return nil, err
 			}
 			ctx = lsctx.WithValidationOptions(ctx, &validationOptions)
+			ctx = lsctx.WithExperimentalFeatures(ctx, &expFeatures)
 
 			return handle(ctx, req, svc.DidChangeWatchedFiles)
 		},
@@ -349,6 +380,70 @@ func (svc *service) Assigner() (jrpc2.Assigner, error) {
 
 			return handle(ctx, req, svc.References)
 		},
+		"textDocument/prepareRename": func(ctx context.Context, req *jrpc2.Request) (interface{}, error) {
+			err := session.CheckInitializationIsConfirmed()
+			if err != nil {
+				return nil, err
+			}
+
+			return handle(ctx, req, svc.PrepareRename)
+		},
+		"textDocument/rename": func(ctx context.Context, req *jrpc2.Request) (interface{}, error) {
+			err := session.CheckInitializationIsConfirmed()
+			if err != nil {
+				return nil, err
+			}
+
+			return handle(ctx, req, svc.Rename)
+		},
+		"textDocument/documentHighlight": func(ctx context.Context, req *jrpc2.Request) (interface{}, error) {
+			err := session.CheckInitializationIsConfirmed()
+			if err != nil {
+				return nil, err
+			}
+
+			return handle(ctx, req, svc.DocumentHighlight)
+		},
+		"textDocument/foldingRange": func(ctx context.Context, req *jrpc2.Request) (interface{}, error) {
+			err := session.CheckInitializationIsConfirmed()
+			if err != nil {
+				return nil, err
+			}
+
+			return handle(ctx, req, svc.FoldingRange)
+		},
+		"textDocument/prepareCallHierarchy": func(ctx context.Context, req *jrpc2.Request) (interface{}, error) {
+			err := session.CheckInitializationIsConfirmed()
+			if err != nil {
+				return nil, err
+			}
+
+			return handle(ctx, req, svc.PrepareCallHierarchy)
+		},
+		"callHierarchy/incomingCalls": func(ctx context.Context, req *jrpc2.Request) (interface{}, error) {
+			err := session.CheckInitializationIsConfirmed()
+			if err != nil {
+				return nil, err
+			}
+
+			return handle(ctx, req, svc.CallHierarchyIncomingCalls)
+		},
+		"callHierarchy/outgoingCalls": func(ctx context.Context, req *jrpc2.Request) (interface{}, error) {
+			err := session.CheckInitializationIsConfirmed()
+			if err != nil {
+				return nil, err
+			}
+
+			return handle(ctx, req, svc.CallHierarchyOutgoingCalls)
+		},
+		"textDocument/inlayHint": func(ctx context.Context, req *jrpc2.Request) (interface{}, error) {
+			err := session.CheckInitializationIsConfirmed()
+			if err != nil {
+				return nil, err
+			}
+
+			return handle(ctx, req, svc.InlayHint)
+		},
 		"workspace/executeCommand": func(ctx context.Context, req *jrpc2.Request) (interface{}, error) {
 			err := session.CheckInitializationIsConfirmed()
 			if err != nil {
@@ -364,6 +459,26 @@ func (svc *service) Assigner() (jrpc2.Assigner, error) {
 
 			return handle(ctx, req, svc.WorkspaceExecuteCommand)
 		},
+		"textDocument/diagnostic": func(ctx context.Context, req *jrpc2.Request) (interface{}, error) {
+			err := session.CheckInitializationIsConfirmed()
+			if err != nil {
+				return nil, err
+			}
+			ctx = lsctx.WithValidationOptions(ctx, &validationOptions)
+			ctx = lsctx.WithExperimentalFeatures(ctx, &expFeatures)
+
+			return handle(ctx, req, svc.TextDocumentDiagnostic)
+		},
+		"workspace/diagnostic": func(ctx context.Context, req *jrpc2.Request) (interface{}, error) {
+			err := session.CheckInitializationIsConfirmed()
+			if err != nil {
+				return nil, err
+			}
+			ctx = lsctx.WithValidationOptions(ctx, &validationOptions)
+			ctx = lsctx.WithExperimentalFeatures(ctx, &expFeatures)
+
+			return handle(ctx, req, svc.WorkspaceDiagnostic)
+		},
 		"workspace/symbol": func(ctx context.Context, req *jrpc2.Request) (interface{}, error) {
 			err := session.CheckInitializationIsConfirmed()
 			if err != nil {
@@ -469,6 +584,10 @@ func (svc *service) configureSessionDependencies(ctx context.Context, cfgOpts *s
 			return err
 		}
 		svc.stateStore = store
+
+		if cfgOpts.Cache.Enable {
+			svc.loadPersistentCache(cfgOpts.Cache)
+		}
 	}
 
 	svc.stateStore.SetLogger(svc.logger)
@@ -503,6 +622,12 @@ func (svc *service) configureSessionDependencies(ctx context.Context, cfgOpts *s
 	svc.closedDirWalker.Collector = svc.walkerCollector
 	svc.openDirWalker.SetLogger(svc.logger)
 
+	credentials, err := registry.LoadCredentials()
+	if err != nil {
+		svc.logger.Printf("failed to load registry credentials: %s", err)
+	}
+	svc.registryClient.Credentials = credentials
+
 	if svc.features == nil {
 		rootModulesFeature, err := frootmodules.NewRootModulesFeature(svc.eventBus, svc.stateStore, svc.fs,
 			svc.tfExecFactory)
@@ -518,6 +643,7 @@ func (svc *service) configureSessionDependencies(ctx context.Context, cfgOpts *s
 			return err
 		}
 		modulesFeature.SetLogger(svc.logger)
+		modulesFeature.SetProviderSchemaMirror(cfgOpts.ExperimentalFeatures.ProviderSchemaMirror, svc.cache)
 		modulesFeature.Start(svc.sessCtx)
 
 		variablesFeature, err := fvariables.NewVariablesFeature(svc.eventBus, svc.stateStore, svc.fs,
@@ -528,33 +654,66 @@ func (svc *service) configureSessionDependencies(ctx context.Context, cfgOpts *s
 		variablesFeature.SetLogger(svc.logger)
 		variablesFeature.Start(svc.sessCtx)
 
+		testsFeature, err := ftests.NewTestsFeature(svc.eventBus, svc.stateStore, svc.fs,
+			modulesFeature, rootModulesFeature)
+		if err != nil {
+			return err
+		}
+		testsFeature.SetLogger(svc.logger)
+		testsFeature.Start(svc.sessCtx)
+
 		svc.features = &Features{
 			Modules:     modulesFeature,
 			RootModules: rootModulesFeature,
 			Variables:   variablesFeature,
+			Tests:       testsFeature,
 		}
 	}
 
-	svc.decoder = decoder.NewDecoder(&idecoder.GlobalPathReader{
+	svc.pathReader = &idecoder.GlobalPathReader{
 		PathReaderMap: idecoder.PathReaderMap{
 			ilsp.OpenTofu.String():     svc.features.Modules,
 			ilsp.OpenTofuVars.String(): svc.features.Variables,
+			ilsp.OpenTofuTest.String(): svc.features.Tests,
+			ilsp.OpenTofuMock.String(): svc.features.Tests,
 		},
-	})
+	}
+	svc.decoder = decoder.NewDecoder(svc.pathReader)
 	decoderContext := idecoder.DecoderContext(ctx)
+	if cfgOpts.ExperimentalFeatures.InitStatusCodeLens {
+		initCmdId := cmd.Name("tofu.init")
+		if cfgOpts.CommandPrefix != "" {
+			initCmdId = fmt.Sprintf("%s.%s", cfgOpts.CommandPrefix, initCmdId)
+		}
+		decoderContext.CodeLenses = append(decoderContext.CodeLenses,
+			codelens.InitStatus(svc.features.Modules, svc.features.RootModules, initCmdId))
+	}
 	svc.features.Modules.AppendCompletionHooks(svc.srvCtx, decoderContext)
 	svc.decoder.SetContext(decoderContext)
 
 	moduleHooks := []notifier.Hook{
-		updateDiagnostics(svc.features, svc.diagsNotifier),
+		expireSemanticTokens(svc.stateStore.DocumentStore),
 	}
 
 	cc, err := ilsp.ClientCapabilities(ctx)
+	if err == nil && cc.TextDocument.Diagnostic != nil &&
+		cc.Workspace.Diagnostics != nil && cc.Workspace.Diagnostics.RefreshSupport {
+		// Diagnostics are pulled by the client instead,
+		// which we ask to pull them again when they change
+		moduleHooks = append(moduleHooks, refreshDiagnostics(svc.server))
+	} else {
+		moduleHooks = append(moduleHooks, updateDiagnostics(svc.features, svc.diagsNotifier))
+	}
+
 	if err == nil {
 		if _, ok := lsp.ExperimentalClientCapabilities(cc.Experimental).ShowReferencesCommandId(); ok {
 			moduleHooks = append(moduleHooks, refreshCodeLens(svc.server))
 		}
 
+		if cfgOpts.ExperimentalFeatures.InitStatusCodeLens && cc.Workspace.CodeLens != nil && cc.Workspace.CodeLens.RefreshSupport {
+			moduleHooks = append(moduleHooks, refreshInitStatusCodeLens(svc.server))
+		}
+
 		if commandId, ok := lsp.ExperimentalClientCapabilities(cc.Experimental).RefreshModuleProvidersCommandId(); ok {
 			moduleHooks = append(moduleHooks, callRefreshClientCommand(svc.server, commandId))
 		}
@@ -579,6 +738,26 @@ func (svc *service) configureSessionDependencies(ctx context.Context, cfgOpts *s
 	return nil
 }
 
+// loadPersistentCache makes the state store write provider schemas and
+// registry module data through to the cache shared by all sessions
+// and loads any data cached by earlier sessions.
+func (svc *service) loadPersistentCache(opts settings.Cache) {
+	cacheDir, err := cache.DefaultDir()
+	if err != nil {
+		svc.logger.Printf("persistent cache not available: %s", err)
+		return
+	}
+
+	startTime := time.Now()
+	svc.cache = cache.New(cacheDir, int64(opts.MaxSizeMB)<<20)
+	svc.stateStore.SetCache(svc.cache)
+	err = svc.stateStore.LoadCache()
+	if err != nil {
+		svc.logger.Printf("failed to load persistent cache: %s", err)
+	}
+	svc.logger.Printf("loaded persistent cache from %s in %s", cacheDir, time.Since(startTime))
+}
+
 func (svc *service) Finish(_ jrpc2.Assigner, status jrpc2.ServerStatus) {
 	if status.Closed || status.Err != nil {
 		svc.logger.Printf("session stopped unexpectedly (err: %v)", status.Err)
@@ -617,6 +796,9 @@ func (svc *service) shutdown() {
 		if svc.features.Variables != nil {
 			svc.features.Variables.Stop()
 		}
+		if svc.features.Tests != nil {
+			svc.features.Tests.Stop()
+		}
 	}
 }
 
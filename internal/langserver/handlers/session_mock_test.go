@@ -19,6 +19,7 @@ import (
 	"github.com/opentofu/tofu-ls/internal/eventbus"
 	fmodules "github.com/opentofu/tofu-ls/internal/features/modules"
 	frootmodules "github.com/opentofu/tofu-ls/internal/features/rootmodules"
+	ftests "github.com/opentofu/tofu-ls/internal/features/tests"
 	fvariables "github.com/opentofu/tofu-ls/internal/features/variables"
 	"github.com/opentofu/tofu-ls/internal/filesystem"
 	"github.com/opentofu/tofu-ls/internal/langserver/session"
@@ -68,6 +69,15 @@ func (ms *mockSession) new(srvCtx context.Context) session.Session {
 		fileSystem = ms.mockInput.FileSystem
 		eventBus = ms.mockInput.EventBus
 	}
+	if stateStore == nil {
+		// the service would otherwise create a store backed
+		// by the persistent cache of the current user
+		var err error
+		stateStore, err = state.NewStateStore()
+		if err != nil {
+			panic(err)
+		}
+	}
 
 	var tfCalls *exec.TofuMockCalls
 	if ms.mockInput != nil && ms.mockInput.TofuCalls != nil {
@@ -163,9 +173,15 @@ func NewTestFeatures(eventBus *eventbus.EventBus, s *state.StateStore, fs *files
 		return nil, err
 	}
 
+	testsFeature, err := ftests.NewTestsFeature(eventBus, s, fs, modulesFeature, rootModulesFeature)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Features{
 		Modules:     modulesFeature,
 		RootModules: rootModulesFeature,
 		Variables:   variablesFeature,
+		Tests:       testsFeature,
 	}, nil
 }
@@ -18,6 +18,7 @@ import (
 	"github.com/opentofu/tofu-ls/internal/tofu/exec"
 	"github.com/opentofu/tofu-ls/internal/walker"
 	"github.com/stretchr/testify/mock"
+	"github.com/zclconf/go-cty/cty"
 )
 
 func TestSignatureHelp_withoutInitialization(t *testing.T) {
@@ -153,3 +154,146 @@ func TestSignatureHelp_withValidData(t *testing.T) {
 			}
 		}`)
 }
+
+func TestSignatureHelp_providerFunction(t *testing.T) {
+	tmpDir := TempDir(t)
+	InitPluginCache(t, tmpDir.Path())
+
+	ss, err := state.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wc := walker.NewWalkerCollector()
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TofuCalls: &exec.TofuMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				tmpDir.Path(): providerFunctionsMockCalls(),
+			},
+		},
+		StateStore:      ss,
+		WalkerCollector: wc,
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+		"capabilities": {},
+		"rootUri": %q,
+		"processId": 12345
+	}`, tmpDir.URI)})
+	waitForWalkerPath(t, ss, wc, tmpDir)
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "opentofu",
+			"text": %q,
+			"uri": "%s/main.tf"
+		}
+	}`, providerFunctionsConfig, tmpDir.URI)})
+	waitForAllJobs(t, ss)
+
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/signatureHelp",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/main.tf"
+			},
+			"position": {
+				"character": 33,
+				"line": 9
+			},
+			"context": {
+				"isRetrigger": false,
+				"triggerCharacter": "(",
+				"triggerKind": 2
+			}
+		}`, tmpDir.URI)}, `{
+			"jsonrpc": "2.0",
+			"id": 3,
+			"result": {
+				"signatures": [{
+					"label": "provider::aws::arn_parse(arn string) string",
+					"documentation": "Parses an ARN into its components.",
+					"parameters": [{"label": "arn"}]
+				}]
+			}
+		}`)
+}
+
+const providerFunctionsConfig = `terraform {
+  required_providers {
+    aws = {
+      source = "hashicorp/aws"
+    }
+  }
+}
+
+locals {
+  arn = provider::aws::arn_parse("arn:aws:iam::123456789012:user/test")
+}
+`
+
+// providerFunctionsMockCalls returns calls of a CLI version supporting
+// provider-defined functions, where the aws provider defines arn_parse
+func providerFunctionsMockCalls() []*mock.Call {
+	return []*mock.Call{
+		{
+			Method:        "Version",
+			Repeatability: 1,
+			Arguments: []interface{}{
+				mock.AnythingOfType(""),
+			},
+			ReturnArguments: []interface{}{
+				version.Must(version.NewVersion("1.7.0")),
+				nil,
+				nil,
+			},
+		},
+		{
+			Method:        "GetExecPath",
+			Repeatability: 1,
+			ReturnArguments: []interface{}{
+				"",
+			},
+		},
+		{
+			Method:        "ProviderSchemas",
+			Repeatability: 1,
+			Arguments: []interface{}{
+				mock.AnythingOfType(""),
+			},
+			ReturnArguments: []interface{}{
+				&tfjson.ProviderSchemas{
+					FormatVersion: "1.0",
+					Schemas: map[string]*tfjson.ProviderSchema{
+						"registry.opentofu.org/hashicorp/aws": {
+							ConfigSchema: &tfjson.Schema{},
+							Functions: map[string]*tfjson.FunctionSignature{
+								"arn_parse": {
+									Description: "Parses an ARN into its components.",
+									ReturnType:  cty.String,
+									Parameters: []*tfjson.FunctionParameter{
+										{
+											Name: "arn",
+											Type: cty.String,
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				nil,
+			},
+		},
+	}
+}
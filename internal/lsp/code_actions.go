@@ -7,6 +7,7 @@ package lsp
 
 import (
 	"sort"
+	"strings"
 
 	lsp "github.com/opentofu/tofu-ls/internal/protocol"
 )
@@ -35,8 +36,17 @@ var (
 	// We do not register this for terraform to allow fine grained selection of actions.
 	// A user should be able to set `source.formatAll` to true, and source.formatAll.opentofu to false to allow all
 	// files to be formatted, but not terraform files (or vice versa).
+	//
+	// `quickfix`: Fixes for problems reported by diagnostics, such as
+	// declaring a missing variable. These are offered for diagnostics
+	// in the given context, including when no kind is requested.
+	//
+	// `refactor.extract`: Extracts the selected expression into a local value,
+	// or the selected resource and data blocks into a new child module.
 	SupportedCodeActions = CodeActions{
 		SourceFormatAllTofu: true,
+		lsp.QuickFix:        true,
+		lsp.RefactorExtract: true,
 	}
 )
 
@@ -52,14 +62,29 @@ func (c CodeActions) AsSlice() []lsp.CodeActionKind {
 	return s
 }
 
+// Only returns actions of the requested kinds, where a quickfix or
+// refactor kind also covers its sub-kinds, e.g. refactor covers
+// refactor.extract. Source actions must be requested explicitly,
+// so that clients can opt out of formatting by kind.
 func (ca CodeActions) Only(only []lsp.CodeActionKind) CodeActions {
 	wanted := make(CodeActions, 0)
 
 	for _, kind := range only {
-		if v, ok := ca[kind]; ok {
-			wanted[kind] = v
+		for action, v := range ca {
+			if action == kind || (isHierarchicalKind(kind) && strings.HasPrefix(string(action), string(kind)+".")) {
+				wanted[action] = v
+			}
 		}
 	}
 
 	return wanted
 }
+
+func isHierarchicalKind(kind lsp.CodeActionKind) bool {
+	for _, parent := range []lsp.CodeActionKind{lsp.QuickFix, lsp.Refactor} {
+		if kind == parent || strings.HasPrefix(string(kind), string(parent)+".") {
+			return true
+		}
+	}
+	return false
+}
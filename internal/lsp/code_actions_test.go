@@ -0,0 +1,49 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package lsp
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	lsp "github.com/opentofu/tofu-ls/internal/protocol"
+)
+
+func TestCodeActions_Only(t *testing.T) {
+	testCases := []struct {
+		only     []lsp.CodeActionKind
+		expected CodeActions
+	}{
+		{
+			[]lsp.CodeActionKind{lsp.Refactor},
+			CodeActions{lsp.RefactorExtract: true},
+		},
+		{
+			[]lsp.CodeActionKind{lsp.QuickFix, lsp.RefactorExtract},
+			CodeActions{lsp.QuickFix: true, lsp.RefactorExtract: true},
+		},
+		{
+			[]lsp.CodeActionKind{lsp.Source},
+			CodeActions{},
+		},
+		{
+			[]lsp.CodeActionKind{"source.formatAll"},
+			CodeActions{},
+		},
+		{
+			[]lsp.CodeActionKind{SourceFormatAllTofu},
+			CodeActions{SourceFormatAllTofu: true},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%d-%v", i, tc.only), func(t *testing.T) {
+			wanted := SupportedCodeActions.Only(tc.only)
+			if diff := cmp.Diff(tc.expected, wanted); diff != "" {
+				t.Fatalf("unexpected code actions: %s", diff)
+			}
+		})
+	}
+}
@@ -6,10 +6,49 @@
 package lsp
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
 	"github.com/hashicorp/hcl/v2"
 	lsp "github.com/opentofu/tofu-ls/internal/protocol"
 )
 
+// WorkspaceDiagnosticReport is the result of the workspace/diagnostic
+// request with either full or unchanged reports as items.
+type WorkspaceDiagnosticReport struct {
+	Items []interface{} `json:"items"`
+}
+
+// WorkspaceFullDiagnosticReport is a full report of a document
+// for the workspace/diagnostic request. Unlike the protocol type,
+// the version can be null for documents which are not open.
+type WorkspaceFullDiagnosticReport struct {
+	URI     lsp.DocumentURI `json:"uri"`
+	Version *int32          `json:"version"`
+	lsp.FullDocumentDiagnosticReport
+}
+
+// WorkspaceUnchangedDiagnosticReport is an unchanged report of a document
+// for the workspace/diagnostic request.
+type WorkspaceUnchangedDiagnosticReport struct {
+	URI     lsp.DocumentURI `json:"uri"`
+	Version *int32          `json:"version"`
+	lsp.UnchangedDocumentDiagnosticReport
+}
+
+// DiagnosticsResultID returns a result ID for pulled diagnostics,
+// which is the same for the same diagnostics, so that clients can
+// be told the diagnostics are unchanged without keeping any state.
+func DiagnosticsResultID(diags []lsp.Diagnostic) string {
+	b, err := json.Marshal(diags)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:8])
+}
+
 func HCLSeverityToLSP(severity hcl.DiagnosticSeverity) lsp.DiagnosticSeverity {
 	var sev lsp.DiagnosticSeverity
 	switch severity {
@@ -31,3 +31,23 @@ func TestHCLDiagsToLSP_NeverReturnsNil(t *testing.T) {
 		t.Fatal("diags should not be nil")
 	}
 }
+
+func TestDiagnosticsResultID(t *testing.T) {
+	diags := HCLDiagsToLSP(hcl.Diagnostics{
+		{
+			Severity: hcl.DiagError,
+			Summary:  "Unsupported argument",
+		},
+	}, "source")
+
+	firstId := DiagnosticsResultID(diags)
+	if firstId == "" {
+		t.Fatal("expected result ID")
+	}
+	if secondId := DiagnosticsResultID(diags); firstId != secondId {
+		t.Fatalf("expected same result ID for same diagnostics, given %q and %q", firstId, secondId)
+	}
+	if emptyId := DiagnosticsResultID(HCLDiagsToLSP(nil, "source")); firstId == emptyId {
+		t.Fatalf("expected different result ID for different diagnostics, given %q", emptyId)
+	}
+}
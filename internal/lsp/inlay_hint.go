@@ -0,0 +1,30 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package lsp
+
+import (
+	"encoding/json"
+
+	lsp "github.com/opentofu/tofu-ls/internal/protocol"
+)
+
+// InlayHint represents an inlay hint with a plain text tooltip.
+//
+// The generated protocol type represents the tooltip as a union
+// of a string and MarkupContent, which is marshaled as an object
+// wrapping the value, rather than the value itself.
+type InlayHint struct {
+	lsp.InlayHint
+	Tooltip string
+}
+
+func (h InlayHint) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		lsp.InlayHint
+		Tooltip string `json:"tooltip,omitempty"`
+	}{
+		InlayHint: h.InlayHint,
+		Tooltip:   h.Tooltip,
+	})
+}
@@ -12,14 +12,19 @@ type LanguageID string
 const (
 	OpenTofu     LanguageID = "opentofu"
 	OpenTofuVars LanguageID = "opentofu-vars"
+	OpenTofuTest LanguageID = "opentofu-test"
+	OpenTofuMock LanguageID = "opentofu-mock"
 	// Terraform - Some editors do not support language ID overrides which makes it difficult to use this language server
 	// We also need to accept language IDs of Terraform to circumvent this issue
 	Terraform     LanguageID = "terraform"
 	TerraformVars LanguageID = "terraform-vars"
+	TerraformTest LanguageID = "terraform-test"
+	TerraformMock LanguageID = "terraform-mock"
 )
 
 // ParseLanguageID parses a string into a LanguageID
-// We also remap Terraform to OpenTofu and TerraformVars to OpenTofuVars
+// We also remap Terraform to OpenTofu, TerraformVars to OpenTofuVars, TerraformTest to OpenTofuTest
+// and TerraformMock to OpenTofuMock
 // We assume that the language ID is valid or the validation step has been done before parsing
 func ParseLanguageID(id string) LanguageID {
 	switch LanguageID(id) {
@@ -27,6 +32,10 @@ func ParseLanguageID(id string) LanguageID {
 		return OpenTofu
 	case TerraformVars:
 		return OpenTofuVars
+	case TerraformTest:
+		return OpenTofuTest
+	case TerraformMock:
+		return OpenTofuMock
 	default:
 		return LanguageID(id)
 	}
@@ -50,6 +59,24 @@ func IsValidVarsLanguage(id string) bool {
 	}
 }
 
+func IsValidTestLanguage(id string) bool {
+	switch LanguageID(id) {
+	case OpenTofuTest, TerraformTest:
+		return true
+	default:
+		return false
+	}
+}
+
+func IsValidMockLanguage(id string) bool {
+	switch LanguageID(id) {
+	case OpenTofuMock, TerraformMock:
+		return true
+	default:
+		return false
+	}
+}
+
 func (l LanguageID) String() string {
 	return string(l)
 }
@@ -66,6 +66,16 @@ func TokenModifiersLegend(clientSupported []string) semtok.TokenModifiers {
 	return legend
 }
 
+// SemanticTokensOptions mirrors lsp.SemanticTokensOptions, where Full
+// can also be lsp.PFullESemanticTokensOptions to announce support
+// for full/delta requests, which the generated type cannot represent.
+type SemanticTokensOptions struct {
+	Legend lsp.SemanticTokensLegend `json:"legend"`
+	Range  bool                     `json:"range,omitempty"`
+	Full   interface{}              `json:"full,omitempty"`
+	lsp.WorkDoneProgressOptions
+}
+
 type SemanticTokensClientCapabilities struct {
 	lsp.SemanticTokensClientCapabilities
 }
@@ -79,3 +89,60 @@ func (c SemanticTokensClientCapabilities) FullRequest() bool {
 	}
 	return false
 }
+
+func (c SemanticTokensClientCapabilities) FullDeltaRequest() bool {
+	full, ok := c.Requests.Full.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	delta, ok := full["delta"].(bool)
+	return ok && delta
+}
+
+func (c SemanticTokensClientCapabilities) RangeRequest() bool {
+	return c.Requests.Range
+}
+
+// tokenLength is the number of integers representing each encoded token
+const tokenLength = 5
+
+// SemanticTokensEdits returns edits which turn previous encoded
+// tokens into current ones. Unchanged tokens at the start
+// and the end are left out, leaving a single edit at most.
+func SemanticTokensEdits(previous, current []uint32) []lsp.SemanticTokensEdit {
+	edits := make([]lsp.SemanticTokensEdit, 0)
+
+	prefix := 0
+	for prefix+tokenLength <= len(previous) && prefix+tokenLength <= len(current) &&
+		tokensEqual(previous[prefix:prefix+tokenLength], current[prefix:prefix+tokenLength]) {
+		prefix += tokenLength
+	}
+
+	suffix := 0
+	for prefix+suffix+tokenLength <= len(previous) && prefix+suffix+tokenLength <= len(current) &&
+		tokensEqual(previous[len(previous)-suffix-tokenLength:len(previous)-suffix],
+			current[len(current)-suffix-tokenLength:len(current)-suffix]) {
+		suffix += tokenLength
+	}
+
+	deleteCount := len(previous) - prefix - suffix
+	data := current[prefix : len(current)-suffix]
+	if deleteCount == 0 && len(data) == 0 {
+		return edits
+	}
+
+	return append(edits, lsp.SemanticTokensEdit{
+		Start:       uint32(prefix),
+		DeleteCount: uint32(deleteCount),
+		Data:        data,
+	})
+}
+
+func tokensEqual(a, b []uint32) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
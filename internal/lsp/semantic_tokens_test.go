@@ -0,0 +1,69 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package lsp
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	lsp "github.com/opentofu/tofu-ls/internal/protocol"
+)
+
+func TestSemanticTokensEdits(t *testing.T) {
+	testCases := []struct {
+		name          string
+		previous      []uint32
+		current       []uint32
+		expectedEdits []lsp.SemanticTokensEdit
+	}{
+		{
+			"no change",
+			[]uint32{0, 0, 8, 3, 0, 0, 9, 6, 0, 1},
+			[]uint32{0, 0, 8, 3, 0, 0, 9, 6, 0, 1},
+			[]lsp.SemanticTokensEdit{},
+		},
+		{
+			"token appended",
+			[]uint32{0, 0, 8, 3, 0},
+			[]uint32{0, 0, 8, 3, 0, 2, 0, 8, 3, 0},
+			[]lsp.SemanticTokensEdit{
+				{Start: 5, DeleteCount: 0, Data: []uint32{2, 0, 8, 3, 0}},
+			},
+		},
+		{
+			"line inserted before tokens",
+			[]uint32{0, 0, 8, 3, 0, 0, 9, 6, 0, 1, 2, 0, 8, 3, 0},
+			[]uint32{0, 0, 8, 3, 0, 0, 9, 6, 0, 1, 3, 0, 8, 3, 0},
+			[]lsp.SemanticTokensEdit{
+				{Start: 10, DeleteCount: 5, Data: []uint32{3, 0, 8, 3, 0}},
+			},
+		},
+		{
+			"token removed in the middle",
+			[]uint32{0, 0, 8, 3, 0, 1, 2, 4, 1, 0, 1, 0, 1, 2, 0},
+			[]uint32{0, 0, 8, 3, 0, 1, 0, 1, 2, 0},
+			[]lsp.SemanticTokensEdit{
+				{Start: 5, DeleteCount: 5, Data: []uint32{}},
+			},
+		},
+		{
+			"all tokens removed",
+			[]uint32{0, 0, 8, 3, 0},
+			[]uint32{},
+			[]lsp.SemanticTokensEdit{
+				{Start: 0, DeleteCount: 5, Data: []uint32{}},
+			},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%d-%s", i, tc.name), func(t *testing.T) {
+			edits := SemanticTokensEdits(tc.previous, tc.current)
+			if diff := cmp.Diff(tc.expectedEdits, edits); diff != "" {
+				t.Fatalf("unexpected edits: %s", diff)
+			}
+		})
+	}
+}
@@ -6,9 +6,12 @@
 package lsp
 
 import (
+	"sort"
+
 	"github.com/hashicorp/hcl-lang/lang"
 	"github.com/opentofu/tofu-ls/internal/document"
 	lsp "github.com/opentofu/tofu-ls/internal/protocol"
+	"github.com/opentofu/tofu-ls/internal/uri"
 )
 
 func TextEditsFromDocumentChanges(changes document.Changes) []lsp.TextEdit {
@@ -48,6 +51,70 @@ func textEdit(te lang.TextEdit, snippetSupport bool) *lsp.TextEdit {
 	}
 }
 
+// WorkspaceEditFromTextEdits turns edits keyed by absolute
+// file paths into a workspace edit
+func WorkspaceEditFromTextEdits(fileEdits map[string][]lang.TextEdit) *lsp.WorkspaceEdit {
+	changes := make(map[lsp.DocumentURI][]lsp.TextEdit, len(fileEdits))
+
+	for path, tes := range fileEdits {
+		changes[lsp.DocumentURI(uri.FromPath(path))] = TextEdits(tes, false)
+	}
+
+	return &lsp.WorkspaceEdit{
+		Changes: changes,
+	}
+}
+
+// DocumentChangesWithNewFiles turns content of files to create and edits
+// of existing files, both keyed by absolute file paths, into document
+// changes of a workspace edit, since plain changes cannot create files.
+func DocumentChangesWithNewFiles(newFiles map[string]string, fileEdits map[string][]lang.TextEdit) []DocumentChange {
+	changes := make([]DocumentChange, 0)
+
+	for _, path := range sortedPaths(newFiles) {
+		fileURI := lsp.DocumentURI(uri.FromPath(path))
+		changes = append(changes, DocumentChange{
+			CreateFile: &lsp.CreateFile{
+				Kind: string(lsp.Create),
+				URI:  fileURI,
+			},
+		}, DocumentChange{
+			TextDocumentEdit: &TextDocumentEdit{
+				TextDocument: OptionalVersionedTextDocumentIdentifier{
+					TextDocumentIdentifier: lsp.TextDocumentIdentifier{URI: fileURI},
+				},
+				Edits: []lsp.TextEdit{
+					{NewText: newFiles[path]},
+				},
+			},
+		})
+	}
+
+	for _, path := range sortedPaths(fileEdits) {
+		changes = append(changes, DocumentChange{
+			TextDocumentEdit: &TextDocumentEdit{
+				TextDocument: OptionalVersionedTextDocumentIdentifier{
+					TextDocumentIdentifier: lsp.TextDocumentIdentifier{
+						URI: lsp.DocumentURI(uri.FromPath(path)),
+					},
+				},
+				Edits: TextEdits(fileEdits[path], false),
+			},
+		})
+	}
+
+	return changes
+}
+
+func sortedPaths[T any](m map[string]T) []string {
+	paths := make([]string, 0, len(m))
+	for path := range m {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
 func insertTextFormat(snippetSupport bool) lsp.InsertTextFormat {
 	if snippetSupport {
 		return lsp.SnippetTextFormat
@@ -0,0 +1,82 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package lsp
+
+import (
+	"encoding/json"
+	"fmt"
+
+	lsp "github.com/opentofu/tofu-ls/internal/protocol"
+)
+
+// The generated protocol types cannot represent workspace edits creating
+// files, since their document changes only cover edits and renames, and
+// the version of edited documents cannot be null. The types below wrap
+// the generated ones where these are needed.
+
+// CodeAction represents a code action whose edit may create files.
+type CodeAction struct {
+	lsp.CodeAction
+	// DocumentChanges replace the edit of the code action, if set
+	DocumentChanges []DocumentChange
+}
+
+func (ca CodeAction) MarshalJSON() ([]byte, error) {
+	if ca.DocumentChanges == nil {
+		return json.Marshal(ca.CodeAction)
+	}
+
+	return json.Marshal(struct {
+		lsp.CodeAction
+		Edit workspaceEdit `json:"edit"`
+	}{
+		CodeAction: ca.CodeAction,
+		Edit: workspaceEdit{
+			DocumentChanges: ca.DocumentChanges,
+		},
+	})
+}
+
+// CodeActionsFromLSP wraps the given code actions
+func CodeActionsFromLSP(actions []lsp.CodeAction) []CodeAction {
+	ca := make([]CodeAction, len(actions))
+	for i, action := range actions {
+		ca[i] = CodeAction{CodeAction: action}
+	}
+	return ca
+}
+
+type workspaceEdit struct {
+	DocumentChanges []DocumentChange `json:"documentChanges"`
+}
+
+// DocumentChange is a union of a file edit and a file creation.
+// At most one field of this struct is non-nil.
+type DocumentChange struct {
+	TextDocumentEdit *TextDocumentEdit
+	CreateFile       *lsp.CreateFile
+}
+
+func (d DocumentChange) MarshalJSON() ([]byte, error) {
+	if d.TextDocumentEdit != nil {
+		return json.Marshal(d.TextDocumentEdit)
+	} else if d.CreateFile != nil {
+		return json.Marshal(d.CreateFile)
+	}
+
+	return nil, fmt.Errorf("empty DocumentChange union value")
+}
+
+// TextDocumentEdit represents edits of a document of any version
+type TextDocumentEdit struct {
+	TextDocument OptionalVersionedTextDocumentIdentifier `json:"textDocument"`
+	Edits        []lsp.TextEdit                          `json:"edits"`
+}
+
+// OptionalVersionedTextDocumentIdentifier identifies a document,
+// where a nil version means that the content on disk is the truth.
+type OptionalVersionedTextDocumentIdentifier struct {
+	Version *int32 `json:"version"`
+	lsp.TextDocumentIdentifier
+}
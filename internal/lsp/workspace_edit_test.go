@@ -0,0 +1,124 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package lsp
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
+	lsp "github.com/opentofu/tofu-ls/internal/protocol"
+	"github.com/opentofu/tofu-ls/internal/uri"
+)
+
+func TestCodeAction_MarshalJSON(t *testing.T) {
+	dirPath := t.TempDir()
+	newPath := filepath.Join(dirPath, "modules", "web", "main.tf")
+	mainPath := filepath.Join(dirPath, "main.tf")
+
+	ca := CodeAction{
+		CodeAction: lsp.CodeAction{
+			Title: "Extract to module",
+			Kind:  lsp.RefactorExtract,
+		},
+		DocumentChanges: DocumentChangesWithNewFiles(map[string]string{
+			newPath: "resource \"aws_instance\" \"web\" {\n}\n",
+		}, map[string][]lang.TextEdit{
+			mainPath: {
+				{
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.InitialPos,
+						End:      hcl.Pos{Line: 3, Column: 1, Byte: 35},
+					},
+					NewText: "module \"web\" {\n  source = \"./modules/web\"\n}\n",
+				},
+			},
+		}),
+	}
+
+	b, err := json.Marshal(ca)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]interface{}{
+		"title": "Extract to module",
+		"kind":  "refactor.extract",
+		"edit": map[string]interface{}{
+			"documentChanges": []interface{}{
+				map[string]interface{}{
+					"kind": "create",
+					"uri":  uri.FromPath(newPath),
+				},
+				map[string]interface{}{
+					"textDocument": map[string]interface{}{
+						"uri":     uri.FromPath(newPath),
+						"version": nil,
+					},
+					"edits": []interface{}{
+						map[string]interface{}{
+							"range": map[string]interface{}{
+								"start": map[string]interface{}{"line": 0.0, "character": 0.0},
+								"end":   map[string]interface{}{"line": 0.0, "character": 0.0},
+							},
+							"newText": "resource \"aws_instance\" \"web\" {\n}\n",
+						},
+					},
+				},
+				map[string]interface{}{
+					"textDocument": map[string]interface{}{
+						"uri":     uri.FromPath(mainPath),
+						"version": nil,
+					},
+					"edits": []interface{}{
+						map[string]interface{}{
+							"range": map[string]interface{}{
+								"start": map[string]interface{}{"line": 0.0, "character": 0.0},
+								"end":   map[string]interface{}{"line": 2.0, "character": 0.0},
+							},
+							"newText": "module \"web\" {\n  source = \"./modules/web\"\n}\n",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	var given map[string]interface{}
+	err = json.Unmarshal(b, &given)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(expected, given); diff != "" {
+		t.Fatalf("unexpected code action: %s", diff)
+	}
+}
+
+func TestCodeAction_MarshalJSON_withoutDocumentChanges(t *testing.T) {
+	action := lsp.CodeAction{
+		Title: "Extract to local value",
+		Kind:  lsp.RefactorExtract,
+		Edit: lsp.WorkspaceEdit{
+			Changes: map[lsp.DocumentURI][]lsp.TextEdit{
+				"file:///main.tf": {{NewText: "local.ami"}},
+			},
+		},
+	}
+
+	expected, err := json.Marshal(action)
+	if err != nil {
+		t.Fatal(err)
+	}
+	given, err := json.Marshal(CodeAction{CodeAction: action})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(string(expected), string(given)); diff != "" {
+		t.Fatalf("unexpected code action: %s", diff)
+	}
+}
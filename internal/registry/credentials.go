@@ -0,0 +1,212 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package registry
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/mitchellh/go-homedir"
+)
+
+const tokenEnvPrefix = "TF_TOKEN_"
+
+// Credentials maps registry hostnames to the API tokens
+// which are sent along with any requests to those hosts
+type Credentials map[string]string
+
+// Token returns the API token for the given host, if any
+func (c Credentials) Token(host string) (string, bool) {
+	token, ok := c[strings.ToLower(host)]
+	return token, ok
+}
+
+// LoadCredentials collects registry credentials the same way OpenTofu does,
+// i.e. from credentials blocks of the CLI config file, the credentials file
+// maintained by tofu login and TF_TOKEN_* environment variables.
+//
+// Tokens from environment variables take precedence over those from files.
+// Any credentials which could be loaded are returned along with errors
+// of sources which couldn't be read.
+func LoadCredentials() (Credentials, error) {
+	creds := make(Credentials)
+	var errs *multierror.Error
+
+	if path, ok := credentialsFilePath(); ok {
+		err := creds.loadJSONFile(path)
+		if err != nil {
+			errs = multierror.Append(errs, err)
+		}
+	}
+	if path, ok := cliConfigFilePath(); ok {
+		err := creds.loadConfigFile(path)
+		if err != nil {
+			errs = multierror.Append(errs, err)
+		}
+	}
+	creds.loadEnv(os.Environ())
+
+	return creds, errs.ErrorOrNil()
+}
+
+// loadConfigFile reads credentials blocks from a CLI config file, such as
+//
+//	credentials "registry.example.com" {
+//	  token = "xxxxxx.atlasv1.zzzzzzzzzzzzz"
+//	}
+func (c Credentials) loadConfigFile(path string) error {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+
+	f, diags := hclparse.NewParser().ParseHCL(src, path)
+	if diags.HasErrors() {
+		return diags
+	}
+
+	content, _, diags := f.Body.PartialContent(&hcl.BodySchema{
+		Blocks: []hcl.BlockHeaderSchema{
+			{Type: "credentials", LabelNames: []string{"hostname"}},
+		},
+	})
+	if diags.HasErrors() {
+		return diags
+	}
+
+	for _, block := range content.Blocks {
+		attrs, diags := block.Body.JustAttributes()
+		if diags.HasErrors() {
+			return diags
+		}
+		attr, ok := attrs["token"]
+		if !ok {
+			continue
+		}
+		var token string
+		diags = gohcl.DecodeExpression(attr.Expr, nil, &token)
+		if diags.HasErrors() {
+			return diags
+		}
+		c[strings.ToLower(block.Labels[0])] = token
+	}
+
+	return nil
+}
+
+type credentialsFile struct {
+	Credentials map[string]struct {
+		Token string `json:"token"`
+	} `json:"credentials"`
+}
+
+// loadJSONFile reads credentials from credentials.tfrc.json
+// as written by tofu login
+func (c Credentials) loadJSONFile(path string) error {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+
+	var file credentialsFile
+	err = json.Unmarshal(src, &file)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	for host, cred := range file.Credentials {
+		if cred.Token != "" {
+			c[strings.ToLower(host)] = cred.Token
+		}
+	}
+	return nil
+}
+
+// loadEnv reads tokens from TF_TOKEN_* variables, where the hostname
+// is encoded with periods replaced by underscores and hyphens replaced
+// by double underscores, e.g. TF_TOKEN_registry_example_com.
+func (c Credentials) loadEnv(environ []string) {
+	for _, kv := range environ {
+		name, token, ok := strings.Cut(kv, "=")
+		if !ok || !strings.HasPrefix(name, tokenEnvPrefix) || token == "" {
+			continue
+		}
+		host := strings.TrimPrefix(name, tokenEnvPrefix)
+		host = strings.ReplaceAll(host, "__", "-")
+		host = strings.ReplaceAll(host, "_", ".")
+		c[strings.ToLower(host)] = token
+	}
+}
+
+func cliConfigFilePath() (string, bool) {
+	for _, envVar := range []string{"TF_CLI_CONFIG_FILE", "TERRAFORM_CONFIG"} {
+		if path := os.Getenv(envVar); path != "" {
+			return path, true
+		}
+	}
+
+	if runtime.GOOS == "windows" {
+		appData := os.Getenv("APPDATA")
+		if appData == "" {
+			return "", false
+		}
+		return firstExistingPath(
+			filepath.Join(appData, "tofu.rc"),
+			filepath.Join(appData, "terraform.rc"),
+		), true
+	}
+
+	home, err := homedir.Dir()
+	if err != nil {
+		return "", false
+	}
+	return firstExistingPath(
+		filepath.Join(home, ".tofurc"),
+		filepath.Join(home, ".terraformrc"),
+	), true
+}
+
+func credentialsFilePath() (string, bool) {
+	if runtime.GOOS == "windows" {
+		appData := os.Getenv("APPDATA")
+		if appData == "" {
+			return "", false
+		}
+		return filepath.Join(appData, "terraform.d", "credentials.tfrc.json"), true
+	}
+
+	home, err := homedir.Dir()
+	if err != nil {
+		return "", false
+	}
+	return filepath.Join(home, ".terraform.d", "credentials.tfrc.json"), true
+}
+
+// firstExistingPath returns the first of the given paths which exists,
+// falling back to the first one, so that OpenTofu's own config file
+// takes precedence over the legacy one.
+func firstExistingPath(paths ...string) string {
+	for _, path := range paths {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return paths[0]
+}
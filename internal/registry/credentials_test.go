@@ -0,0 +1,104 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package registry
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/mitchellh/go-homedir"
+)
+
+func TestLoadCredentials(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("credentials files are looked up in APPDATA on Windows")
+	}
+
+	homeDir := t.TempDir()
+	t.Setenv("HOME", homeDir)
+	homedir.DisableCache = true
+	t.Cleanup(func() {
+		homedir.DisableCache = false
+	})
+
+	err := os.Mkdir(filepath.Join(homeDir, ".terraform.d"), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(filepath.Join(homeDir, ".terraform.d", "credentials.tfrc.json"), []byte(`{
+  "credentials": {
+    "app.example.com": {
+      "token": "login-token"
+    },
+    "registry.example.com": {
+      "token": "login-token"
+    }
+  }
+}`), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	configFile := filepath.Join(t.TempDir(), "custom.tofurc")
+	err = os.WriteFile(configFile, []byte(`
+plugin_cache_dir = "/tmp/plugins"
+
+credentials "Registry.Example.com" {
+  token = "config-token"
+}
+
+credentials "modules.example-corp.io" {
+  token = "config-token"
+}
+`), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("TF_CLI_CONFIG_FILE", configFile)
+	t.Setenv("TF_TOKEN_modules_example__corp_io", "env-token")
+
+	creds, err := LoadCredentials()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedCreds := map[string]string{
+		"app.example.com":         "login-token",
+		"registry.example.com":    "config-token",
+		"modules.example-corp.io": "env-token",
+	}
+	for host, expectedToken := range expectedCreds {
+		token, ok := creds.Token(host)
+		if !ok {
+			t.Fatalf("expected token for %q", host)
+		}
+		if token != expectedToken {
+			t.Fatalf("unexpected token for %q: %s", host, cmp.Diff(expectedToken, token))
+		}
+	}
+}
+
+func TestLoadCredentials_invalidConfigFile(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "custom.tofurc")
+	err := os.WriteFile(configFile, []byte(`credentials "registry.example.com" {`), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("TF_CLI_CONFIG_FILE", configFile)
+	t.Setenv("TF_TOKEN_registry_example_com", "env-token")
+
+	creds, err := LoadCredentials()
+	if err == nil {
+		t.Fatal("expected error for invalid config file")
+	}
+
+	// credentials from other sources are still returned
+	token, ok := creds.Token("registry.example.com")
+	if !ok || token != "env-token" {
+		t.Fatalf("expected token from environment, given: %q", token)
+	}
+}
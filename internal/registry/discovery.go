@@ -0,0 +1,111 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+const (
+	discoveryPath    = "/.well-known/terraform.json"
+	modulesServiceID = "modules.v1"
+)
+
+// discoverService looks up the base URL of the service with the given ID
+// on the registry host via service discovery. Discovered services are
+// remembered for the lifetime of the client.
+//
+// See https://opentofu.org/docs/internals/remote-service-discovery/
+func (c Client) discoverService(ctx context.Context, host, serviceID string) (*url.URL, error) {
+	services, ok := c.discoveredServices(host)
+	if !ok {
+		var err error
+		services, err = c.fetchServices(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		if c.services != nil {
+			c.services.Store(host, services)
+		}
+	}
+
+	rawServiceURL, ok := services[serviceID]
+	if !ok {
+		return nil, fmt.Errorf("host %s does not provide a %s service", host, serviceID)
+	}
+	serviceURL, ok := rawServiceURL.(string)
+	if !ok {
+		return nil, fmt.Errorf("host %s returned an invalid %s service URL", host, serviceID)
+	}
+
+	discoveryURL := &url.URL{Scheme: "https", Host: host, Path: discoveryPath}
+	u, err := discoveryURL.Parse(serviceURL)
+	if err != nil {
+		return nil, fmt.Errorf("host %s returned an invalid %s service URL: %w", host, serviceID, err)
+	}
+	return u, nil
+}
+
+func (c Client) discoveredServices(host string) (map[string]any, bool) {
+	if c.services == nil {
+		return nil, false
+	}
+	services, ok := c.services.Load(host)
+	if !ok {
+		return nil, false
+	}
+	return services.(map[string]any), true
+}
+
+func (c Client) fetchServices(ctx context.Context, host string) (map[string]any, error) {
+	discoveryURL := &url.URL{Scheme: "https", Host: host, Path: discoveryPath}
+
+	req, err := c.newRequest(ctx, host, discoveryURL.String())
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		bodyBytes, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, ClientError{StatusCode: resp.StatusCode, Body: string(bodyBytes)}
+	}
+
+	services := make(map[string]any)
+	err = json.NewDecoder(resp.Body).Decode(&services)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode service discovery document of %s: %w", host, err)
+	}
+
+	return services, nil
+}
+
+// newRequest creates a GET request to the given registry host,
+// which carries the API token for that host, if there is any
+func (c Client) newRequest(ctx context.Context, host, reqURL string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if token, ok := c.Credentials.Token(host); ok {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	return req, nil
+}
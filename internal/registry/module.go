@@ -76,6 +76,10 @@ func (c Client) GetModuleData(ctx context.Context, addr tfaddr.Module, cons vers
 		return nil, err
 	}
 
+	if !isPublicRegistryModule(addr) {
+		return c.getProtocolModuleData(ctx, addr, v)
+	}
+
 	ctx = httptrace.WithClientTrace(ctx, otelhttptrace.NewClientTrace(ctx, otelhttptrace.WithoutSubSpans()))
 
 	url := fmt.Sprintf("%s/registry/docs/modules/%s/%s/%s/v%s/index.json", c.BaseAPIURL,
@@ -133,6 +137,10 @@ func (c Client) GetModuleVersions(ctx context.Context, addr tfaddr.Module) (vers
 	ctx, span := otel.Tracer(tracerName).Start(ctx, "registry:GetModuleVersions")
 	defer span.End()
 
+	if !isPublicRegistryModule(addr) {
+		return c.getProtocolModuleVersions(ctx, addr)
+	}
+
 	url := fmt.Sprintf("%s/registry/docs/modules/%s/%s/%s/index.json", c.BaseAPIURL,
 		addr.Package.Namespace,
 		addr.Package.Name,
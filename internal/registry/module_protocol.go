@@ -0,0 +1,184 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http/httptrace"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/go-version"
+	tfaddr "github.com/opentofu/registry-address"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/httptrace/otelhttptrace"
+	"go.opentelemetry.io/otel"
+)
+
+// Modules hosted in registries other than the public OpenTofu registry
+// are looked up via the module registry protocol, since the documentation
+// API at BaseAPIURL only covers the public registry.
+//
+// See https://opentofu.org/docs/internals/module-registry-protocol/
+
+type protocolModuleVersionsResponse struct {
+	Modules []struct {
+		Versions []struct {
+			Version string `json:"version"`
+		} `json:"versions"`
+	} `json:"modules"`
+}
+
+// protocolModuleResponse represents the module details, which aren't part
+// of the protocol, but are served by most registry implementations
+type protocolModuleResponse struct {
+	Version     string                    `json:"version"`
+	PublishedAt time.Time                 `json:"published_at"`
+	Root        protocolModuleSubmodule   `json:"root"`
+	Submodules  []protocolModuleSubmodule `json:"submodules"`
+}
+
+type protocolModuleSubmodule struct {
+	Path    string   `json:"path"`
+	Inputs  []Input  `json:"inputs"`
+	Outputs []Output `json:"outputs"`
+}
+
+func isPublicRegistryModule(addr tfaddr.Module) bool {
+	return addr.Package.Host == tfaddr.DefaultModuleRegistryHost
+}
+
+func (c Client) getProtocolModuleVersions(ctx context.Context, addr tfaddr.Module) (version.Collection, error) {
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "registry:getProtocolModuleVersions")
+	defer span.End()
+
+	var response protocolModuleVersionsResponse
+	err := c.getProtocolModule(ctx, addr, "versions", &response)
+	if err != nil {
+		return nil, err
+	}
+
+	var foundVersions version.Collection
+	for _, mod := range response.Modules {
+		for _, entry := range mod.Versions {
+			ver, err := version.NewVersion(entry.Version)
+			if err == nil {
+				foundVersions = append(foundVersions, ver)
+			}
+		}
+	}
+
+	sort.Sort(sort.Reverse(foundVersions))
+
+	return foundVersions, nil
+}
+
+func (c Client) getProtocolModuleData(ctx context.Context, addr tfaddr.Module, v *version.Version) (*ModuleResponse, error) {
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "registry:getProtocolModuleData")
+	defer span.End()
+
+	var response protocolModuleResponse
+	err := c.getProtocolModule(ctx, addr, v.String(), &response)
+	if err != nil {
+		return nil, err
+	}
+
+	inputs, outputs := protocolModuleInterface(response.Root)
+	data := &ModuleResponse{
+		Version:     response.Version,
+		PublishedAt: response.PublishedAt,
+		Inputs:      inputs,
+		Outputs:     outputs,
+		Submodules:  make(map[string]Submodule, len(response.Submodules)),
+	}
+	if data.Version == "" {
+		data.Version = v.String()
+	}
+	for _, sm := range response.Submodules {
+		inputs, outputs := protocolModuleInterface(sm)
+		data.Submodules[sm.Path] = Submodule{
+			Path:    sm.Path,
+			Inputs:  inputs,
+			Outputs: outputs,
+		}
+	}
+
+	return data, nil
+}
+
+// getProtocolModule requests the given path below the module
+// in the modules service of the module's registry host
+func (c Client) getProtocolModule(ctx context.Context, addr tfaddr.Module, path string, response any) error {
+	host := addr.Package.Host.String()
+	serviceURL, err := c.discoverService(ctx, host, modulesServiceID)
+	if err != nil {
+		return err
+	}
+
+	modURL, err := serviceURL.Parse(fmt.Sprintf("%s/%s/%s/%s",
+		addr.Package.Namespace,
+		addr.Package.Name,
+		addr.Package.TargetSystem,
+		path))
+	if err != nil {
+		return err
+	}
+
+	ctx = httptrace.WithClientTrace(ctx, otelhttptrace.NewClientTrace(ctx, otelhttptrace.WithoutSubSpans()))
+
+	req, err := c.newRequest(ctx, host, modURL.String())
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		bodyBytes, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return err
+		}
+
+		return ClientError{StatusCode: resp.StatusCode, Body: string(bodyBytes)}
+	}
+
+	return json.NewDecoder(resp.Body).Decode(response)
+}
+
+// protocolModuleInterface converts inputs and outputs of the module
+// into maps, as returned by the documentation API
+func protocolModuleInterface(sm protocolModuleSubmodule) (map[string]Input, map[string]Output) {
+	inputs := make(map[string]Input, len(sm.Inputs))
+	for _, input := range sm.Inputs {
+		input.Default = decodeProtocolDefault(input.Default)
+		inputs[input.Name] = input
+	}
+	outputs := make(map[string]Output, len(sm.Outputs))
+	for _, output := range sm.Outputs {
+		outputs[output.Name] = output
+	}
+	return inputs, outputs
+}
+
+// decodeProtocolDefault decodes default values of inputs, which registries
+// serve as JSON encoded strings, such as "\"eu-west-1\"" or "true".
+func decodeProtocolDefault(rawDefault any) any {
+	s, ok := rawDefault.(string)
+	if !ok || s == "" {
+		return rawDefault
+	}
+
+	var value any
+	err := json.Unmarshal([]byte(s), &value)
+	if err != nil {
+		return rawDefault
+	}
+	return value
+}
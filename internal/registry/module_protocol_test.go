@@ -0,0 +1,216 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/go-version"
+	tfaddr "github.com/opentofu/registry-address"
+)
+
+var protocolModuleVersionsMockResponse = `{
+  "modules": [
+    {
+      "source": "example.com/corp/vpc/aws",
+      "versions": [
+        {"version": "1.0.0"},
+        {"version": "1.2.0"},
+        {"version": "1.1.0"}
+      ]
+    }
+  ]
+}`
+
+var protocolModuleDataMockResponse = `{
+  "id": "corp/vpc/aws/1.2.0",
+  "version": "1.2.0",
+  "published_at": "2024-03-01T10:00:00Z",
+  "root": {
+    "path": "",
+    "inputs": [
+      {
+        "name": "cidr",
+        "type": "string",
+        "description": "CIDR block of the VPC",
+        "default": "\"10.0.0.0/16\"",
+        "required": false
+      },
+      {
+        "name": "name",
+        "type": "string",
+        "description": "Name of the VPC",
+        "default": "",
+        "required": true
+      }
+    ],
+    "outputs": [
+      {
+        "name": "vpc_id",
+        "description": "ID of the VPC"
+      }
+    ]
+  },
+  "submodules": [
+    {
+      "path": "modules/subnets",
+      "inputs": [
+        {
+          "name": "enabled",
+          "type": "bool",
+          "description": "",
+          "default": "true",
+          "required": false
+        }
+      ],
+      "outputs": []
+    }
+  ]
+}`
+
+// newProtocolTestClient returns a client, which sends requests
+// for any registry host to the given TLS test server
+func newProtocolTestClient(srv *httptest.Server) Client {
+	client := NewClient()
+	client.httpClient = srv.Client()
+	transport := client.httpClient.Transport.(*http.Transport)
+	transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return (&net.Dialer{}).DialContext(ctx, network, srv.Listener.Addr().String())
+	}
+	return client
+}
+
+func newProtocolTestServer(t *testing.T) *httptest.Server {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret-token" {
+			http.Error(w, "unauthorized", 401)
+			return
+		}
+		switch r.RequestURI {
+		case "/.well-known/terraform.json":
+			w.Write([]byte(`{"modules.v1": "/api/registry/v1/modules/"}`))
+			return
+		case "/api/registry/v1/modules/corp/vpc/aws/versions":
+			w.Write([]byte(protocolModuleVersionsMockResponse))
+			return
+		case "/api/registry/v1/modules/corp/vpc/aws/1.2.0":
+			w.Write([]byte(protocolModuleDataMockResponse))
+			return
+		}
+		http.Error(w, fmt.Sprintf("unexpected request: %q", r.RequestURI), 400)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestGetModuleVersions_privateRegistry(t *testing.T) {
+	ctx := context.Background()
+	addr, err := tfaddr.ParseModuleSource("example.com/corp/vpc/aws")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := newProtocolTestClient(newProtocolTestServer(t))
+	client.Credentials = Credentials{"example.com": "secret-token"}
+
+	versions, err := client.GetModuleVersions(ctx, addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedVersions := version.Collection{
+		version.Must(version.NewVersion("1.2.0")),
+		version.Must(version.NewVersion("1.1.0")),
+		version.Must(version.NewVersion("1.0.0")),
+	}
+	if diff := cmp.Diff(expectedVersions, versions); diff != "" {
+		t.Fatalf("mismatched versions: %s", diff)
+	}
+}
+
+func TestGetModuleVersions_privateRegistryUnauthorized(t *testing.T) {
+	ctx := context.Background()
+	addr, err := tfaddr.ParseModuleSource("example.com/corp/vpc/aws")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := newProtocolTestClient(newProtocolTestServer(t))
+
+	_, err = client.GetModuleVersions(ctx, addr)
+	var clientErr ClientError
+	if !errors.As(err, &clientErr) {
+		t.Fatalf("expected client error, given: %#v", err)
+	}
+	if clientErr.StatusCode != 401 {
+		t.Fatalf("expected status code 401, given: %d", clientErr.StatusCode)
+	}
+}
+
+func TestGetModuleData_privateRegistry(t *testing.T) {
+	ctx := context.Background()
+	addr, err := tfaddr.ParseModuleSource("example.com/corp/vpc/aws")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cons := version.MustConstraints(version.NewConstraint("~> 1.1"))
+
+	client := newProtocolTestClient(newProtocolTestServer(t))
+	client.Credentials = Credentials{"example.com": "secret-token"}
+
+	data, err := client.GetModuleData(ctx, addr, cons)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedData := &ModuleResponse{
+		Version:     "1.2.0",
+		PublishedAt: time.Date(2024, time.March, 1, 10, 0, 0, 0, time.UTC),
+		Inputs: map[string]Input{
+			"cidr": {
+				Name:        "cidr",
+				Type:        "string",
+				Description: "CIDR block of the VPC",
+				Default:     "10.0.0.0/16",
+			},
+			"name": {
+				Name:        "name",
+				Type:        "string",
+				Description: "Name of the VPC",
+				Default:     "",
+				Required:    true,
+			},
+		},
+		Outputs: map[string]Output{
+			"vpc_id": {
+				Name:        "vpc_id",
+				Description: "ID of the VPC",
+			},
+		},
+		Submodules: map[string]Submodule{
+			"modules/subnets": {
+				Path: "modules/subnets",
+				Inputs: map[string]Input{
+					"enabled": {
+						Name:    "enabled",
+						Type:    "bool",
+						Default: true,
+					},
+				},
+				Outputs: map[string]Output{},
+			},
+		},
+	}
+	if diff := cmp.Diff(expectedData, data); diff != "" {
+		t.Fatalf("mismatched data: %s", diff)
+	}
+}
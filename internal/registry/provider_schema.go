@@ -0,0 +1,110 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http/httptrace"
+	"net/url"
+	"sort"
+
+	"github.com/hashicorp/go-version"
+	tfaddr "github.com/opentofu/registry-address"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/httptrace/otelhttptrace"
+	"go.opentelemetry.io/otel"
+)
+
+// Provider schemas are served by mirrors following the layout of the
+// provider network mirror protocol, with an additional schema document
+// per version, as produced by tofu providers schema -json:
+//
+//	<mirror>/<hostname>/<namespace>/<type>/index.json
+//	<mirror>/<hostname>/<namespace>/<type>/<version>/schema.json
+//
+// See https://opentofu.org/docs/internals/provider-network-mirror-protocol/
+
+type mirrorVersionsResponse struct {
+	Versions map[string]struct{} `json:"versions"`
+}
+
+// GetMirroredProviderVersions returns the versions of the provider
+// available in the mirror, sorted from the newest
+func (c Client) GetMirroredProviderVersions(ctx context.Context, mirrorURL string, pAddr tfaddr.Provider) (version.Collection, error) {
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "registry:GetMirroredProviderVersions")
+	defer span.End()
+
+	b, err := c.getMirrored(ctx, mirrorURL, pAddr, "index.json")
+	if err != nil {
+		return nil, err
+	}
+
+	var response mirrorVersionsResponse
+	err = json.Unmarshal(b, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	var foundVersions version.Collection
+	for rawVersion := range response.Versions {
+		ver, err := version.NewVersion(rawVersion)
+		if err == nil {
+			foundVersions = append(foundVersions, ver)
+		}
+	}
+
+	sort.Sort(sort.Reverse(foundVersions))
+
+	return foundVersions, nil
+}
+
+// GetMirroredProviderSchema returns the raw JSON schema document
+// of the given provider version
+func (c Client) GetMirroredProviderSchema(ctx context.Context, mirrorURL string, pAddr tfaddr.Provider, v *version.Version) ([]byte, error) {
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "registry:GetMirroredProviderSchema")
+	defer span.End()
+
+	return c.getMirrored(ctx, mirrorURL, pAddr, v.String()+"/schema.json")
+}
+
+func (c Client) getMirrored(ctx context.Context, mirrorURL string, pAddr tfaddr.Provider, path string) ([]byte, error) {
+	baseURL, err := url.Parse(mirrorURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mirror URL %q: %w", mirrorURL, err)
+	}
+	if baseURL.Path == "" || baseURL.Path[len(baseURL.Path)-1] != '/' {
+		baseURL.Path += "/"
+	}
+
+	reqURL, err := baseURL.Parse(fmt.Sprintf("./%s/%s/%s/%s",
+		pAddr.Hostname, pAddr.Namespace, pAddr.Type, path))
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = httptrace.WithClientTrace(ctx, otelhttptrace.NewClientTrace(ctx, otelhttptrace.WithoutSubSpans()))
+
+	req, err := c.newRequest(ctx, baseURL.Host, reqURL.String())
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != 200 {
+		return nil, ClientError{StatusCode: resp.StatusCode, Body: string(bodyBytes)}
+	}
+
+	return bodyBytes, nil
+}
@@ -7,6 +7,7 @@ package registry
 
 import (
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/hashicorp/go-cleanhttp"
@@ -24,7 +25,12 @@ type Client struct {
 	BaseAPIURL      string
 	BaseRegistryURL string
 	Timeout         time.Duration
-	httpClient      *http.Client
+	// Credentials are used to authenticate requests to registry hosts
+	// other than the public registry, found via service discovery
+	Credentials Credentials
+	httpClient  *http.Client
+	// services caches the services discovered per registry host
+	services *sync.Map
 }
 
 func NewClient() Client {
@@ -36,6 +42,8 @@ func NewClient() Client {
 		BaseAPIURL:      defaultBaseURL,
 		BaseRegistryURL: registryBaseURL,
 		Timeout:         defaultTimeout,
+		Credentials:     make(Credentials),
 		httpClient:      client,
+		services:        &sync.Map{},
 	}
 }
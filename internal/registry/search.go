@@ -0,0 +1,82 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/httptrace/otelhttptrace"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const moduleSearchResultType = "module"
+
+// SearchResult represents a single entry returned by the search API,
+// which can be a module, provider or any of their documentation pages
+type SearchResult struct {
+	Type        string `json:"type"`
+	Addr        string `json:"addr"`
+	Version     string `json:"version"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+// SearchModules searches the registry for modules matching the query.
+// Results are returned in the order of relevance as ranked by the registry.
+func (c Client) SearchModules(ctx context.Context, query string) ([]SearchResult, error) {
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "registry:SearchModules")
+	defer span.End()
+
+	reqUrl := fmt.Sprintf("%s/registry/docs/search?q=%s", c.BaseAPIURL, url.QueryEscape(query))
+
+	ctx = httptrace.WithClientTrace(ctx, otelhttptrace.NewClientTrace(ctx, otelhttptrace.WithoutSubSpans()))
+
+	req, err := http.NewRequestWithContext(ctx, "GET", reqUrl, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		bodyBytes, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, ClientError{StatusCode: resp.StatusCode, Body: string(bodyBytes)}
+	}
+
+	var response []SearchResult
+	err = json.NewDecoder(resp.Body).Decode(&response)
+	if err != nil {
+		return nil, err
+	}
+
+	modules := make([]SearchResult, 0)
+	for _, result := range response {
+		if result.Type == moduleSearchResultType && result.Addr != "" {
+			modules = append(modules, result)
+		}
+	}
+	span.AddEvent("registry:foundModules",
+		trace.WithAttributes(attribute.KeyValue{
+			Key:   attribute.Key("moduleCount"),
+			Value: attribute.IntValue(len(modules)),
+		}))
+
+	return modules, nil
+}
@@ -0,0 +1,106 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// searchMockResponse represents the shortened response from https://api.opentofu.org/registry/docs/search?q=vpc
+var searchMockResponse = `[
+  {
+    "id": "terraform-aws-modules/vpc/aws",
+    "last_updated": "2025-04-21T23:55:13Z",
+    "type": "module",
+    "addr": "terraform-aws-modules/vpc/aws",
+    "version": "v5.21.0",
+    "title": "vpc",
+    "description": "Terraform module to create AWS VPC resources"
+  },
+  {
+    "id": "providers/hashicorp/aws/latest/resources/vpc",
+    "last_updated": "2025-05-01T10:12:00Z",
+    "type": "provider/resource",
+    "addr": "hashicorp/aws",
+    "version": "v5.97.0",
+    "title": "aws_vpc",
+    "description": "Provides a VPC resource."
+  },
+  {
+    "id": "terraform-google-modules/network/google",
+    "last_updated": "2025-03-11T08:01:44Z",
+    "type": "module",
+    "addr": "terraform-google-modules/network/google",
+    "version": "v10.0.0",
+    "title": "network",
+    "description": "Sets up a new VPC network on Google Cloud"
+  }
+]`
+
+func TestSearchModules(t *testing.T) {
+	ctx := context.Background()
+	client := NewClient()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.RequestURI == "/registry/docs/search?q=vpc" {
+			w.Write([]byte(searchMockResponse))
+			return
+		}
+		http.Error(w, fmt.Sprintf("unexpected request: %q", r.RequestURI), 400)
+	}))
+	client.BaseAPIURL = srv.URL
+	t.Cleanup(srv.Close)
+
+	results, err := client.SearchModules(ctx, "vpc")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedResults := []SearchResult{
+		{
+			Type:        "module",
+			Addr:        "terraform-aws-modules/vpc/aws",
+			Version:     "v5.21.0",
+			Title:       "vpc",
+			Description: "Terraform module to create AWS VPC resources",
+		},
+		{
+			Type:        "module",
+			Addr:        "terraform-google-modules/network/google",
+			Version:     "v10.0.0",
+			Title:       "network",
+			Description: "Sets up a new VPC network on Google Cloud",
+		},
+	}
+	if diff := cmp.Diff(expectedResults, results); diff != "" {
+		t.Fatalf("mismatched results: %s", diff)
+	}
+}
+
+func TestSearchModules_clientError(t *testing.T) {
+	ctx := context.Background()
+	client := NewClient()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", 503)
+	}))
+	client.BaseAPIURL = srv.URL
+	t.Cleanup(srv.Close)
+
+	_, err := client.SearchModules(ctx, "vpc")
+	var clientErr ClientError
+	if !errors.As(err, &clientErr) {
+		t.Fatalf("expected client error, given: %#v", err)
+	}
+	if clientErr.StatusCode != 503 {
+		t.Fatalf("expected status code 503, given: %d", clientErr.StatusCode)
+	}
+}
@@ -17,8 +17,20 @@ import (
 )
 
 type ExperimentalFeatures struct {
-	ValidateOnSave        bool `mapstructure:"validateOnSave"`
-	PrefillRequiredFields bool `mapstructure:"prefillRequiredFields"`
+	ValidateOnSave        bool           `mapstructure:"validateOnSave"`
+	PrefillRequiredFields bool           `mapstructure:"prefillRequiredFields"`
+	PlanStateHover        PlanStateHover `mapstructure:"planStateHover"`
+	InitStatusCodeLens    bool           `mapstructure:"initStatusCodeLens"`
+	// ProviderSchemaMirror is the base URL of a mirror serving schemas
+	// of providers, which are neither installed nor bundled.
+	ProviderSchemaMirror string `mapstructure:"providerSchemaMirror"`
+}
+
+type PlanStateHover struct {
+	Enable bool `mapstructure:"enable"`
+	// PlanFile is the path to a saved plan, relative to each root module.
+	// The state is shown instead, if it's empty or the file doesn't exist.
+	PlanFile string `mapstructure:"planFile"`
 }
 
 type ValidationOptions struct {
@@ -36,6 +48,13 @@ type Tofu struct {
 	LogFilePath string `mapstructure:"logFilePath"`
 }
 
+// Cache configures the persistent cache of provider schemas
+// and registry module data, shared by all sessions
+type Cache struct {
+	Enable    bool `mapstructure:"enable" default:"true"`
+	MaxSizeMB int  `mapstructure:"maxSizeMB" default:"512"`
+}
+
 type Options struct {
 	CommandPrefix string   `mapstructure:"commandPrefix"`
 	Indexing      Indexing `mapstructure:"indexing"`
@@ -49,6 +68,8 @@ type Options struct {
 
 	TofuOptions Tofu `mapstructure:"tofu"`
 
+	Cache Cache `mapstructure:"cache"`
+
 	XLegacyModulePaths          []string `mapstructure:"rootModulePaths"`
 	XLegacyExcludeModulePaths   []string `mapstructure:"excludeModulePaths"`
 	XLegacyIgnoreDirectoryNames []string `mapstructure:"ignoreDirectoryNames"`
@@ -84,6 +105,10 @@ func (o *Options) Validate() error {
 		}
 	}
 
+	if o.Cache.MaxSizeMB < 0 {
+		return fmt.Errorf("expected non-negative cache size, got %d", o.Cache.MaxSizeMB)
+	}
+
 	return nil
 }
 
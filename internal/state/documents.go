@@ -6,7 +6,9 @@
 package state
 
 import (
+	"fmt"
 	"log"
+	"sync/atomic"
 	"time"
 
 	"github.com/hashicorp/go-memdb"
@@ -19,6 +21,8 @@ type DocumentStore struct {
 	tableName string
 	logger    *log.Logger
 
+	lastSemanticTokensId uint64
+
 	// TimeProvider provides current time (for mocking time.Now in tests)
 	TimeProvider func() time.Time
 }
@@ -93,6 +97,33 @@ func (s *DocumentStore) UpdateDocument(dh document.Handle, newText []byte, newVe
 	return nil
 }
 
+// SetSemanticTokens stores the encoded semantic tokens of the given
+// version of the document and returns a new result ID identifying them.
+func (s *DocumentStore) SetSemanticTokens(dh document.Handle, version int, data []uint32) (string, error) {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	doc, err := copyDocument(txn, dh)
+	if err != nil {
+		return "", err
+	}
+
+	id := atomic.AddUint64(&s.lastSemanticTokensId, 1)
+	doc.SemanticTokens = &document.SemanticTokens{
+		ResultID: fmt.Sprintf("%d-%d", version, id),
+		Version:  version,
+		Data:     data,
+	}
+
+	err = txn.Insert(s.tableName, doc)
+	if err != nil {
+		return "", err
+	}
+
+	txn.Commit()
+	return doc.SemanticTokens.ResultID, nil
+}
+
 func copyDocument(txn *memdb.Txn, dh document.Handle) (*document.Document, error) {
 	doc, err := getDocument(txn, dh)
 	if err != nil {
@@ -102,6 +133,42 @@ func copyDocument(txn *memdb.Txn, dh document.Handle) (*document.Document, error
 	return doc.Copy(), nil
 }
 
+// ExpireSemanticTokens marks semantic tokens of all documents
+// in the given directory as outdated, so that they're not reused
+// for the same version of the document.
+func (s *DocumentStore) ExpireSemanticTokens(dirHandle document.DirHandle) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	it, err := txn.Get(s.tableName, "dir", dirHandle)
+	if err != nil {
+		return err
+	}
+
+	docs := make([]*document.Document, 0)
+	for item := it.Next(); item != nil; item = it.Next() {
+		doc := item.(*document.Document)
+		if doc.SemanticTokens == nil || doc.SemanticTokens.IsOutdated {
+			continue
+		}
+		docs = append(docs, doc.Copy())
+	}
+
+	for _, doc := range docs {
+		tokens := *doc.SemanticTokens
+		tokens.IsOutdated = true
+		doc.SemanticTokens = &tokens
+
+		err = txn.Insert(s.tableName, doc)
+		if err != nil {
+			return err
+		}
+	}
+
+	txn.Commit()
+	return nil
+}
+
 func (s *DocumentStore) GetDocument(dh document.Handle) (*document.Document, error) {
 	txn := s.db.Txn(false)
 	return getDocument(txn, dh)
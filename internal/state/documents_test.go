@@ -122,6 +122,90 @@ func TestDocumentStore_GetDocument_basic(t *testing.T) {
 	}
 }
 
+func TestDocumentStore_SetSemanticTokens(t *testing.T) {
+	s, err := NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testHandle := document.HandleFromURI("file:///dir/test.tf")
+	err = s.DocumentStore.OpenDocument(testHandle, "opentofu", 0, []byte("foo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resultId, err := s.DocumentStore.SetSemanticTokens(testHandle, 0, []uint32{0, 0, 3, 1, 0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resultId != "0-1" {
+		t.Fatalf("unexpected result ID: %q", resultId)
+	}
+
+	// Tokens are kept for the next version of the document
+	err = s.DocumentStore.UpdateDocument(testHandle, []byte("foo {}"), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	doc, err := s.DocumentStore.GetDocument(testHandle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedTokens := &document.SemanticTokens{
+		ResultID: "0-1",
+		Version:  0,
+		Data:     []uint32{0, 0, 3, 1, 0},
+	}
+	if diff := cmp.Diff(expectedTokens, doc.SemanticTokens); diff != "" {
+		t.Fatalf("unexpected semantic tokens: %s", diff)
+	}
+
+	resultId, err = s.DocumentStore.SetSemanticTokens(testHandle, 1, []uint32{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resultId != "1-2" {
+		t.Fatalf("unexpected result ID: %q", resultId)
+	}
+}
+
+func TestDocumentStore_ExpireSemanticTokens(t *testing.T) {
+	s, err := NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testHandle := document.HandleFromURI("file:///dir/test.tf")
+	err = s.DocumentStore.OpenDocument(testHandle, "opentofu", 0, []byte("foo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = s.DocumentStore.SetSemanticTokens(testHandle, 0, []uint32{0, 0, 3, 1, 0})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = s.DocumentStore.ExpireSemanticTokens(testHandle.Dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	doc, err := s.DocumentStore.GetDocument(testHandle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedTokens := &document.SemanticTokens{
+		ResultID:   "0-1",
+		Version:    0,
+		Data:       []uint32{0, 0, 3, 1, 0},
+		IsOutdated: true,
+	}
+	if diff := cmp.Diff(expectedTokens, doc.SemanticTokens); diff != "" {
+		t.Fatalf("unexpected semantic tokens: %s", diff)
+	}
+}
+
 func TestDocumentStore_GetDocument_notFound(t *testing.T) {
 	s, err := NewStateStore()
 	if err != nil {
@@ -185,6 +185,28 @@ func (js *JobStore) ListIncompleteJobsForDir(dir document.DirHandle) (job.IDs, e
 	return jobIDs, nil
 }
 
+// ListIncompleteJobsForDirOfTypes returns queued and running jobs
+// of the given types for the given directory
+func (js *JobStore) ListIncompleteJobsForDirOfTypes(dir document.DirHandle, types ...string) (job.IDs, error) {
+	jobIDs := make(job.IDs, 0)
+	txn := js.db.Txn(false)
+
+	for _, state := range []State{StateQueued, StateRunning} {
+		for _, jobType := range types {
+			it, err := txn.Get(jobsTableName, "dir_state_type", dir, state, jobType)
+			if err != nil {
+				return jobIDs, fmt.Errorf("failed to find %s jobs for %q: %w", jobType, dir, err)
+			}
+			for obj := it.Next(); obj != nil; obj = it.Next() {
+				sj := obj.(*ScheduledJob)
+				jobIDs = append(jobIDs, sj.ID)
+			}
+		}
+	}
+
+	return jobIDs, nil
+}
+
 func (js *JobStore) DequeueJobsForDir(dir document.DirHandle) error {
 	txn := js.db.Txn(true)
 	defer txn.Abort()
@@ -261,6 +261,56 @@ func TestJobStore_DequeueJobsForDir(t *testing.T) {
 	}
 }
 
+func TestJobStore_ListIncompleteJobsForDirOfTypes(t *testing.T) {
+	ss, err := NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	ctx = lsctx.WithDocumentContext(ctx, lsctx.Document{})
+	dir := document.DirHandleFromPath("/test-1")
+	id1, err := ss.JobStore.EnqueueJob(ctx, job.Job{
+		Func: func(ctx context.Context) error {
+			return nil
+		},
+		Dir:  dir,
+		Type: "first-type",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = ss.JobStore.EnqueueJob(ctx, job.Job{
+		Func: func(ctx context.Context) error {
+			return nil
+		},
+		Dir:  dir,
+		Type: "second-type",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = ss.JobStore.EnqueueJob(ctx, job.Job{
+		Func: func(ctx context.Context) error {
+			return nil
+		},
+		Dir:  document.DirHandleFromPath("/test-2"),
+		Type: "first-type",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedJobIds := job.IDs{id1}
+	jobIds, err := ss.JobStore.ListIncompleteJobsForDirOfTypes(dir, "first-type")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(expectedJobIds, jobIds); diff != "" {
+		t.Fatalf("unexpected jobs: %s", diff)
+	}
+}
+
 func TestJobStore_AwaitNextJob_closedOnly(t *testing.T) {
 	ss, err := NewStateStore()
 	if err != nil {
@@ -201,6 +201,35 @@ func (s *ProviderSchemaStore) AddPreloadedSchema(addr tfaddr.Provider, pv *versi
 	return nil
 }
 
+func (s *ProviderSchemaStore) AddMirroredSchema(addr tfaddr.Provider, pv *version.Version, mirrorURL string, schema *tfschema.ProviderSchema) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	src := MirrorSchemaSource{MirrorURL: mirrorURL}
+	obj, err := txn.First(s.tableName, "id_prefix", addr, src, pv)
+	if err != nil {
+		return err
+	}
+	if obj != nil {
+		return &AlreadyExistsError{
+			Idx: fmt.Sprintf("%s@%s@%s", addr, src, pv),
+		}
+	}
+
+	err = txn.Insert(s.tableName, &ProviderSchema{
+		Address: addr,
+		Version: pv,
+		Source:  src,
+		Schema:  schema.Copy(),
+	})
+	if err != nil {
+		return err
+	}
+
+	txn.Commit()
+	return nil
+}
+
 func (s *ProviderSchemaStore) AllSchemasExist(pvm map[tfaddr.Provider]version.Constraints) (bool, error) {
 	for pAddr, pCons := range pvm {
 		exists, err := s.schemaExists(pAddr, pCons)
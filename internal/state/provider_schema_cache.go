@@ -0,0 +1,178 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/go-version"
+	tfjson "github.com/hashicorp/terraform-json"
+	tfschema "github.com/opentofu/opentofu-schema/schema"
+	tfaddr "github.com/opentofu/registry-address"
+)
+
+// Provider schemas are cached as documents in the format produced by
+// tofu providers schema -json, with a single provider per document:
+//
+//	provider-schemas/<hostname>/<namespace>/<type>/<version>.json
+const providerSchemasCachePrefix = "provider-schemas"
+
+// ProviderSchemaCacheDir returns the prefix of the keys
+// of all cached schema versions of the given provider
+func ProviderSchemaCacheDir(addr tfaddr.Provider) string {
+	return path.Join(providerSchemasCachePrefix, addr.Hostname.String(), addr.Namespace, addr.Type)
+}
+
+// ProviderSchemaCacheKey returns the key of the cached
+// schema of the given provider version
+func ProviderSchemaCacheKey(addr tfaddr.Provider, pv *version.Version) string {
+	return path.Join(ProviderSchemaCacheDir(addr), pv.String()+".json")
+}
+
+// CacheSchema persists the schema of the given provider version,
+// so that later sessions don't need to obtain it again.
+// It does nothing if no persistent cache is set.
+func (s *ProviderSchemaStore) CacheSchema(addr tfaddr.Provider, pv *version.Version, jsonSchema *tfjson.ProviderSchema) error {
+	if s.cache == nil || pv == nil {
+		return nil
+	}
+
+	b, err := json.Marshal(&tfjson.ProviderSchemas{
+		FormatVersion: "1.0",
+		Schemas: map[string]*tfjson.ProviderSchema{
+			addr.String(): jsonSchema,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.cache.Write(ProviderSchemaCacheKey(addr, pv), b)
+}
+
+// UseCachedSchemas checks whether schemas of all the given provider
+// versions were loaded from the persistent cache and if so, marks them
+// as recently used, so they're kept in the cache.
+func (s *ProviderSchemaStore) UseCachedSchemas(pvm map[tfaddr.Provider]*version.Version) (bool, error) {
+	if s.cache == nil || len(pvm) == 0 {
+		return false, nil
+	}
+
+	txn := s.db.Txn(false)
+	for pAddr, pv := range pvm {
+		if pv == nil {
+			return false, nil
+		}
+		obj, err := txn.First(s.tableName, "id", pAddr, CachedSchemaSource{}, pv)
+		if err != nil {
+			return false, err
+		}
+		if obj == nil {
+			return false, nil
+		}
+	}
+
+	for pAddr, pv := range pvm {
+		err := s.cache.Touch(ProviderSchemaCacheKey(pAddr, pv))
+		if err != nil {
+			s.logger.Printf("PSS: failed to mark cached schema as used (%s, %s): %s", pAddr, pv, err)
+		}
+	}
+
+	return true, nil
+}
+
+// LoadCachedSchemas loads all schemas from the persistent cache.
+// Entries which cannot be decoded are skipped and reported as errors.
+func (s *ProviderSchemaStore) LoadCachedSchemas() error {
+	if s.cache == nil {
+		return nil
+	}
+
+	keys, err := s.cache.Keys(providerSchemasCachePrefix)
+	if err != nil {
+		return err
+	}
+
+	var errs *multierror.Error
+	for _, key := range keys {
+		err := s.loadCachedSchema(key)
+		if err != nil {
+			errs = multierror.Append(errs, fmt.Errorf("%s: %w", key, err))
+		}
+	}
+
+	return errs.ErrorOrNil()
+}
+
+func (s *ProviderSchemaStore) loadCachedSchema(key string) error {
+	rel := strings.TrimPrefix(key, providerSchemasCachePrefix+"/")
+	rawAddr, fileName := path.Split(rel)
+	rawVersion, ok := strings.CutSuffix(fileName, ".json")
+	if !ok {
+		return errors.New("unexpected file in cache")
+	}
+
+	pAddr, err := tfaddr.ParseProviderSource(strings.TrimSuffix(rawAddr, "/"))
+	if err != nil {
+		return err
+	}
+	pv, err := version.NewVersion(rawVersion)
+	if err != nil {
+		return err
+	}
+
+	b, err := s.cache.Read(key)
+	if err != nil {
+		return err
+	}
+
+	jsonSchemas := tfjson.ProviderSchemas{}
+	err = json.Unmarshal(b, &jsonSchemas)
+	if err != nil {
+		return err
+	}
+
+	jsonSchema, ok := jsonSchemas.Schemas[pAddr.String()]
+	if !ok && len(jsonSchemas.Schemas) == 1 {
+		for _, js := range jsonSchemas.Schemas {
+			jsonSchema = js
+		}
+	} else if !ok {
+		return fmt.Errorf("no schema found for %q", pAddr)
+	}
+
+	pSchema := tfschema.ProviderSchemaFromJson(jsonSchema, pAddr)
+	pSchema.SetProviderVersion(pAddr, pv)
+
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	src := CachedSchemaSource{}
+	obj, err := txn.First(s.tableName, "id", pAddr, src, pv)
+	if err != nil {
+		return err
+	}
+	if obj != nil {
+		return nil
+	}
+
+	err = txn.Insert(s.tableName, &ProviderSchema{
+		Address: pAddr,
+		Version: pv,
+		Source:  src,
+		Schema:  pSchema,
+	})
+	if err != nil {
+		return err
+	}
+
+	txn.Commit()
+	return nil
+}
@@ -45,6 +45,42 @@ func TestStateStore_AddPreloadedSchema_duplicate(t *testing.T) {
 	}
 }
 
+func TestStateStore_AddMirroredSchema_duplicate(t *testing.T) {
+	s, err := NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	addr := tfaddr.Provider{
+		Hostname:  tfaddr.DefaultProviderRegistryHost,
+		Namespace: "corp",
+		Type:      "internal",
+	}
+	pv := testVersion(t, "1.0.0")
+	schema := &tfschema.ProviderSchema{}
+
+	err = s.ProviderSchemas.AddMirroredSchema(addr, pv, "https://mirror.example.com/", schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = s.ProviderSchemas.AddMirroredSchema(addr, pv, "https://mirror.example.com/", schema)
+	aeErr := &AlreadyExistsError{}
+	if !errors.As(err, &aeErr) {
+		t.Fatalf("expected duplicate insertion to fail, given: %v", err)
+	}
+
+	missing, err := s.ProviderSchemas.MissingSchemas(map[tfaddr.Provider]version.Constraints{
+		addr: version.MustConstraints(version.NewConstraint("~> 1.0")),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(missing) != 0 {
+		t.Fatalf("expected mirrored schema to be found, missing: %v", missing)
+	}
+}
+
 func TestStateStore_AddLocalSchema_duplicate(t *testing.T) {
 	s, err := NewStateStore()
 	if err != nil {
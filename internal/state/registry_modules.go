@@ -46,9 +46,27 @@ func (s *RegistryModuleStore) Exists(sourceAddr tfaddr.Module, constraint versio
 	return false, nil
 }
 
+// Cache stores the data of the given module version and writes it
+// through to the persistent cache, if one is set.
 func (s *RegistryModuleStore) Cache(sourceAddr tfaddr.Module, modVer *version.Version,
 	inputs []registry.Input, outputs []registry.Output) error {
 
+	err := s.insertModule(sourceAddr, modVer, inputs, outputs)
+	if err != nil {
+		return err
+	}
+
+	err = s.persistModule(sourceAddr, modVer, inputs, outputs)
+	if err != nil {
+		s.logger.Printf("RMS: failed to persist module data (%s, %s): %s", sourceAddr, modVer, err)
+	}
+
+	return nil
+}
+
+func (s *RegistryModuleStore) insertModule(sourceAddr tfaddr.Module, modVer *version.Version,
+	inputs []registry.Input, outputs []registry.Output) error {
+
 	txn := s.db.Txn(true)
 	defer txn.Abort()
 
@@ -134,3 +152,50 @@ func (s *RegistryModuleStore) RegistryModuleMeta(addr tfaddr.Module, cons versio
 		Source: addr.String(),
 	}
 }
+
+// RegistryModuleSearch represents the modules found
+// in the registry when searching for Query
+type RegistryModuleSearch struct {
+	Query   string
+	Results []RegistryModuleSearchResult
+}
+
+type RegistryModuleSearchResult struct {
+	Source        string
+	Description   string
+	LatestVersion *version.Version
+}
+
+// CacheSearchResults stores the modules found for the given query,
+// replacing any results previously cached for it.
+func (s *RegistryModuleStore) CacheSearchResults(query string, results []RegistryModuleSearchResult) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	err := txn.Insert(s.searchTableName, &RegistryModuleSearch{
+		Query:   query,
+		Results: results,
+	})
+	if err != nil {
+		return err
+	}
+
+	txn.Commit()
+	return nil
+}
+
+func (s *RegistryModuleStore) SearchResults(query string) ([]RegistryModuleSearchResult, error) {
+	txn := s.db.Txn(false)
+
+	obj, err := txn.First(s.searchTableName, "id", query)
+	if err != nil {
+		return nil, err
+	}
+	if obj == nil {
+		return nil, &RecordNotFoundError{
+			Source: query,
+		}
+	}
+
+	return obj.(*RegistryModuleSearch).Results, nil
+}
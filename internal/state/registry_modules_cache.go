@@ -0,0 +1,197 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/opentofu/opentofu-schema/registry"
+	tfaddr "github.com/opentofu/registry-address"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+// Registry module data is cached per module source address,
+// which may include a subdirectory, and version:
+//
+//	registry-modules/<escaped source address>/<version>.json
+const registryModulesCachePrefix = "registry-modules"
+
+type cachedRegistryModule struct {
+	Inputs  []cachedModuleInput  `json:"inputs"`
+	Outputs []cachedModuleOutput `json:"outputs"`
+}
+
+type cachedModuleInput struct {
+	Name        string             `json:"name"`
+	Type        cty.Type           `json:"type"`
+	Description lang.MarkupContent `json:"description"`
+	Default     *cachedModuleValue `json:"default,omitempty"`
+	Required    bool               `json:"required"`
+}
+
+type cachedModuleValue struct {
+	Type  cty.Type        `json:"type"`
+	Value json.RawMessage `json:"value"`
+}
+
+type cachedModuleOutput struct {
+	Name        string             `json:"name"`
+	Description lang.MarkupContent `json:"description"`
+}
+
+func registryModuleCacheKey(sourceAddr tfaddr.Module, modVer *version.Version) string {
+	return path.Join(registryModulesCachePrefix,
+		url.PathEscape(sourceAddr.String()), modVer.String()+".json")
+}
+
+// persistModule writes the module data through to the persistent cache
+func (s *RegistryModuleStore) persistModule(sourceAddr tfaddr.Module, modVer *version.Version,
+	inputs []registry.Input, outputs []registry.Output) error {
+
+	if s.cache == nil || modVer == nil {
+		return nil
+	}
+
+	mod := cachedRegistryModule{
+		Inputs:  make([]cachedModuleInput, len(inputs)),
+		Outputs: make([]cachedModuleOutput, len(outputs)),
+	}
+	for i, input := range inputs {
+		mod.Inputs[i] = cachedModuleInput{
+			Name:        input.Name,
+			Type:        input.Type,
+			Description: input.Description,
+			Required:    input.Required,
+		}
+		if input.Type == cty.NilType {
+			mod.Inputs[i].Type = cty.DynamicPseudoType
+		}
+		if input.Default != cty.NilVal && input.Default.IsWhollyKnown() {
+			rawValue, err := ctyjson.Marshal(input.Default, input.Default.Type())
+			if err != nil {
+				return fmt.Errorf("%s: %w", input.Name, err)
+			}
+			mod.Inputs[i].Default = &cachedModuleValue{
+				Type:  input.Default.Type(),
+				Value: rawValue,
+			}
+		}
+	}
+	for i, output := range outputs {
+		mod.Outputs[i] = cachedModuleOutput{
+			Name:        output.Name,
+			Description: output.Description,
+		}
+	}
+
+	b, err := json.Marshal(mod)
+	if err != nil {
+		return err
+	}
+
+	return s.cache.Write(registryModuleCacheKey(sourceAddr, modVer), b)
+}
+
+// LoadCachedModules loads data of all modules from the persistent cache.
+// Entries which cannot be decoded are skipped and reported as errors.
+func (s *RegistryModuleStore) LoadCachedModules() error {
+	if s.cache == nil {
+		return nil
+	}
+
+	keys, err := s.cache.Keys(registryModulesCachePrefix)
+	if err != nil {
+		return err
+	}
+
+	var errs *multierror.Error
+	for _, key := range keys {
+		err := s.loadCachedModule(key)
+		if err != nil {
+			errs = multierror.Append(errs, fmt.Errorf("%s: %w", key, err))
+		}
+	}
+
+	return errs.ErrorOrNil()
+}
+
+func (s *RegistryModuleStore) loadCachedModule(key string) error {
+	rel := strings.TrimPrefix(key, registryModulesCachePrefix+"/")
+	escapedAddr, fileName, ok := strings.Cut(rel, "/")
+	if !ok {
+		return errors.New("unexpected file in cache")
+	}
+	rawVersion, ok := strings.CutSuffix(fileName, ".json")
+	if !ok {
+		return errors.New("unexpected file in cache")
+	}
+
+	rawAddr, err := url.PathUnescape(escapedAddr)
+	if err != nil {
+		return err
+	}
+	sourceAddr, err := tfaddr.ParseModuleSource(rawAddr)
+	if err != nil {
+		return err
+	}
+	modVer, err := version.NewVersion(rawVersion)
+	if err != nil {
+		return err
+	}
+
+	b, err := s.cache.Read(key)
+	if err != nil {
+		return err
+	}
+
+	var mod cachedRegistryModule
+	err = json.Unmarshal(b, &mod)
+	if err != nil {
+		return err
+	}
+
+	inputs := make([]registry.Input, len(mod.Inputs))
+	for i, input := range mod.Inputs {
+		inputs[i] = registry.Input{
+			Name:        input.Name,
+			Type:        input.Type,
+			Description: input.Description,
+			Required:    input.Required,
+		}
+		if input.Default != nil {
+			val, err := ctyjson.Unmarshal(input.Default.Value, input.Default.Type)
+			if err != nil {
+				return fmt.Errorf("%s: %w", input.Name, err)
+			}
+			inputs[i].Default = val
+		}
+	}
+	outputs := make([]registry.Output, len(mod.Outputs))
+	for i, output := range mod.Outputs {
+		outputs[i] = registry.Output{
+			Name:        output.Name,
+			Description: output.Description,
+		}
+	}
+
+	err = s.insertModule(sourceAddr, modVer, inputs, outputs)
+	if err != nil {
+		existsError := &AlreadyExistsError{}
+		if errors.As(err, &existsError) {
+			return nil
+		}
+		return err
+	}
+
+	return nil
+}
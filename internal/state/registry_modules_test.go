@@ -13,6 +13,7 @@ import (
 	"github.com/hashicorp/hcl-lang/lang"
 	"github.com/opentofu/opentofu-schema/registry"
 	tfaddr "github.com/opentofu/registry-address"
+	"github.com/opentofu/tofu-ls/internal/cache"
 	"github.com/zclconf/go-cty-debug/ctydebug"
 	"github.com/zclconf/go-cty/cty"
 )
@@ -121,6 +122,79 @@ func TestModule_DeclaredModuleMeta(t *testing.T) {
 	}
 }
 
+func TestStateStore_cache_persistent(t *testing.T) {
+	diskCache := cache.New(t.TempDir(), 0)
+
+	source, err := tfaddr.ParseModuleSource("terraform-aws-modules/vpc/aws//modules/vpc-endpoints")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v := version.Must(version.NewVersion("5.1.0"))
+	inputs := []registry.Input{
+		{
+			Name:        "create",
+			Type:        cty.Bool,
+			Description: lang.Markdown("Whether to create endpoints"),
+			Default:     cty.True,
+		},
+		{
+			Name:        "endpoints",
+			Type:        cty.Map(cty.List(cty.String)),
+			Description: lang.PlainText("Endpoints to create"),
+			Default:     cty.MapValEmpty(cty.List(cty.String)),
+		},
+		{
+			Name:        "vpc_id",
+			Type:        cty.DynamicPseudoType,
+			Description: lang.Markdown("ID of the VPC"),
+			Required:    true,
+		},
+	}
+	outputs := []registry.Output{
+		{
+			Name:        "endpoints",
+			Description: lang.Markdown("Created endpoints"),
+		},
+	}
+
+	ss, err := NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ss.SetCache(diskCache)
+	err = ss.RegistryModules.Cache(source, v, inputs, outputs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a new session loads the data from the persistent cache
+	ss, err = NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ss.SetCache(diskCache)
+	err = ss.LoadCache()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cons := version.MustConstraints(version.NewConstraint("~> 5.0"))
+	meta, err := ss.RegistryModules.RegistryModuleMeta(source, cons)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedMeta := &registry.ModuleData{
+		Version: v,
+		Inputs:  inputs,
+		Outputs: outputs,
+	}
+	if diff := cmp.Diff(expectedMeta, meta, ctydebug.CmpOptions); diff != "" {
+		t.Fatalf("mismatch cached metadata: %s", diff)
+	}
+}
+
 func TestStateStore_cache_error(t *testing.T) {
 	s, err := NewStateStore()
 	if err != nil {
@@ -157,3 +231,48 @@ func TestStateStore_cache_error(t *testing.T) {
 		t.Fatal("should exist")
 	}
 }
+
+func TestStateStore_cache_searchResults(t *testing.T) {
+	s, err := NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = s.RegistryModules.SearchResults("vpc")
+	if !IsRecordNotFound(err) {
+		t.Fatalf("expected record not found error, given: %#v", err)
+	}
+
+	results := []RegistryModuleSearchResult{
+		{
+			Source:        "terraform-aws-modules/vpc/aws",
+			Description:   "Terraform module to create AWS VPC resources",
+			LatestVersion: version.Must(version.NewVersion("5.21.0")),
+		},
+	}
+	err = s.RegistryModules.CacheSearchResults("vpc", results)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cached, err := s.RegistryModules.SearchResults("vpc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(results, cached); diff != "" {
+		t.Fatalf("unexpected search results: %s", diff)
+	}
+
+	// results of a repeated search replace the cached ones
+	err = s.RegistryModules.CacheSearchResults("vpc", []RegistryModuleSearchResult{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cached, err = s.RegistryModules.SearchResults("vpc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cached) != 0 {
+		t.Fatalf("expected no search results, given: %#v", cached)
+	}
+}
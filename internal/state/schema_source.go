@@ -36,3 +36,30 @@ func (LocalSchemaSource) isSchemaSrcImpl() schemaSrcSigil {
 func (lss LocalSchemaSource) String() string {
 	return fmt.Sprintf("local(%s)", lss.ModulePath)
 }
+
+// MirrorSchemaSource represents schemas downloaded from a mirror
+// for modules which weren't initialized
+type MirrorSchemaSource struct {
+	MirrorURL string
+}
+
+func (MirrorSchemaSource) isSchemaSrcImpl() schemaSrcSigil {
+	return schemaSrcSigil{}
+}
+
+func (mss MirrorSchemaSource) String() string {
+	return fmt.Sprintf("mirror(%s)", mss.MirrorURL)
+}
+
+// CachedSchemaSource represents schemas loaded from the persistent cache,
+// which were obtained in an earlier session
+type CachedSchemaSource struct {
+}
+
+func (CachedSchemaSource) isSchemaSrcImpl() schemaSrcSigil {
+	return schemaSrcSigil{}
+}
+
+func (CachedSchemaSource) String() string {
+	return "cached"
+}
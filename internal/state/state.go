@@ -12,6 +12,8 @@ import (
 	"time"
 
 	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/go-multierror"
+	"github.com/opentofu/tofu-ls/internal/cache"
 )
 
 const (
@@ -22,6 +24,7 @@ const (
 	providerIdsTableName    = "provider_ids"
 	walkerPathsTableName    = "walker_paths"
 	registryModuleTableName = "registry_module"
+	moduleSearchTableName   = "module_search"
 
 	tracerName = "github.com/opentofu/tofu-ls/internal/state"
 )
@@ -164,6 +167,16 @@ var dbSchema = &memdb.DBSchema{
 				},
 			},
 		},
+		moduleSearchTableName: {
+			Name: moduleSearchTableName,
+			Indexes: map[string]*memdb.IndexSchema{
+				"id": {
+					Name:    "id",
+					Unique:  true,
+					Indexer: &memdb.StringFieldIndex{Field: "Query"},
+				},
+			},
+		},
 		providerIdsTableName: {
 			Name: providerIdsTableName,
 			Indexes: map[string]*memdb.IndexSchema{
@@ -219,11 +232,18 @@ type ProviderSchemaStore struct {
 	db        *memdb.MemDB
 	tableName string
 	logger    *log.Logger
+
+	// cache persists schemas across sessions, if set
+	cache *cache.Cache
 }
 type RegistryModuleStore struct {
-	db        *memdb.MemDB
-	tableName string
-	logger    *log.Logger
+	db              *memdb.MemDB
+	tableName       string
+	searchTableName string
+	logger          *log.Logger
+
+	// cache persists module data across sessions, if set
+	cache *cache.Cache
 }
 
 func NewStateStore() (*StateStore, error) {
@@ -259,9 +279,10 @@ func NewStateStore() (*StateStore, error) {
 			logger:    defaultLogger,
 		},
 		RegistryModules: &RegistryModuleStore{
-			db:        db,
-			tableName: registryModuleTableName,
-			logger:    defaultLogger,
+			db:              db,
+			tableName:       registryModuleTableName,
+			searchTableName: moduleSearchTableName,
+			logger:          defaultLogger,
 		},
 		WalkerPaths: &WalkerPathStore{
 			db:              db,
@@ -282,4 +303,29 @@ func (s *StateStore) SetLogger(logger *log.Logger) {
 	s.RegistryModules.logger = logger
 }
 
+// SetCache makes the stores of provider schemas and registry modules
+// write through to the given persistent cache.
+// See [StateStore.LoadCache] for loading the cached data.
+func (s *StateStore) SetCache(c *cache.Cache) {
+	s.ProviderSchemas.cache = c
+	s.RegistryModules.cache = c
+}
+
+// LoadCache loads provider schemas and registry modules
+// from the persistent cache, as set via [StateStore.SetCache].
+func (s *StateStore) LoadCache() error {
+	var errs *multierror.Error
+
+	err := s.ProviderSchemas.LoadCachedSchemas()
+	if err != nil {
+		errs = multierror.Append(errs, err)
+	}
+	err = s.RegistryModules.LoadCachedModules()
+	if err != nil {
+		errs = multierror.Append(errs, err)
+	}
+
+	return errs.ErrorOrNil()
+}
+
 var defaultLogger = log.New(io.Discard, "", 0)
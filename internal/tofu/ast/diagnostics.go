@@ -17,9 +17,16 @@ const (
 	SchemaValidationSource
 	ReferenceValidationSource
 	TofuValidateSource
+	TofuPlanSource
+	ProviderLockValidationSource
 )
 
 func (d DiagnosticSource) String() string {
+	if d == TofuPlanSource {
+		// Plan diagnostics can repeat those of validation,
+		// so they are told apart in the editor
+		return "OpenTofu plan"
+	}
 	return "OpenTofu"
 }
 
@@ -160,6 +160,31 @@ func (mm *ModuleManifest) ContainsLocalModule(path string) bool {
 	return false
 }
 
+// ModuleCallDir returns the absolute path to the directory where
+// the module called name from the module at callerPath is installed.
+//
+// Module calls are identified by keys reflecting their position within
+// the module tree, e.g. "app.vpc" for module "vpc" called from module
+// "app", so this also works for calls from nested modules.
+func (mm *ModuleManifest) ModuleCallDir(callerPath, name string) (string, bool) {
+	for _, caller := range mm.Records {
+		if !pathcmp.PathEquals(filepath.Join(mm.RootDir(), caller.Dir), callerPath) {
+			continue
+		}
+
+		key := name
+		if !caller.IsRoot() {
+			key = caller.Key + "." + name
+		}
+		for _, mod := range mm.Records {
+			if mod.Key == key {
+				return filepath.Join(mm.RootDir(), mod.Dir), true
+			}
+		}
+	}
+	return "", false
+}
+
 func ParseModuleManifestFromFile(path string) (*ModuleManifest, error) {
 	b, err := os.ReadFile(path)
 	if err != nil {
@@ -70,6 +70,42 @@ func TestParseModuleManifestFromFile(t *testing.T) {
 	}
 }
 
+func TestModuleManifest_ModuleCallDir(t *testing.T) {
+	rootDir := t.TempDir()
+	mm := NewModuleManifest(rootDir, []ModuleRecord{
+		{Dir: "."},
+		{Key: "app", Dir: filepath.Join("modules", "app")},
+		{Key: "app.vpc", Dir: filepath.Join(".terraform", "modules", "app.vpc")},
+		{Key: "vpc", Dir: filepath.Join(".terraform", "modules", "vpc")},
+		{Key: "vpc.subnets", Dir: filepath.Join(".terraform", "modules", "vpc", "modules", "subnets")},
+	})
+
+	testCases := []struct {
+		callerPath  string
+		name        string
+		expectedDir string
+		expectedOk  bool
+	}{
+		{rootDir, "vpc", filepath.Join(rootDir, ".terraform", "modules", "vpc"), true},
+		{filepath.Join(rootDir, "modules", "app"), "vpc", filepath.Join(rootDir, ".terraform", "modules", "app.vpc"), true},
+		{filepath.Join(rootDir, ".terraform", "modules", "vpc"), "subnets", filepath.Join(rootDir, ".terraform", "modules", "vpc", "modules", "subnets"), true},
+		{filepath.Join(rootDir, "modules", "app"), "unknown", "", false},
+		{filepath.Join(rootDir, "modules", "other"), "vpc", "", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.callerPath+"/"+tc.name, func(t *testing.T) {
+			dir, ok := mm.ModuleCallDir(tc.callerPath, tc.name)
+			if ok != tc.expectedOk {
+				t.Fatalf("expected ok: %t, given: %t", tc.expectedOk, ok)
+			}
+			if dir != tc.expectedDir {
+				t.Fatalf("expected dir: %q, given: %q", tc.expectedDir, dir)
+			}
+		})
+	}
+}
+
 const testManifestContent = `{
     "Modules": [
         {
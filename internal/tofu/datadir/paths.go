@@ -62,6 +62,24 @@ func PathGlobPatternsForWatching() []WatchPattern {
 	return patterns
 }
 
+// InstalledModuleRoot returns the path of the root module
+// whose data directory the module at modPath is installed in.
+func InstalledModuleRoot(modPath string) (string, bool) {
+	sep := string(filepath.Separator)
+	idx := strings.Index(modPath+sep, sep+DataDirName+sep)
+	if idx < 0 {
+		return "", false
+	}
+	return modPath[:idx], true
+}
+
+// IsInstalledModulePath reports whether modPath is within a data directory,
+// i.e. whether it is a copy of a module installed by init.
+func IsInstalledModulePath(modPath string) bool {
+	_, ok := InstalledModuleRoot(modPath)
+	return ok
+}
+
 func ModuleUriFromDataDir(rawUri string) (string, bool) {
 	suffix := "/" + DataDirName
 	if strings.HasSuffix(rawUri, suffix) {
@@ -0,0 +1,59 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package datadir
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+)
+
+func TestInstalledModuleRoot(t *testing.T) {
+	testCases := []struct {
+		modPath      string
+		expectedRoot string
+		expectedOk   bool
+	}{
+		{
+			filepath.Join("root", "modules", "app"),
+			"",
+			false,
+		},
+		{
+			filepath.Join("root", ".terraform-modules"),
+			"",
+			false,
+		},
+		{
+			filepath.Join("root", DataDirName),
+			"root",
+			true,
+		},
+		{
+			filepath.Join("root", DataDirName, "modules", "vpc"),
+			"root",
+			true,
+		},
+		{
+			filepath.Join("root", "env", DataDirName, "modules", "vpc", "nested"),
+			filepath.Join("root", "env"),
+			true,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%d-%s", i, tc.modPath), func(t *testing.T) {
+			root, ok := InstalledModuleRoot(tc.modPath)
+			if ok != tc.expectedOk {
+				t.Fatalf("expected ok: %t, given: %t", tc.expectedOk, ok)
+			}
+			if root != tc.expectedRoot {
+				t.Fatalf("expected root: %q, given: %q", tc.expectedRoot, root)
+			}
+			if IsInstalledModulePath(tc.modPath) != tc.expectedOk {
+				t.Fatalf("expected installed: %t", tc.expectedOk)
+			}
+		})
+	}
+}
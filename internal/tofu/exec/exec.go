@@ -8,6 +8,7 @@ package exec
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"log"
 	"os/exec"
@@ -218,3 +219,78 @@ func (e *Executor) ProviderSchemas(ctx context.Context) (*tfjson.ProviderSchemas
 
 	return ps, e.contextfulError(ctx, "ProviderSchemas", err)
 }
+
+func (e *Executor) Show(ctx context.Context) (*tfjson.State, error) {
+	ctx, cancel := e.withTimeout(ctx)
+	defer cancel()
+	err := e.setLogPath("Show")
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "tofu-exec:Show")
+	defer span.End()
+
+	state, err := e.tf.Show(ctx)
+	e.setSpanStatus(span, err)
+
+	return state, e.contextfulError(ctx, "Show", err)
+}
+
+func (e *Executor) ShowPlanFile(ctx context.Context, planPath string) (*tfjson.Plan, error) {
+	ctx, cancel := e.withTimeout(ctx)
+	defer cancel()
+	err := e.setLogPath("ShowPlanFile")
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "tofu-exec:ShowPlanFile")
+	defer span.End()
+
+	plan, err := e.tf.ShowPlanFile(ctx, planPath)
+	e.setSpanStatus(span, err)
+
+	return plan, e.contextfulError(ctx, "ShowPlanFile", err)
+}
+
+// Plan runs tofu plan and returns diagnostics from its machine-readable
+// output, which are returned even if planning fails, and whether there
+// are any changes.
+func (e *Executor) Plan(ctx context.Context, opts ...tfexec.PlanOption) ([]tfjson.Diagnostic, bool, error) {
+	ctx, cancel := e.withTimeout(ctx)
+	defer cancel()
+	err := e.setLogPath("Plan")
+	if err != nil {
+		return []tfjson.Diagnostic{}, false, err
+	}
+
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "tofu-exec:Plan")
+	defer span.End()
+
+	buf := bytes.NewBuffer([]byte{})
+	hasChanges, err := e.tf.PlanJSON(ctx, buf, opts...)
+	e.setSpanStatus(span, err)
+
+	return planDiagnostics(buf.Bytes()), hasChanges, e.contextfulError(ctx, "Plan", err)
+}
+
+// planDiagnostics returns diagnostics from the lines of
+// the machine-readable UI output of tofu plan
+func planDiagnostics(output []byte) []tfjson.Diagnostic {
+	diags := make([]tfjson.Diagnostic, 0)
+
+	for _, line := range bytes.Split(output, []byte("\n")) {
+		var msg struct {
+			Type       string             `json:"type"`
+			Diagnostic *tfjson.Diagnostic `json:"diagnostic"`
+		}
+		err := json.Unmarshal(line, &msg)
+		if err != nil || msg.Type != "diagnostic" || msg.Diagnostic == nil {
+			continue
+		}
+		diags = append(diags, *msg.Diagnostic)
+	}
+
+	return diags
+}
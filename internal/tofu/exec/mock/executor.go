@@ -121,6 +121,50 @@ func (_m *Executor) Init(ctx context.Context, opts ...tfexec.InitOption) error {
 	return r0
 }
 
+// Plan provides a mock function with given fields: ctx, opts
+func (_m *Executor) Plan(ctx context.Context, opts ...tfexec.PlanOption) ([]tfjson.Diagnostic, bool, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Plan")
+	}
+
+	var r0 []tfjson.Diagnostic
+	var r1 bool
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, ...tfexec.PlanOption) ([]tfjson.Diagnostic, bool, error)); ok {
+		return rf(ctx, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, ...tfexec.PlanOption) []tfjson.Diagnostic); ok {
+		r0 = rf(ctx, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]tfjson.Diagnostic)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, ...tfexec.PlanOption) bool); ok {
+		r1 = rf(ctx, opts...)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, ...tfexec.PlanOption) error); ok {
+		r2 = rf(ctx, opts...)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // ProviderSchemas provides a mock function with given fields: ctx
 func (_m *Executor) ProviderSchemas(ctx context.Context) (*tfjson.ProviderSchemas, error) {
 	ret := _m.Called(ctx)
@@ -179,6 +223,66 @@ func (_m *Executor) SetTimeout(duration time.Duration) {
 	_m.Called(duration)
 }
 
+// Show provides a mock function with given fields: ctx
+func (_m *Executor) Show(ctx context.Context) (*tfjson.State, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Show")
+	}
+
+	var r0 *tfjson.State
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*tfjson.State, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *tfjson.State); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*tfjson.State)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ShowPlanFile provides a mock function with given fields: ctx, planPath
+func (_m *Executor) ShowPlanFile(ctx context.Context, planPath string) (*tfjson.Plan, error) {
+	ret := _m.Called(ctx, planPath)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ShowPlanFile")
+	}
+
+	var r0 *tfjson.Plan
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*tfjson.Plan, error)); ok {
+		return rf(ctx, planPath)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *tfjson.Plan); ok {
+		r0 = rf(ctx, planPath)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*tfjson.Plan)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, planPath)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Validate provides a mock function with given fields: ctx
 func (_m *Executor) Validate(ctx context.Context) ([]tfjson.Diagnostic, error) {
 	ret := _m.Called(ctx)
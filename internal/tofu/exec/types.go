@@ -34,4 +34,7 @@ type TofuExecutor interface {
 	Version(ctx context.Context) (*version.Version, map[string]*version.Version, error)
 	Validate(ctx context.Context) ([]tfjson.Diagnostic, error)
 	ProviderSchemas(ctx context.Context) (*tfjson.ProviderSchemas, error)
+	Plan(ctx context.Context, opts ...tfexec.PlanOption) ([]tfjson.Diagnostic, bool, error)
+	Show(ctx context.Context) (*tfjson.State, error)
+	ShowPlanFile(ctx context.Context, planPath string) (*tfjson.Plan, error)
 }
@@ -26,11 +26,21 @@ func _() {
 	_ = x[OpTypeSchemaVarsValidation-15]
 	_ = x[OpTypeReferenceValidation-16]
 	_ = x[OpTypeTofuValidate-17]
+	_ = x[OpTypeParseTestConfiguration-18]
+	_ = x[OpTypeDecodeTestReferenceTargets-19]
+	_ = x[OpTypeDecodeTestReferenceOrigins-20]
+	_ = x[OpTypeSchemaTestValidation-21]
+	_ = x[OpTypeParseMockConfiguration-22]
+	_ = x[OpTypeSchemaMockValidation-23]
+	_ = x[OpTypeObtainPlanState-24]
+	_ = x[OpTypeTofuPlan-25]
+	_ = x[OpTypeProviderLockValidation-26]
+	_ = x[OpTypeFetchProviderSchemas-27]
 }
 
-const _OpType_name = "OpTypeUnknownOpTypeGetTofuVersionOpTypeGetInstalledTofuVersionOpTypeObtainSchemaOpTypeParseModuleConfigurationOpTypeParseVariablesOpTypeParseModuleManifestOpTypeLoadModuleMetadataOpTypeDecodeReferenceTargetsOpTypeDecodeReferenceOriginsOpTypeDecodeVarsReferencesOpTypeGetModuleDataFromRegistryOpTypeParseProviderVersionsOpTypePreloadEmbeddedSchemaOpTypeSchemaModuleValidationOpTypeSchemaVarsValidationOpTypeReferenceValidationOpTypeTofuValidate"
+const _OpType_name = "OpTypeUnknownOpTypeGetTofuVersionOpTypeGetInstalledTofuVersionOpTypeObtainSchemaOpTypeParseModuleConfigurationOpTypeParseVariablesOpTypeParseModuleManifestOpTypeLoadModuleMetadataOpTypeDecodeReferenceTargetsOpTypeDecodeReferenceOriginsOpTypeDecodeVarsReferencesOpTypeGetModuleDataFromRegistryOpTypeParseProviderVersionsOpTypePreloadEmbeddedSchemaOpTypeSchemaModuleValidationOpTypeSchemaVarsValidationOpTypeReferenceValidationOpTypeTofuValidateOpTypeParseTestConfigurationOpTypeDecodeTestReferenceTargetsOpTypeDecodeTestReferenceOriginsOpTypeSchemaTestValidationOpTypeParseMockConfigurationOpTypeSchemaMockValidationOpTypeObtainPlanStateOpTypeTofuPlanOpTypeProviderLockValidationOpTypeFetchProviderSchemas"
 
-var _OpType_index = [...]uint16{0, 13, 33, 62, 80, 110, 130, 155, 179, 207, 235, 261, 292, 319, 346, 374, 400, 425, 443}
+var _OpType_index = [...]uint16{0, 13, 33, 62, 80, 110, 130, 155, 179, 207, 235, 261, 292, 319, 346, 374, 400, 425, 443, 471, 503, 535, 561, 589, 615, 636, 650, 678, 704}
 
 func (i OpType) String() string {
 	if i >= OpType(len(_OpType_index)-1) {
@@ -37,4 +37,14 @@ const (
 	OpTypeSchemaVarsValidation
 	OpTypeReferenceValidation
 	OpTypeTofuValidate
+	OpTypeParseTestConfiguration
+	OpTypeDecodeTestReferenceTargets
+	OpTypeDecodeTestReferenceOrigins
+	OpTypeSchemaTestValidation
+	OpTypeParseMockConfiguration
+	OpTypeSchemaMockValidation
+	OpTypeObtainPlanState
+	OpTypeTofuPlan
+	OpTypeProviderLockValidation
+	OpTypeFetchProviderSchemas
 )
@@ -39,12 +39,23 @@ func main() {
 				Version: version,
 			}, nil
 		},
+		"check": func() (cli.Command, error) {
+			return &cmd.CheckCommand{
+				Ui:      ui,
+				Version: version,
+			}, nil
+		},
 		"version": func() (cli.Command, error) {
 			return &cmd.VersionCommand{
 				Ui:      ui,
 				Version: version,
 			}, nil
 		},
+		"cache clean": func() (cli.Command, error) {
+			return &cmd.CacheCleanCommand{
+				Ui: ui,
+			}, nil
+		},
 		"providers bundled": func() (cli.Command, error) {
 			return &cmd.ProvidersBundledCommand{
 				Ui: ui,